@@ -0,0 +1,22 @@
+package purfecterm
+
+import "testing"
+
+// The cursor helpers read the current line and the cell after the last
+// written character.
+func TestAtCursorHelpers(t *testing.T) {
+	b := NewBuffer(20, 3, 100)
+	NewParser(b).Parse([]byte("\x1b[1mhello"))
+
+	if got := b.CurrentLineText(); got != "hello" {
+		t.Fatalf("CurrentLineText = %q, want %q", got, "hello")
+	}
+	if got := b.CharAtCursor(); got != ' ' {
+		t.Fatalf("CharAtCursor = %q, want blank after the text", got)
+	}
+
+	NewParser(b).Parse([]byte("\x1b[1;2H"))
+	if got := b.CellAtCursor(); got.Char != 'e' || !got.Bold {
+		t.Fatalf("CellAtCursor = %+v, want bold 'e'", got)
+	}
+}
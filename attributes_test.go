@@ -0,0 +1,49 @@
+package purfecterm
+
+import "testing"
+
+// Attributes set through SGR and the graphics setters read back through
+// GetCurrentAttributes, and SetCurrentAttributes restores them
+func TestGetCurrentAttributes(t *testing.T) {
+	b := NewBuffer(20, 3, 0)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b[1;3;4:3;5;7;9;53;12;38;5;202;48;2;1;2;3;58;5;4m"))
+	b.SetBGP(7)
+	b.SetXFlip(true)
+	b.SetFlexWidthMode(true)
+
+	want := Attributes{
+		Foreground:        PaletteColor(202),
+		Background:        TrueColor(1, 2, 3),
+		Bold:              true,
+		Italic:            true,
+		Underline:         true,
+		UnderlineStyle:    UnderlineCurly,
+		UnderlineColor:    PaletteColor(4),
+		HasUnderlineColor: true,
+		Reverse:           true,
+		Blink:             true,
+		Strikethrough:     true,
+		Overline:          true,
+		FlexWidth:         true,
+		BGP:               7,
+		XFlip:             true,
+		Font:              2,
+	}
+	got := b.GetCurrentAttributes()
+	if got != want {
+		t.Fatalf("GetCurrentAttributes =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// The pen is what new cells get
+	p.Parse([]byte("Z"))
+	if c := b.GetCell(0, 0); c.UnderlineStyle != want.UnderlineStyle || c.BGP != want.BGP || !c.XFlip || c.Font != 2 {
+		t.Errorf("cell %+v does not carry the pen", c)
+	}
+
+	p.Parse([]byte("\x1b[0m"))
+	b.SetCurrentAttributes(got)
+	if again := b.GetCurrentAttributes(); again != want {
+		t.Errorf("after SetCurrentAttributes =\n%+v\nwant\n%+v", again, want)
+	}
+}
@@ -0,0 +1,33 @@
+package purfecterm
+
+import "testing"
+
+// A half-transparent selection averages the selection color with the cell
+// background instead of replacing it.
+func TestSelectionBackgroundBlend(t *testing.T) {
+	s := DefaultColorScheme()
+	s.Selection = TrueColor(200, 100, 0)
+	bg := TrueColor(0, 50, 100)
+
+	if got := s.SelectionBackground(bg); got != s.Selection {
+		t.Errorf("opaque selection = %+v, want %+v", got, s.Selection)
+	}
+	s.SelectionAlpha = 0.5
+	if got, want := s.SelectionBackground(bg), TrueColor(100, 75, 50); got != want {
+		t.Errorf("blended selection = %+v, want %+v", got, want)
+	}
+}
+
+func TestBlockCursorColors(t *testing.T) {
+	s := DefaultColorScheme()
+	s.Cursor = TrueColor(255, 255, 255)
+	fg, bg := TrueColor(10, 10, 10), TrueColor(0, 0, 0)
+
+	if f, b := s.BlockCursorColors(fg, bg); f != bg || b != fg {
+		t.Errorf("opaque cursor should swap fg and bg, got %+v/%+v", f, b)
+	}
+	s.CursorAlpha = 0.5
+	if f, b := s.BlockCursorColors(fg, bg); f != fg || b != TrueColor(128, 128, 128) {
+		t.Errorf("translucent cursor = %+v/%+v, want fg kept and bg tinted", f, b)
+	}
+}
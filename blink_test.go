@@ -0,0 +1,62 @@
+package purfecterm
+
+import (
+	"math"
+	"testing"
+)
+
+// SGR 5 and SGR 6 are distinguished; SGR 25 clears both. Rapid blink runs
+// its animation phase at twice the slow rate.
+func TestSGRBlinkRates(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	NewParser(b).Parse([]byte("\x1b[5mS\x1b[6mR\x1b[25mN"))
+
+	slow, rapid, none := b.GetCell(0, 0), b.GetCell(1, 0), b.GetCell(2, 0)
+	if !slow.Blink || slow.BlinkRapid {
+		t.Fatalf("SGR 5 should be slow blink, got %+v", slow)
+	}
+	if !rapid.Blink || !rapid.BlinkRapid {
+		t.Fatalf("SGR 6 should be rapid blink, got %+v", rapid)
+	}
+	if none.Blink || none.BlinkRapid {
+		t.Fatal("SGR 25 should clear both blink rates")
+	}
+	if got := rapid.BlinkPhase(1.0); got != 2.0 {
+		t.Fatalf("rapid BlinkPhase(1.0) = %v, want 2.0", got)
+	}
+	if got := slow.BlinkPhase(1.0); got != 1.0 {
+		t.Fatalf("slow BlinkPhase(1.0) = %v, want 1.0", got)
+	}
+}
+
+// The phase step covers a full wave in CycleMs; the defaults keep the
+// original ~0.21 rad per 50ms tick, 3px bounce and even on/off split.
+func TestBlinkAnimationPhaseStep(t *testing.T) {
+	d := DefaultBlinkAnimation
+	if got := d.PhaseStep(50); math.Abs(got-0.21) > 0.001 {
+		t.Errorf("default step per 50ms = %v, want ~0.21", got)
+	}
+
+	slow := BlinkAnimation{CycleMs: 3000}
+	if got, want := slow.PhaseStep(50), 2*math.Pi/60; math.Abs(got-want) > 1e-12 {
+		t.Errorf("3000ms cycle step = %v, want %v", got, want)
+	}
+	if got := (BlinkAnimation{}).PhaseStep(50); got != d.PhaseStep(50) {
+		t.Errorf("zero CycleMs step = %v, want the default %v", got, d.PhaseStep(50))
+	}
+
+	if got := d.BounceOffset(math.Pi/2, 0); got != 3.0 {
+		t.Errorf("peak bounce = %v, want 3", got)
+	}
+	flat := BlinkAnimation{Amplitude: 0, PhaseShift: 0.5}
+	if got := flat.BounceOffset(1, 3); got != 0 {
+		t.Errorf("zero amplitude bounce = %v, want 0", got)
+	}
+
+	if !d.Visible(3.0) || d.Visible(3.2) {
+		t.Error("default duty cycle should show the first half of the phase")
+	}
+	if mostlyOn := (BlinkAnimation{DutyCycle: 0.75}); !mostlyOn.Visible(4.0) {
+		t.Error("75% duty cycle should still show text at 4.0 rad")
+	}
+}
@@ -0,0 +1,47 @@
+package purfecterm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// A bookmark follows its line as the scrollback cap evicts older lines, and
+// jumping to it brings the line back to the bottom row.
+func TestBookmarkTracksTrim(t *testing.T) {
+	b := NewBuffer(10, 5, 20)
+	p := NewParser(b)
+	lines := make([]string, 10)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line%d", i)
+	}
+	p.Parse([]byte(strings.Join(lines, "\r\n")))
+
+	b.AddBookmark("mark")
+	b.AddBookmark("other")
+	if got := b.Bookmarks(); len(got) != 2 || got[0] != (Bookmark{"mark", 9}) {
+		t.Fatalf("Bookmarks = %+v, want mark at row 9 first", got)
+	}
+
+	// 20 more lines make 25 in scrollback; the cap of 20 evicts 5
+	p.Parse([]byte(strings.Repeat("\r\nmore", 20)))
+	bm := b.Bookmarks()[0]
+	if bm.BufferY != 4 {
+		t.Fatalf("BufferY after trim = %d, want 4", bm.BufferY)
+	}
+	b.EnsureVisibleBufferY(bm.BufferY, PositionBottom)
+	if got := b.GetVisibleCell(4, 4).Char; got != '9' {
+		t.Errorf("bottom row shows %q, want line9", got)
+	}
+
+	b.RemoveBookmark("other")
+	if got := b.Bookmarks(); len(got) != 1 || got[0].Name != "mark" {
+		t.Errorf("after RemoveBookmark: %+v, want only mark", got)
+	}
+
+	// Once its line is evicted the bookmark stops at the oldest line
+	p.Parse([]byte(strings.Repeat("\r\nmore", 10)))
+	if got := b.Bookmarks()[0].BufferY; got != 0 {
+		t.Errorf("BufferY after its line was evicted = %d, want 0", got)
+	}
+}
@@ -0,0 +1,36 @@
+package purfecterm
+
+import "testing"
+
+// Renderers take the boundary line color from the scheme when it is set.
+func TestScrollbackBoundaryColor(t *testing.T) {
+	s := DefaultColorScheme()
+	if got := s.ScrollbackBoundaryColor(true); got != TrueColor(255, 200, 0) {
+		t.Errorf("default dark boundary = %+v, want yellow", got)
+	}
+	if got := s.ScrollbackBoundaryColor(false); got == TrueColor(255, 200, 0) {
+		t.Error("default light boundary should be darker than yellow")
+	}
+
+	s.ScrollbackBoundary = TrueColor(0, 120, 255)
+	for _, isDark := range []bool{true, false} {
+		if got := s.ScrollbackBoundaryColor(isDark); got != TrueColor(0, 120, 255) {
+			t.Errorf("isDark=%v: boundary = %+v, want the scheme color", isDark, got)
+		}
+	}
+	s.ScrollbackBoundary = StandardColor(1)
+	if got := s.ScrollbackBoundaryColor(true); got != s.DarkPalette[1] {
+		t.Errorf("palette boundary = %+v, want %+v", got, s.DarkPalette[1])
+	}
+}
+
+func TestShowScrollbackBoundary(t *testing.T) {
+	b := NewBuffer(10, 3, 10)
+	if !b.GetShowScrollbackBoundary() {
+		t.Fatal("boundary should be shown by default")
+	}
+	b.SetShowScrollbackBoundary(false)
+	if b.GetShowScrollbackBoundary() {
+		t.Fatal("SetShowScrollbackBoundary(false) did not hide the boundary")
+	}
+}
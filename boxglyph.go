@@ -0,0 +1,252 @@
+package purfecterm
+
+import "math"
+
+// BoxRect is a filled rectangle in pixels relative to the cell origin.
+// Alpha is the coverage to paint with the foreground color: 1 for solid
+// strokes and blocks, less for the shade characters.
+type BoxRect struct {
+	X, Y, W, H float64
+	Alpha      float64
+}
+
+// Box-drawing arm weights, packed two bits per direction in boxLineArms
+const (
+	boxArmNone   = 0
+	boxArmLight  = 1
+	boxArmHeavy  = 2
+	boxArmDouble = 3
+)
+
+// boxLineArms describes U+2500-U+257F as up<<6 | right<<4 | down<<2 | left
+// arm weights. Zero marks dashed, arc and diagonal characters, which are
+// left to the font.
+var boxLineArms = [128]uint8{
+	0x11, 0x22, 0x44, 0x88, 0x00, 0x00, 0x00, 0x00, // U+2500
+	0x00, 0x00, 0x00, 0x00, 0x14, 0x24, 0x18, 0x28, // U+2508
+	0x05, 0x06, 0x09, 0x0A, 0x50, 0x60, 0x90, 0xA0, // U+2510
+	0x41, 0x42, 0x81, 0x82, 0x54, 0x64, 0x94, 0x58, // U+2518
+	0x98, 0xA4, 0x68, 0xA8, 0x45, 0x46, 0x85, 0x49, // U+2520
+	0x89, 0x86, 0x4A, 0x8A, 0x15, 0x16, 0x25, 0x26, // U+2528
+	0x19, 0x1A, 0x29, 0x2A, 0x51, 0x52, 0x61, 0x62, // U+2530
+	0x91, 0x92, 0xA1, 0xA2, 0x55, 0x56, 0x65, 0x66, // U+2538
+	0x95, 0x59, 0x99, 0x96, 0xA5, 0x5A, 0x69, 0xA6, // U+2540
+	0x6A, 0x9A, 0xA9, 0xAA, 0x00, 0x00, 0x00, 0x00, // U+2548
+	0x33, 0xCC, 0x34, 0x1C, 0x3C, 0x07, 0x0D, 0x0F, // U+2550
+	0x70, 0xD0, 0xF0, 0x43, 0xC1, 0xC3, 0x74, 0xDC, // U+2558
+	0xFC, 0x47, 0xCD, 0xCF, 0x37, 0x1D, 0x3F, 0x73, // U+2560
+	0xD1, 0xF3, 0x77, 0xDD, 0xFF, 0x00, 0x00, 0x00, // U+2568
+	0x00, 0x00, 0x00, 0x00, 0x01, 0x40, 0x10, 0x04, // U+2570
+	0x02, 0x80, 0x20, 0x08, 0x21, 0x48, 0x12, 0x84, // U+2578
+}
+
+// boxSpan is a range along one axis of the cell
+type boxSpan struct{ lo, hi float64 }
+
+// boxBands returns the stroke bands for a line of the given weight centered
+// across size: one band for light and heavy, two for double.
+func boxBands(size, lt float64, weight uint8) []boxSpan {
+	switch weight {
+	case boxArmHeavy:
+		s := math.Floor((size - 2*lt) / 2)
+		return []boxSpan{{s, s + 2*lt}}
+	case boxArmDouble:
+		s := math.Floor((size - 3*lt) / 2)
+		return []boxSpan{{s, s + lt}, {s + 2*lt, s + 3*lt}}
+	default:
+		s := math.Floor((size - lt) / 2)
+		return []boxSpan{{s, s + lt}}
+	}
+}
+
+// boxArmReach returns where an arm meets the joint along its own axis: the
+// start of an arm toward the far edge (toFar) or the end of one toward the
+// origin. size is the cell size along that axis; p1 and p2 are the
+// perpendicular arm weights, opposite is the arm on the other side of the
+// joint. For one line of a double arm, side is the perpendicular weight on
+// that line's side (p1 or p2) and other the weight on the far side.
+func boxArmReach(size, lt float64, weight, p1, p2, opposite uint8, double bool, side, other uint8, toFar bool) float64 {
+	pick := func(bands []boxSpan, first bool) float64 {
+		if first {
+			if toFar {
+				return bands[0].lo
+			}
+			return bands[0].hi
+		}
+		last := bands[len(bands)-1]
+		if toFar {
+			return last.lo
+		}
+		return last.hi
+	}
+
+	if double {
+		switch {
+		case side == boxArmDouble:
+			// Inner line of a double corner or junction
+			return pick(boxBands(size, lt, boxArmDouble), !toFar)
+		case side != boxArmNone:
+			return pick(boxBands(size, lt, side), true)
+		case other != boxArmNone:
+			// Outer line: reach across to the far perpendicular stroke
+			return pick(boxBands(size, lt, other), toFar)
+		}
+		return pick(boxBands(size, lt, boxArmLight), true)
+	}
+
+	if p1 == boxArmNone && p2 == boxArmNone {
+		return pick(boxBands(size, lt, weight), true)
+	}
+	if opposite == boxArmNone && (p1 == boxArmDouble || p2 == boxArmDouble) {
+		// A single stroke ending on a double line stops at the near line
+		return pick(boxBands(size, lt, boxArmDouble), !toFar)
+	}
+	reach := math.Inf(1)
+	if !toFar {
+		reach = math.Inf(-1)
+	}
+	for _, p := range []uint8{p1, p2} {
+		if p == boxArmNone {
+			continue
+		}
+		for _, b := range boxBands(size, lt, p) {
+			if toFar {
+				reach = math.Min(reach, b.lo)
+			} else {
+				reach = math.Max(reach, b.hi)
+			}
+		}
+	}
+	return reach
+}
+
+// boxLineRects builds the strokes of a U+2500-U+257F line character
+func boxLineRects(arms uint8, w, h float64) []BoxRect {
+	up, right, down, left := arms>>6&3, arms>>4&3, arms>>2&3, arms&3
+	lt := math.Max(1, math.Round(math.Min(w, h)/10))
+
+	var rects []BoxRect
+	// Horizontal arms: bands along y, reach along x (perpendiculars up/down)
+	horiz := func(weight, opposite uint8, toFar bool) {
+		if weight == boxArmNone {
+			return
+		}
+		bands := boxBands(h, lt, weight)
+		for i, band := range bands {
+			side, other := up, down
+			if i == 1 {
+				side, other = down, up
+			}
+			reach := boxArmReach(w, lt, weight, up, down, opposite, weight == boxArmDouble, side, other, toFar)
+			x0, x1 := reach, w
+			if !toFar {
+				x0, x1 = 0, reach
+			}
+			rects = append(rects, BoxRect{X: x0, Y: band.lo, W: x1 - x0, H: band.hi - band.lo, Alpha: 1})
+		}
+	}
+	// Vertical arms: bands along x, reach along y (perpendiculars left/right)
+	vert := func(weight, opposite uint8, toFar bool) {
+		if weight == boxArmNone {
+			return
+		}
+		bands := boxBands(w, lt, weight)
+		for i, band := range bands {
+			side, other := left, right
+			if i == 1 {
+				side, other = right, left
+			}
+			reach := boxArmReach(h, lt, weight, left, right, opposite, weight == boxArmDouble, side, other, toFar)
+			y0, y1 := reach, h
+			if !toFar {
+				y0, y1 = 0, reach
+			}
+			rects = append(rects, BoxRect{X: band.lo, Y: y0, W: band.hi - band.lo, H: y1 - y0, Alpha: 1})
+		}
+	}
+	horiz(right, left, true)
+	horiz(left, right, false)
+	vert(down, up, true)
+	vert(up, down, false)
+	return rects
+}
+
+// boxBlockRects builds a U+2580-U+259F block element
+func boxBlockRects(r rune, w, h float64) []BoxRect {
+	solid := func(x0, y0, x1, y1 float64) BoxRect {
+		return BoxRect{X: x0, Y: y0, W: x1 - x0, H: y1 - y0, Alpha: 1}
+	}
+	eighthX := func(n int) float64 { return math.Round(w * float64(n) / 8) }
+	eighthY := func(n int) float64 { return math.Round(h * float64(n) / 8) }
+	midX, midY := math.Round(w/2), math.Round(h/2)
+
+	switch {
+	case r == 0x2580: // Upper half
+		return []BoxRect{solid(0, 0, w, midY)}
+	case r >= 0x2581 && r <= 0x2588: // Lower 1/8 .. full block
+		return []BoxRect{solid(0, h-eighthY(int(r-0x2580)), w, h)}
+	case r >= 0x2589 && r <= 0x258F: // Left 7/8 .. 1/8
+		return []BoxRect{solid(0, 0, eighthX(int(0x2590-r)), h)}
+	case r == 0x2590: // Right half
+		return []BoxRect{solid(midX, 0, w, h)}
+	case r >= 0x2591 && r <= 0x2593: // Light, medium, dark shade
+		return []BoxRect{{X: 0, Y: 0, W: w, H: h, Alpha: float64(r-0x2590) / 4}}
+	case r == 0x2594: // Upper 1/8
+		return []BoxRect{solid(0, 0, w, eighthY(1))}
+	case r == 0x2595: // Right 1/8
+		return []BoxRect{solid(w-eighthX(1), 0, w, h)}
+	}
+
+	// Quadrants U+2596-U+259F: upper-left, upper-right, lower-left, lower-right
+	quads := map[rune][4]bool{
+		0x2596: {false, false, true, false},
+		0x2597: {false, false, false, true},
+		0x2598: {true, false, false, false},
+		0x2599: {true, false, true, true},
+		0x259A: {true, false, false, true},
+		0x259B: {true, true, true, false},
+		0x259C: {true, true, false, true},
+		0x259D: {false, true, false, false},
+		0x259E: {false, true, true, false},
+		0x259F: {false, true, true, true},
+	}
+	q, ok := quads[r]
+	if !ok {
+		return nil
+	}
+	var rects []BoxRect
+	if q[0] {
+		rects = append(rects, solid(0, 0, midX, midY))
+	}
+	if q[1] {
+		rects = append(rects, solid(midX, 0, w, midY))
+	}
+	if q[2] {
+		rects = append(rects, solid(0, midY, midX, h))
+	}
+	if q[3] {
+		rects = append(rects, solid(midX, midY, w, h))
+	}
+	return rects
+}
+
+// BoxGlyphRects returns the rectangles that draw a box-drawing or block
+// element character (U+2500-U+259F) into a w×h pixel cell, so renderers can
+// paint seamless borders and blocks instead of relying on font glyphs that
+// may not meet at cell edges. Strokes are aligned to whole pixels. Returns
+// false for characters that should still come from the font (dashed lines,
+// arcs and diagonals).
+func BoxGlyphRects(r rune, w, h float64) ([]BoxRect, bool) {
+	switch {
+	case r >= 0x2500 && r <= 0x257F:
+		arms := boxLineArms[r-0x2500]
+		if arms == 0 {
+			return nil, false
+		}
+		return boxLineRects(arms, w, h), true
+	case r >= 0x2580 && r <= 0x259F:
+		rects := boxBlockRects(r, w, h)
+		return rects, rects != nil
+	}
+	return nil, false
+}
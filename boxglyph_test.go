@@ -0,0 +1,69 @@
+package purfecterm
+
+import "testing"
+
+// coverage paints rects into a w×h grid of booleans
+func coverage(rects []BoxRect, w, h int) [][]bool {
+	grid := make([][]bool, h)
+	for y := range grid {
+		grid[y] = make([]bool, w)
+	}
+	for _, r := range rects {
+		for y := int(r.Y); y < int(r.Y+r.H); y++ {
+			for x := int(r.X); x < int(r.X+r.W); x++ {
+				grid[y][x] = true
+			}
+		}
+	}
+	return grid
+}
+
+// '│' is a one-pixel column through the center of the cell, top to bottom.
+func TestBoxGlyphLightVertical(t *testing.T) {
+	rects, ok := BoxGlyphRects('│', 8, 16)
+	if !ok {
+		t.Fatal("'│' should be drawn as vectors")
+	}
+	grid := coverage(rects, 8, 16)
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 8; x++ {
+			if want := x == 3; grid[y][x] != want {
+				t.Fatalf("pixel %d,%d painted=%v, want %v", x, y, grid[y][x], want)
+			}
+		}
+	}
+}
+
+// '╔' joins its outer and inner strokes without crossing into the corner.
+func TestBoxGlyphDoubleCorner(t *testing.T) {
+	rects, _ := BoxGlyphRects('╔', 9, 18)
+	grid := coverage(rects, 9, 18)
+	// Bands for width 9 are x=3 and x=5; for height 18, y=7 and y=9
+	for _, p := range [][2]int{{3, 7}, {8, 7}, {3, 17}, {5, 9}, {8, 9}, {5, 17}} {
+		if !grid[p[1]][p[0]] {
+			t.Errorf("pixel %v should be painted", p)
+		}
+	}
+	for _, p := range [][2]int{{2, 7}, {3, 6}, {4, 8}, {5, 8}, {4, 9}} {
+		if grid[p[1]][p[0]] {
+			t.Errorf("pixel %v should be clear", p)
+		}
+	}
+}
+
+func TestBoxGlyphBlocks(t *testing.T) {
+	rects, ok := BoxGlyphRects('▄', 8, 16)
+	if !ok || len(rects) != 1 || rects[0].Y != 8 || rects[0].H != 8 || rects[0].W != 8 {
+		t.Fatalf("lower half block: %+v", rects)
+	}
+	rects, _ = BoxGlyphRects('▒', 8, 16)
+	if len(rects) != 1 || rects[0].Alpha != 0.5 {
+		t.Fatalf("medium shade: %+v", rects)
+	}
+	if _, ok := BoxGlyphRects('╭', 8, 16); ok {
+		t.Fatal("arcs should fall back to the font")
+	}
+	if _, ok := BoxGlyphRects('A', 8, 16); ok {
+		t.Fatal("non box-drawing runes should fall back to the font")
+	}
+}
@@ -18,6 +18,10 @@ const keyboardAutoScrollDuration = 500 * time.Millisecond
 // can resume (if no keyboard activity or scroll-causing event occurs).
 const manualScrollCooldown = 5 * time.Second
 
+// defaultScrollLineStep is how many lines a single mouse wheel notch scrolls
+// when no step has been configured.
+const defaultScrollLineStep = 3
+
 // HorizMemo stores horizontal scroll memo data for a single scanline.
 // This is populated during paint to track cursor position relative to rendered content.
 type HorizMemo struct {
@@ -118,10 +122,26 @@ type Buffer struct {
 	cursorBlink   int // 0=no blink, 1=slow blink, 2=fast blink
 
 	bracketedPasteMode bool
+	newlineMode        bool // LNM (ANSI mode 20): LF also returns the carriage, Enter sends CR LF
+	focusReporting     bool // DEC 1004: report focus in/out to the application
+	reverseScreen      bool // DECSCNM: default foreground/background swapped
+
+	// Colors set by the program over the scheme's (see SetPaletteColor, SetDynamicColor)
+	paletteOverrides map[int]Color
+	dynamicColors    map[DynamicColor]Color
+
+	// Renderers leave default-background cells unpainted (see SetTransparentBackground)
+	transparentBackground bool
+
+	// Synchronized output (DEC 2026): dirty callbacks are held back while active
+	syncOutput       bool
+	syncOutputStart  time.Time
+	syncOutputTimer  *time.Timer // Ends an update the program never finishes
+	syncDirtyPending bool
 
 	// Mouse tracking modes (set via DEC Private Mode sequences)
-	mouseTrackingMode  int // 0=off, 1000=X11 normal, 1002=cell motion, 1003=all motion
-	mouseEncodingMode  int // 0=X10 default, 1006=SGR extended
+	mouseTrackingMode  int // 0=off, 9=X10, 1000=X11 normal, 1002=cell motion, 1003=all motion
+	mouseEncodingMode  int // 0=X10 default, 1006=SGR extended, 1015=urxvt
 
 	currentFg        Color
 	currentBg            Color
@@ -133,11 +153,16 @@ type Buffer struct {
 	currentHasUnderlineColor bool
 	currentReverse       bool
 	currentBlink         bool
+	currentBlinkRapid    bool
 	currentStrikethrough bool
+	currentFaint         bool
+	currentConceal       bool
+	currentOverline      bool
 	currentFlexWidth     bool // Current attribute for East Asian Width mode
 
 	// Flexible cell width mode (East Asian Width)
 	flexWidthMode      bool               // When true, new chars get FlexWidth=true and calculated CellWidth
+	wideCharMode       bool               // When true (and flex is off), wide chars are followed by a continuation cell
 	visualWidthWrap    bool               // When true, wrap based on accumulated visual width, not cell count
 	ambiguousWidthMode AmbiguousWidthMode // How to handle ambiguous width chars: Auto/Narrow/Wide
 
@@ -151,9 +176,19 @@ type Buffer struct {
 	// Scrollback storage
 	scrollback         [][]Cell
 	scrollbackInfo     []LineInfo
-	maxScrollback      int
+	maxScrollback      int  // Line cap (0 = unlimited)
+	scrollbackByteLimit int // Estimated byte cap (0 = unlimited)
+	scrollbackBytes    int  // Estimated bytes currently held in scrollback
 	scrollOffset       int  // Vertical scroll offset
 	scrollbackDisabled bool // When true, scrollback accumulation is disabled (for games)
+	scrollLineStep     int  // Lines per wheel notch (0 = defaultScrollLineStep)
+	scrollPageStep     int  // Lines per page scroll (0 = rows-1)
+
+	// Storage of the last line evicted from scrollback, reused by makeEmptyLine
+	spareLine []Cell
+
+	// When true, renderers skip the scrollback boundary line
+	hideScrollbackBoundary bool
 
 	// Horizontal scrolling
 	horizOffset int // Horizontal scroll offset (in columns)
@@ -179,10 +214,40 @@ type Buffer struct {
 
 	// Smart word wrap mode (DEC Private Mode 7702)
 	smartWordWrap bool // When true, wrap at word boundaries instead of mid-word
+	// Characters smart word wrap may break after (nil = defaultWordWrapBoundaries)
+	wordWrapBoundaries []rune
+
+	// When true, HT writes spaces up to the next tab stop instead of moving over cells
+	expandTabs bool
+
+	// When true, a cursor waiting to wrap reports and moves as if on the last column (xterm)
+	pendingWrapMode bool
+
+	// DECSTBM scroll margins (0-indexed, inclusive); 0/0 means full screen
+	scrollTop    int
+	scrollBottom int
+
+	// DECOM (DEC Private Mode 6): CUP/VPA rows and cursor reports are
+	// relative to the top margin
+	originMode bool
 
 	selectionActive      bool
 	selStartX, selStartY int
 	selEndX, selEndY     int
+	autoCopyOnSelect     bool              // Report finished selections (see SetAutoCopyOnSelect)
+	onSelectionChanged   func(text string) // Called when a selection is finished or cleared
+
+	// Applied to pasted bytes before they're sent (see SetPasteFilter)
+	pasteFilter func(data []byte) []byte
+
+	// Search highlight ranges (buffer-absolute rows, see SetSearchMatches)
+	searchMatches []Match
+
+	// Named scrollback positions (buffer-absolute rows, see AddBookmark)
+	bookmarks []Bookmark
+
+	resizeAnchor      ResizeAnchor // Line Resize keeps in place while scrolled back
+	scrollbackEvicted int          // Lines ever evicted from the scrollback front
 
 	savedCursorX int
 	savedCursorY int
@@ -191,6 +256,13 @@ type Buffer struct {
 	onDirty       func()
 	onScaleChange func()     // Called when screen scaling modes change
 	onThemeChange func(bool) // Called when theme changes (arg: isDark)
+	onTitleChange func(string) // Called when the window title changes (OSC 0/2)
+	onComment     func(string) // Called for OSC 9999 header comments
+	onCursorMove  func(x, y int)                  // Called when a cursor mover changes the position
+	onModeChange  func(mode string, enabled bool) // Called when a terminal mode is toggled
+
+	// Window title set by the application (OSC 0/2)
+	title string
 
 	// Theme state (DECSCNM - Screen Mode)
 	darkTheme          bool // Current theme: true=dark, false=light
@@ -231,6 +303,7 @@ type Buffer struct {
 	cropRects    map[int]*CropRectangle // Crop rectangle ID -> CropRectangle
 	spriteUnitX  int                    // Subdivisions per cell horizontally (default 8)
 	spriteUnitY  int                    // Subdivisions per cell vertically (default 8)
+	hiddenLayers map[int]bool           // Sprite layers excluded from rendering (nil = all shown)
 
 	// Screen crop (in sprite coordinate units, -1 = no crop)
 	widthCrop  int // X coordinate beyond which nothing renders
@@ -241,6 +314,16 @@ type Buffer struct {
 
 	// Max content width from splits (for horizontal scrollbar, independent from scrollback)
 	splitContentWidth int
+
+	// Most recent split validation diagnostic (see LastSplitWarning),
+	// guarded by splitWarningMu rather than mu
+	splitWarningMu sync.Mutex
+	splitWarning   string
+
+	// Damage tracking for TakeDamage: the visible grid as last emitted, and
+	// whether a full-screen event (clear, scroll, resize) has happened since
+	damageLast [][]Cell
+	damageFull bool
 }
 
 // ScreenSplit defines a split region that can show a different part of the buffer.
@@ -255,6 +338,7 @@ type ScreenSplit struct {
 	LeftFineScroll  int     // 0 to (subdivisions-1), higher = more of left column clipped
 	CharWidthScale  float64 // Character width multiplier (0 = inherit from main screen)
 	LineDensity     int     // Line density override (0 = inherit from main screen)
+	ScrollOffset    int     // Extra lines scrolled back, on top of the main view's scroll offset
 }
 
 // NewBuffer creates a new terminal buffer
@@ -325,6 +409,9 @@ func (b *Buffer) SetScaleChangeCallback(fn func()) {
 
 func (b *Buffer) markDirty() {
 	b.dirty = true
+	if b.holdDirtyForSync() {
+		return
+	}
 	if b.onDirty != nil {
 		b.onDirty()
 	}
@@ -350,8 +437,97 @@ func (b *Buffer) notifyThemeChange() {
 	}
 }
 
+// SetTitleChangeCallback sets a callback to be invoked when the window title
+// changes. The callback runs without the buffer lock held.
+func (b *Buffer) SetTitleChangeCallback(fn func(string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onTitleChange = fn
+}
+
+// SetCursorMoveCallback sets a callback to be invoked when the cursor is
+// repositioned by SetCursor, RestoreCursor or one of the MoveCursor methods
+// (and so by the CSI sequences that use them). Cursor advance from printed
+// text is not reported. The callback runs without the buffer lock held.
+func (b *Buffer) SetCursorMoveCallback(fn func(x, y int)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onCursorMove = fn
+}
+
+// unlockNotifyCursor releases the lock and reports the cursor position to
+// the cursor move callback if it differs from (oldX, oldY).
+func (b *Buffer) unlockNotifyCursor(oldX, oldY int) {
+	x, y, fn := b.cursorX, b.cursorY, b.onCursorMove
+	b.mu.Unlock()
+	if fn != nil && (x != oldX || y != oldY) {
+		fn(x, y)
+	}
+}
+
+// SetModeChangeCallback sets a callback to be invoked when a terminal mode
+// changes state. mode is one of "autowrap", "bracketedpaste",
+// "focusreporting", "newline", "origin", "flexwidth", "widechar",
+// "132column" or "40column". Adapters use "bracketedpaste" to learn when
+// the child wants pastes wrapped (PreparePaste already follows the mode).
+// The callback runs without the buffer lock held.
+func (b *Buffer) SetModeChangeCallback(fn func(mode string, enabled bool)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onModeChange = fn
+}
+
+// unlockNotifyMode releases the lock and reports mode to the mode change
+// callback if its state changed.
+func (b *Buffer) unlockNotifyMode(mode string, was, enabled bool) {
+	fn := b.onModeChange
+	b.mu.Unlock()
+	if fn != nil && was != enabled {
+		fn(mode, enabled)
+	}
+}
+
+// SetTitle sets the window title
+// This is called by OSC 0 and OSC 2 escape sequences
+func (b *Buffer) SetTitle(title string) {
+	b.mu.Lock()
+	changed := b.title != title
+	b.title = title
+	fn := b.onTitleChange
+	b.mu.Unlock()
+	if changed && fn != nil {
+		fn(title)
+	}
+}
+
+// SetCommentCallback sets a callback to be invoked with the text of each
+// OSC 9999 header comment, such as one prepended to a saved session.
+// Comments never reach the screen. The callback runs without the buffer
+// lock held.
+func (b *Buffer) SetCommentCallback(fn func(text string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onComment = fn
+}
+
+// reportComment passes an OSC 9999 comment to the comment callback
+func (b *Buffer) reportComment(text string) {
+	b.mu.RLock()
+	fn := b.onComment
+	b.mu.RUnlock()
+	if fn != nil {
+		fn(text)
+	}
+}
+
+// GetTitle returns the window title last set by the application
+func (b *Buffer) GetTitle() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.title
+}
+
 // SetDarkTheme sets the current theme (true=dark, false=light)
-// This is called by DECSCNM (CSI ? 5 h/l) escape sequences
 func (b *Buffer) SetDarkTheme(dark bool) {
 	b.mu.Lock()
 	changed := b.darkTheme != dark
@@ -365,6 +541,75 @@ func (b *Buffer) SetDarkTheme(dark bool) {
 	}
 }
 
+// SetReverseScreen sets DECSCNM reverse video (CSI ? 5 h/l), which swaps the
+// default foreground and background independently of the theme
+func (b *Buffer) SetReverseScreen(reverse bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.reverseScreen != reverse {
+		b.reverseScreen = reverse
+		b.markFullDamage()
+		b.markDirty()
+	}
+}
+
+// IsReverseScreen returns whether DECSCNM reverse video is active
+func (b *Buffer) IsReverseScreen() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.reverseScreen
+}
+
+// EffectiveScheme returns the scheme renderers should draw with: s with
+// any colors the program set (OSC 4/10/11/12) in place of its own, then
+// reversed with ReverseVideo while DECSCNM reverse video is active
+func (b *Buffer) EffectiveScheme(s ColorScheme) ColorScheme {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	s = b.applyColorOverridesLocked(s)
+	if b.reverseScreen {
+		return s.ReverseVideo()
+	}
+	return s
+}
+
+// SetTransparentBackground makes default-background cells transparent, for
+// a terminal drawn over a background image: renderers clear instead of
+// painting the scheme background, and only cells with an explicit
+// background color are filled. The host window must be able to show
+// transparency (e.g. an RGBA visual in GTK, WA_TranslucentBackground in Qt).
+func (b *Buffer) SetTransparentBackground(transparent bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.transparentBackground != transparent {
+		b.transparentBackground = transparent
+		b.markFullDamage()
+		b.markDirty()
+	}
+}
+
+// IsTransparentBackground returns whether default-background cells are
+// left transparent
+func (b *Buffer) IsTransparentBackground() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.transparentBackground
+}
+
+// SkipBackgroundFill reports whether a renderer can leave a cell's
+// background unpainted. bg is the cell's resolved background after
+// selection, search and cursor shading, and schemeBg the color the
+// renderer cleared the screen to. Normally any cell showing schemeBg is
+// skipped; with a transparent background the screen was cleared to
+// nothing, so only default-background cells are, and a cell explicitly
+// colored like the scheme background still hides the image behind it.
+func (b *Buffer) SkipBackgroundFill(cell *Cell, bg, schemeBg Color) bool {
+	if bg != schemeBg {
+		return false
+	}
+	return !b.IsTransparentBackground() || cell.Background.Type == ColorTypeDefault
+}
+
 // IsDarkTheme returns the current theme state (true=dark, false=light)
 func (b *Buffer) IsDarkTheme() bool {
 	b.mu.RLock()
@@ -401,6 +646,7 @@ func (b *Buffer) UpdatePreferredDarkTheme(dark bool) {
 }
 
 func (b *Buffer) initScreen() {
+	b.markFullDamage()
 	effectiveRows := b.EffectiveRows()
 	b.screen = make([][]Cell, effectiveRows)
 	b.lineInfos = make([]LineInfo, effectiveRows)
@@ -412,6 +658,11 @@ func (b *Buffer) initScreen() {
 
 // makeEmptyLine creates an empty line (zero length - will grow as chars are written)
 func (b *Buffer) makeEmptyLine() []Cell {
+	// Reuse the storage of the last line evicted from scrollback, if any
+	if line := b.spareLine; line != nil {
+		b.spareLine = nil
+		return line[:0]
+	}
 	// Start with zero length - lines grow dynamically as characters are written
 	return make([]Cell, 0)
 }
@@ -463,6 +714,23 @@ func (b *Buffer) Resize(cols, rows int) {
 	// (scrollOffset > logicalHiddenAbove means boundary line would be visible or past it)
 	wasViewingScrollback := b.scrollOffset > oldLogicalHiddenAbove
 
+	// Line to keep in place (see SetResizeAnchor), as a buffer-absolute row
+	// and its distance from the top of the visible area
+	anchorY, anchorRow := -1, 0
+	if wasViewingScrollback {
+		top := b.screenToBufferY(0)
+		switch b.resizeAnchor {
+		case ResizeAnchorTop:
+			anchorY = top
+		case ResizeAnchorCursor:
+			anchorY = len(b.scrollback) + b.cursorY
+			anchorRow = anchorY - top
+		case ResizeAnchorBottom:
+			anchorY = top + b.rows - 1
+		}
+	}
+	evictedBefore := b.scrollbackEvicted
+
 	// When window gets wider, prefer to unscroll horizontally first
 	// This reveals hidden columns on the left before showing blank columns on the right
 	if cols > b.cols && b.horizOffset > 0 {
@@ -509,15 +777,53 @@ func (b *Buffer) Resize(cols, rows int) {
 		b.scrollOffset = newLogicalHiddenAbove
 	}
 
+	// Put the anchor line back where it was, following it past any
+	// scrollback lines the resize evicted
+	if anchorY >= 0 {
+		if b.resizeAnchor == ResizeAnchorBottom {
+			anchorRow = rows - 1
+		}
+		anchorY -= b.scrollbackEvicted - evictedBefore
+		b.scrollOffset = b.scrollOffsetForRowInternal(anchorY, anchorRow)
+	}
+
 	// Also clamp to maximum scroll offset (scrollback + hidden + magnetic threshold)
 	maxOffset := b.getMaxScrollOffsetInternal()
 	if b.scrollOffset > maxOffset {
 		b.scrollOffset = maxOffset
 	}
 
+	b.markFullDamage()
 	b.markDirty()
 }
 
+// ResizeAnchor chooses which line Resize keeps in place while the user is
+// scrolled back into the scrollback
+type ResizeAnchor int
+
+const (
+	ResizeAnchorNone   ResizeAnchor = iota // Keep the scroll offset (default)
+	ResizeAnchorTop                        // Keep the top visible line on the top row
+	ResizeAnchorCursor                     // Keep the cursor's line the same distance from the top
+	ResizeAnchorBottom                     // Keep the bottom visible line on the bottom row
+)
+
+// SetResizeAnchor sets which line Resize pins while the view is scrolled
+// back, so the content being read doesn't jump as the window changes
+// size. It has no effect while the view follows the live screen.
+func (b *Buffer) SetResizeAnchor(anchor ResizeAnchor) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.resizeAnchor = anchor
+}
+
+// GetResizeAnchor returns the line Resize pins while scrolled back
+func (b *Buffer) GetResizeAnchor() ResizeAnchor {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.resizeAnchor
+}
+
 // adjustScreenToRows adjusts the screen slice to have the target number of rows
 // without truncating line content (lines remain variable width)
 // Only moves lines to scrollback if actual content exceeds the new height
@@ -585,31 +891,123 @@ func (b *Buffer) adjustScreenToRows(targetRows int) {
 	}
 }
 
-// pushLineToScrollback adds a line to the scrollback buffer
+// scrollbackCellBytes is the estimated memory cost of one stored cell, used
+// to enforce the scrollback byte limit (Cell is 88 bytes on 64-bit targets).
+const scrollbackCellBytes = 88
+
+// scrollbackLineBytes estimates the memory used by one scrollback line
+func scrollbackLineBytes(line []Cell) int {
+	return len(line) * scrollbackCellBytes
+}
+
+// pushLineToScrollback adds a line to the scrollback buffer, then evicts from
+// the front until both the line cap and the byte cap are satisfied
 func (b *Buffer) pushLineToScrollback(line []Cell, info LineInfo) {
 	// Skip if scrollback is disabled (lines are discarded instead)
 	if b.scrollbackDisabled {
 		return
 	}
 
-	trimmed := false
-	if len(b.scrollback) >= b.maxScrollback {
-		b.scrollback = b.scrollback[1:]
-		b.scrollbackInfo = b.scrollbackInfo[1:]
-		trimmed = true
-	}
 	b.scrollback = append(b.scrollback, line)
 	b.scrollbackInfo = append(b.scrollbackInfo, info)
+	b.scrollbackBytes += scrollbackLineBytes(line)
+
+	trimmed := b.trimScrollback()
 
 	// If scrollback was trimmed from front and we're scrolled into scrollback,
 	// adjust offset to keep viewing the same content
-	if trimmed && b.scrollOffset > 0 {
-		b.scrollOffset--
+	if trimmed > 0 && b.scrollOffset > 0 {
+		b.scrollOffset -= trimmed
+		if b.scrollOffset < 0 {
+			b.scrollOffset = 0
+		}
 	}
 	// Note: if user was at scrollOffset 0, they stay at 0 (viewing newest content)
 	// If at some other scrollback position, they stay there but see newer lines
 }
 
+// trimScrollback evicts the oldest scrollback lines while either cap is
+// exceeded (a cap of 0 is disabled) and returns how many were removed.
+// The newest line is always kept, even if it alone exceeds the byte cap.
+func (b *Buffer) trimScrollback() int {
+	trimmed := 0
+	for len(b.scrollback) > 1 &&
+		((b.maxScrollback > 0 && len(b.scrollback) > b.maxScrollback) ||
+			(b.scrollbackByteLimit > 0 && b.scrollbackBytes > b.scrollbackByteLimit)) {
+		b.scrollbackBytes -= scrollbackLineBytes(b.scrollback[0])
+		b.spareLine = b.scrollback[0]
+		b.scrollback[0] = nil
+		b.scrollback = b.scrollback[1:]
+		b.scrollbackInfo = b.scrollbackInfo[1:]
+		trimmed++
+	}
+	if trimmed > 0 {
+		b.scrollbackEvicted += trimmed
+		b.shiftBookmarksInternal(trimmed)
+	}
+	return trimmed
+}
+
+// SetMaxScrollback changes the scrollback line cap (0 = unlimited) at
+// runtime. Shrinking it evicts the oldest lines at once; the view keeps
+// its distance from the bottom unless that now lies past the oldest line.
+func (b *Buffer) SetMaxScrollback(lines int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lines < 0 {
+		lines = 0
+	}
+	b.maxScrollback = lines
+	if b.trimScrollback() > 0 {
+		maxOffset := b.getMaxScrollOffsetInternal()
+		if b.scrollOffset > maxOffset {
+			b.scrollOffset = maxOffset
+		}
+		b.markFullDamage()
+		b.markDirty()
+	}
+}
+
+// GetMaxScrollback returns the scrollback line cap (0 = unlimited)
+func (b *Buffer) GetMaxScrollback() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.maxScrollback
+}
+
+// SetScrollbackByteLimit caps scrollback memory at roughly the given number of
+// bytes (estimated from cell counts), in addition to the line cap.
+// 0 disables the byte cap.
+func (b *Buffer) SetScrollbackByteLimit(bytes int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if bytes < 0 {
+		bytes = 0
+	}
+	b.scrollbackByteLimit = bytes
+	if b.trimScrollback() > 0 {
+		maxOffset := b.getMaxScrollOffsetInternal()
+		if b.scrollOffset > maxOffset {
+			b.scrollOffset = maxOffset
+		}
+		b.markDirty()
+	}
+}
+
+// GetScrollbackByteLimit returns the scrollback byte cap (0 = disabled)
+func (b *Buffer) GetScrollbackByteLimit() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.scrollbackByteLimit
+}
+
+// GetScrollbackBytes returns the estimated memory used by scrollback lines
+func (b *Buffer) GetScrollbackBytes() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.scrollbackBytes
+}
+
 // SetLogicalSize sets the logical terminal dimensions
 // A value of 0 means "use physical dimension"
 // This implements the ESC [ 8 ; rows ; cols t escape sequence
@@ -665,6 +1063,7 @@ func (b *Buffer) SetLogicalSize(logicalRows, logicalCols int) {
 		b.cursorY = newEffectiveRows - 1
 	}
 
+	b.markFullDamage()
 	b.markDirty()
 }
 
@@ -759,8 +1158,9 @@ func (b *Buffer) GetSize() (cols, rows int) {
 // SetBracketedPasteMode enables or disables bracketed paste mode
 func (b *Buffer) SetBracketedPasteMode(enabled bool) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	was := b.bracketedPasteMode
 	b.bracketedPasteMode = enabled
+	b.unlockNotifyMode("bracketedpaste", was, enabled)
 }
 
 // IsBracketedPasteModeEnabled returns whether bracketed paste mode is enabled
@@ -770,8 +1170,62 @@ func (b *Buffer) IsBracketedPasteModeEnabled() bool {
 	return b.bracketedPasteMode
 }
 
+// SetNewlineMode sets line feed/new line mode (LNM, CSI 20 h/l). While it
+// is on, a received LF (or VT/FF) also moves the cursor to column 0, and
+// Enter should send CR LF instead of CR (see EnterKeySequence).
+func (b *Buffer) SetNewlineMode(enabled bool) {
+	b.mu.Lock()
+	was := b.newlineMode
+	b.newlineMode = enabled
+	b.unlockNotifyMode("newline", was, enabled)
+}
+
+// IsNewlineModeEnabled returns whether LNM is on
+func (b *Buffer) IsNewlineModeEnabled() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.newlineMode
+}
+
+// EnterKeySequence returns what an unmodified Enter key sends to the PTY:
+// CR, or CR LF under LNM
+func (b *Buffer) EnterKeySequence() []byte {
+	if b.IsNewlineModeEnabled() {
+		return []byte{'\r', '\n'}
+	}
+	return []byte{'\r'}
+}
+
+// SetFocusReporting enables or disables focus in/out reporting (DEC 1004)
+func (b *Buffer) SetFocusReporting(enabled bool) {
+	b.mu.Lock()
+	was := b.focusReporting
+	b.focusReporting = enabled
+	b.unlockNotifyMode("focusreporting", was, enabled)
+}
+
+// IsFocusReportingEnabled returns whether focus reporting is enabled
+func (b *Buffer) IsFocusReportingEnabled() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.focusReporting
+}
+
+// FocusEvent returns the bytes to send to the application when the terminal
+// gains or loses focus: ESC [ I / ESC [ O with focus reporting enabled,
+// nil otherwise
+func (b *Buffer) FocusEvent(focused bool) []byte {
+	if !b.IsFocusReportingEnabled() {
+		return nil
+	}
+	if focused {
+		return []byte("\x1b[I")
+	}
+	return []byte("\x1b[O")
+}
+
 // SetMouseTrackingMode sets the mouse tracking mode
-// 0=off, 1000=X11 normal (press/release), 1002=cell motion, 1003=all motion
+// 0=off, 9=X10 (press only), 1000=X11 normal (press/release), 1002=cell motion, 1003=all motion
 func (b *Buffer) SetMouseTrackingMode(mode int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -786,7 +1240,7 @@ func (b *Buffer) GetMouseTrackingMode() int {
 }
 
 // SetMouseEncodingMode sets the mouse encoding mode
-// 0=X10 default, 1006=SGR extended
+// 0=X10 default, 1006=SGR extended, 1015=urxvt decimal
 func (b *Buffer) SetMouseEncodingMode(mode int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -805,9 +1259,10 @@ func (b *Buffer) GetMouseEncodingMode() int {
 // based on Unicode East_Asian_Width property (0.5/1.0/1.5/2.0 cell units)
 func (b *Buffer) SetFlexWidthMode(enabled bool) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	was := b.flexWidthMode
 	b.flexWidthMode = enabled
 	b.currentFlexWidth = enabled
+	b.unlockNotifyMode("flexwidth", was, enabled)
 }
 
 // IsFlexWidthModeEnabled returns whether flexible East Asian Width mode is enabled
@@ -817,6 +1272,24 @@ func (b *Buffer) IsFlexWidthModeEnabled() bool {
 	return b.flexWidthMode
 }
 
+// SetWideCharMode enables or disables wide-char cell occupancy
+// When enabled (and flex mode is off), a wide character is stored as two
+// cells like a hardware terminal: the glyph followed by a continuation cell,
+// so logical cell indexes match visual columns
+func (b *Buffer) SetWideCharMode(enabled bool) {
+	b.mu.Lock()
+	was := b.wideCharMode
+	b.wideCharMode = enabled
+	b.unlockNotifyMode("widechar", was, enabled)
+}
+
+// IsWideCharModeEnabled returns whether wide-char cell occupancy is enabled
+func (b *Buffer) IsWideCharModeEnabled() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.wideCharMode
+}
+
 // SetVisualWidthWrap enables or disables visual width-based line wrapping
 // When enabled, lines wrap based on accumulated visual width (sum of CellWidth)
 // When disabled, lines wrap based on cell count
@@ -886,23 +1359,178 @@ func (b *Buffer) SetAttributes(fg, bg Color, bold, italic, underline, reverse bo
 	b.currentReverse = reverse
 }
 
+// Attributes is the current pen: the attributes written into new cells.
+// Fields mean the same as the Cell fields of the same name.
+type Attributes struct {
+	Foreground        Color
+	Background        Color
+	Bold              bool
+	Faint             bool
+	Italic            bool
+	Underline         bool
+	UnderlineStyle    UnderlineStyle
+	UnderlineColor    Color
+	HasUnderlineColor bool
+	Reverse           bool
+	Blink             bool
+	BlinkRapid        bool
+	Strikethrough     bool
+	Overline          bool
+	Conceal           bool
+	FlexWidth         bool
+	BGP               int
+	XFlip             bool
+	YFlip             bool
+	Font              uint8
+}
+
+// GetCurrentAttributes returns the current pen, for a UI showing the active
+// attributes or a serializer saving them
+func (b *Buffer) GetCurrentAttributes() Attributes {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return Attributes{
+		Foreground:        b.currentFg,
+		Background:        b.currentBg,
+		Bold:              b.currentBold,
+		Faint:             b.currentFaint,
+		Italic:            b.currentItalic,
+		Underline:         b.currentUnderline,
+		UnderlineStyle:    b.currentUnderlineStyle,
+		UnderlineColor:    b.currentUnderlineColor,
+		HasUnderlineColor: b.currentHasUnderlineColor,
+		Reverse:           b.currentReverse,
+		Blink:             b.currentBlink,
+		BlinkRapid:        b.currentBlinkRapid,
+		Strikethrough:     b.currentStrikethrough,
+		Overline:          b.currentOverline,
+		Conceal:           b.currentConceal,
+		FlexWidth:         b.currentFlexWidth,
+		BGP:               b.currentBGP,
+		XFlip:             b.currentXFlip,
+		YFlip:             b.currentYFlip,
+		Font:              b.currentFont,
+	}
+}
+
+// SetCurrentAttributes restores a pen read by GetCurrentAttributes. The
+// font slot is clamped to 0..10 as in SetFont.
+func (b *Buffer) SetCurrentAttributes(a Attributes) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentFg = a.Foreground
+	b.currentBg = a.Background
+	b.currentBold = a.Bold
+	b.currentFaint = a.Faint
+	b.currentItalic = a.Italic
+	b.currentUnderline = a.Underline
+	b.currentUnderlineStyle = a.UnderlineStyle
+	b.currentUnderlineColor = a.UnderlineColor
+	b.currentHasUnderlineColor = a.HasUnderlineColor
+	b.currentReverse = a.Reverse
+	b.currentBlink = a.Blink
+	b.currentBlinkRapid = a.BlinkRapid
+	b.currentStrikethrough = a.Strikethrough
+	b.currentOverline = a.Overline
+	b.currentConceal = a.Conceal
+	b.currentFlexWidth = a.FlexWidth
+	b.currentBGP = a.BGP
+	b.currentXFlip = a.XFlip
+	b.currentYFlip = a.YFlip
+	b.currentFont = min(a.Font, 10)
+}
+
 // ResetAttributes resets text attributes to defaults
 func (b *Buffer) ResetAttributes() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.resetAttributesInternal()
+}
+
+// resetAttributesInternal is ResetAttributes with the lock already held
+func (b *Buffer) resetAttributesInternal() {
 	b.currentFg = DefaultForeground
 	b.currentBg = DefaultBackground
 	b.currentBold = false
+	b.currentFaint = false
 	b.currentItalic = false
 	b.currentUnderline = false
 	b.currentUnderlineStyle = UnderlineNone
 	b.currentHasUnderlineColor = false
 	b.currentReverse = false
 	b.currentBlink = false
+	b.currentBlinkRapid = false
 	b.currentStrikethrough = false
+	b.currentOverline = false
+	b.currentConceal = false
 	b.currentFont = 0
 }
 
+// CurrentSGR returns the SGR parameters (without CSI or the final 'm') that
+// reproduce the current text attributes from a reset, e.g. "0;1;4;31".
+// It is the reply body for a DECRQSS query of SGR.
+func (b *Buffer) CurrentSGR() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	sgr := []string{"0"}
+	if b.currentBold {
+		sgr = append(sgr, "1")
+	}
+	if b.currentFaint {
+		sgr = append(sgr, "2")
+	}
+	if b.currentItalic {
+		sgr = append(sgr, "3")
+	}
+	switch b.currentUnderlineStyle {
+	case UnderlineNone:
+		if b.currentUnderline {
+			sgr = append(sgr, "4")
+		}
+	case UnderlineSingle:
+		sgr = append(sgr, "4")
+	default:
+		sgr = append(sgr, "4:"+itoa(int(b.currentUnderlineStyle)))
+	}
+	if b.currentBlinkRapid {
+		sgr = append(sgr, "6")
+	} else if b.currentBlink {
+		sgr = append(sgr, "5")
+	}
+	if b.currentReverse {
+		sgr = append(sgr, "7")
+	}
+	if b.currentConceal {
+		sgr = append(sgr, "8")
+	}
+	if b.currentStrikethrough {
+		sgr = append(sgr, "9")
+	}
+	if b.currentFont != 0 {
+		sgr = append(sgr, itoa(10+int(b.currentFont)))
+	}
+	if b.currentOverline {
+		sgr = append(sgr, "53")
+	}
+	if !b.currentFg.IsDefault() {
+		sgr = append(sgr, b.currentFg.ToSGRCode(true))
+	}
+	if !b.currentBg.IsDefault() {
+		sgr = append(sgr, b.currentBg.ToSGRCode(false))
+	}
+	if b.currentHasUnderlineColor {
+		// SGR 58 has no short form for the 16 standard colors
+		switch c := b.currentUnderlineColor; c.Type {
+		case ColorTypeStandard, ColorTypePalette:
+			sgr = append(sgr, "58;5;"+itoa(int(c.Index)))
+		case ColorTypeTrueColor:
+			sgr = append(sgr, "58;2;"+itoa(int(c.R))+";"+itoa(int(c.G))+";"+itoa(int(c.B)))
+		}
+	}
+	return strings.Join(sgr, ";")
+}
+
 // SetFont sets the current font slot (0..10) written into subsequent cells.
 // Values are clamped to the valid range.
 func (b *Buffer) SetFont(slot int) {
@@ -1015,6 +1643,13 @@ func (b *Buffer) SetBold(bold bool) {
 	b.currentBold = bold
 }
 
+// SetFaint sets faint (dim) attribute
+func (b *Buffer) SetFaint(faint bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentFaint = faint
+}
+
 // SetItalic sets italic attribute
 func (b *Buffer) SetItalic(italic bool) {
 	b.mu.Lock()
@@ -1069,6 +1704,15 @@ func (b *Buffer) SetBlink(blink bool) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	b.currentBlink = blink
+	b.currentBlinkRapid = false
+}
+
+// SetBlinkRapid sets blink attribute with rapid (SGR 6) timing
+func (b *Buffer) SetBlinkRapid(rapid bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentBlink = rapid
+	b.currentBlinkRapid = rapid
 }
 
 // SetStrikethrough sets strikethrough attribute
@@ -1078,6 +1722,20 @@ func (b *Buffer) SetStrikethrough(strikethrough bool) {
 	b.currentStrikethrough = strikethrough
 }
 
+// SetConceal sets conceal (hidden) attribute
+func (b *Buffer) SetConceal(conceal bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentConceal = conceal
+}
+
+// SetOverline sets overline attribute
+func (b *Buffer) SetOverline(overline bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentOverline = overline
+}
+
 
 
 
@@ -1103,8 +1761,9 @@ func (b *Buffer) SetStrikethrough(strikethrough bool) {
 // When disabled, the cursor stays at the last column and characters overwrite that position.
 func (b *Buffer) SetAutoWrapMode(enabled bool) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	was := b.autoWrapMode
 	b.autoWrapMode = enabled
+	b.unlockNotifyMode("autowrap", was, enabled)
 }
 
 // IsAutoWrapModeEnabled returns true if auto-wrap is enabled (DECAWM).
@@ -1115,8 +1774,8 @@ func (b *Buffer) IsAutoWrapModeEnabled() bool {
 }
 
 // SetSmartWordWrap enables or disables smart word wrap (mode 7702).
-// When enabled, wrap occurs at word boundaries (space, hyphen, comma, semicolon, emdash)
-// instead of mid-word.
+// When enabled, wrap occurs at word boundaries (space, hyphen, comma, semicolon, emdash
+// by default, see SetWordWrapBoundaries) instead of mid-word.
 func (b *Buffer) SetSmartWordWrap(enabled bool) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -1130,6 +1789,96 @@ func (b *Buffer) IsSmartWordWrapEnabled() bool {
 	return b.smartWordWrap
 }
 
+// defaultWordWrapBoundaries are the characters smart word wrap breaks after
+// unless SetWordWrapBoundaries chooses others: space, hyphen, comma,
+// semicolon and emdash (U+2014)
+var defaultWordWrapBoundaries = []rune{' ', '-', ',', ';', '\u2014'}
+
+// SetWordWrapBoundaries sets the characters smart word wrap may break a
+// line after, for content whose natural break points differ (paths, URLs,
+// other scripts). nil or empty restores the default set.
+func (b *Buffer) SetWordWrapBoundaries(runes []rune) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(runes) == 0 {
+		b.wordWrapBoundaries = nil
+		return
+	}
+	b.wordWrapBoundaries = append([]rune(nil), runes...)
+}
+
+// GetWordWrapBoundaries returns a copy of the characters smart word wrap
+// breaks after
+func (b *Buffer) GetWordWrapBoundaries() []rune {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.wordWrapBoundaries == nil {
+		return append([]rune(nil), defaultWordWrapBoundaries...)
+	}
+	return append([]rune(nil), b.wordWrapBoundaries...)
+}
+
+// isWordWrapBoundaryInternal reports whether smart word wrap may break
+// after ch. Caller must hold the lock.
+func (b *Buffer) isWordWrapBoundaryInternal(ch rune) bool {
+	boundaries := b.wordWrapBoundaries
+	if boundaries == nil {
+		boundaries = defaultWordWrapBoundaries
+	}
+	for _, r := range boundaries {
+		if ch == r {
+			return true
+		}
+	}
+	return false
+}
+
+// SetExpandTabs makes a tab write spaces (with the current attributes) up
+// to the next tab stop, rather than moving the cursor over the cells in
+// between. The screen then holds no gaps where a tab was, for exporters
+// that want literal spaces.
+func (b *Buffer) SetExpandTabs(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expandTabs = enabled
+}
+
+// IsExpandTabsEnabled returns true if tabs are expanded to spaces.
+func (b *Buffer) IsExpandTabsEnabled() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.expandTabs
+}
+
+// SetPendingWrapMode selects how the cursor behaves after a character is
+// written in the last column. Either way the wrap waits for the next
+// printable character, so a full-width line followed by CR LF leaves no
+// blank line. Off (the default), the cursor sits one past the margin until
+// then. On, it stays on the last column with the wrap pending, as xterm
+// and the VT series do (the "last column flag"): GetCursor reports that
+// column, and BS, LF, cursor moves and edits first drop the pending wrap.
+func (b *Buffer) SetPendingWrapMode(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pendingWrapMode = enabled
+}
+
+// IsPendingWrapModeEnabled returns true if the cursor stays on the last
+// column while a wrap is pending.
+func (b *Buffer) IsPendingWrapModeEnabled() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.pendingWrapMode
+}
+
+// IsWrapPending reports whether the next printable character will wrap to
+// the next line, because the last one filled the row.
+func (b *Buffer) IsWrapPending() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.autoWrapMode && b.pastRightMarginInternal()
+}
+
 
 
 
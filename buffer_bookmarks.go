@@ -0,0 +1,58 @@
+package purfecterm
+
+// --- Scrollback Bookmarks ---
+
+// Bookmark is a named position in the buffer. BufferY is buffer-absolute
+// like Match.Row (0 = oldest scrollback line) and follows its line as old
+// scrollback is evicted; pass it to EnsureVisibleBufferY to jump back.
+type Bookmark struct {
+	Name    string
+	BufferY int
+}
+
+// AddBookmark marks the bottom visible row under name, replacing any
+// bookmark already using that name. Jumping to it with PositionBottom
+// brings back the view it was set from.
+func (b *Buffer) AddBookmark(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bufferY := b.screenToBufferY(b.rows - 1)
+	for i := range b.bookmarks {
+		if b.bookmarks[i].Name == name {
+			b.bookmarks[i].BufferY = bufferY
+			return
+		}
+	}
+	b.bookmarks = append(b.bookmarks, Bookmark{Name: name, BufferY: bufferY})
+}
+
+// RemoveBookmark deletes the named bookmark (no-op if there is none)
+func (b *Buffer) RemoveBookmark(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.bookmarks {
+		if b.bookmarks[i].Name == name {
+			b.bookmarks = append(b.bookmarks[:i], b.bookmarks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Bookmarks returns a copy of the bookmarks in the order they were added
+func (b *Buffer) Bookmarks() []Bookmark {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]Bookmark(nil), b.bookmarks...)
+}
+
+// shiftBookmarksInternal moves bookmarks up after n lines are evicted from
+// the front of the scrollback. Like scrollOffset, a bookmark whose line is
+// gone stops at the oldest remaining line.
+func (b *Buffer) shiftBookmarksInternal(n int) {
+	for i := range b.bookmarks {
+		b.bookmarks[i].BufferY -= n
+		if b.bookmarks[i].BufferY < 0 {
+			b.bookmarks[i].BufferY = 0
+		}
+	}
+}
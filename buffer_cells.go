@@ -1,5 +1,7 @@
 package purfecterm
 
+import "strings"
+
 // --- Cell Access Methods ---
 
 // GetCell returns the cell at the given screen position
@@ -32,6 +34,42 @@ func (b *Buffer) getCellInternal(x, y int) Cell {
 	return line[x]
 }
 
+// GetLine returns a copy of logical screen row y, padded to EffectiveCols
+// with the cells GetCell would return past the stored content. Longer rows
+// are returned in full. Returns nil if y is outside the logical screen.
+func (b *Buffer) GetLine(y int) []Cell {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if y < 0 || y >= b.EffectiveRows() {
+		return nil
+	}
+	n := b.EffectiveCols()
+	if y < len(b.screen) {
+		n = max(n, len(b.screen[y]))
+	}
+	line := make([]Cell, n)
+	for x := range line {
+		line[x] = b.getCellInternal(x, y)
+	}
+	return line
+}
+
+// GetScrollbackLine returns a copy of scrollback line index (0 is the
+// oldest), padded to EffectiveCols with the line's default cell. Returns
+// nil if index is out of range.
+func (b *Buffer) GetScrollbackLine(index int) []Cell {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if index < 0 || index >= len(b.scrollback) {
+		return nil
+	}
+	line := make([]Cell, max(b.EffectiveCols(), len(b.scrollback[index])))
+	for x := range line {
+		line[x] = b.getScrollbackCell(x, index)
+	}
+	return line
+}
+
 // GetVisibleCell returns the cell accounting for scroll offset (both vertical and horizontal)
 func (b *Buffer) GetVisibleCell(x, y int) Cell {
 	b.mu.RLock()
@@ -127,6 +165,83 @@ func (b *Buffer) getLogicalCell(x, logicalY int) Cell {
 	return line[x]
 }
 
+// CellAtCursor returns the cell under the cursor
+func (b *Buffer) CellAtCursor() Cell {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.getCellInternal(b.cursorX, b.cursorY)
+}
+
+// CharAtCursor returns the character under the cursor (' ' for blank cells)
+func (b *Buffer) CharAtCursor() rune {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	ch := b.getCellInternal(b.cursorX, b.cursorY).Char
+	if ch == 0 {
+		ch = ' '
+	}
+	return ch
+}
+
+// CurrentLineText returns the text of the logical line the cursor is on,
+// with trailing blanks trimmed
+func (b *Buffer) CurrentLineText() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.cursorY < 0 || b.cursorY >= len(b.screen) {
+		return ""
+	}
+	var sb strings.Builder
+	for _, cell := range b.screen[b.cursorY] {
+		if cell.Continuation {
+			continue
+		}
+		if cell.Char == 0 {
+			sb.WriteByte(' ')
+			continue
+		}
+		sb.WriteString(cell.String())
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// --- Recoloring ---
+
+// RemapColors rewrites the foreground, background and underline colors of
+// every cell on the screen and in scrollback through mapping, along with
+// the default cells used past the end of each line. Use it to apply a new
+// palette to history or invert everything at once; colors that should stay
+// must be returned unchanged. The current text attributes are not touched.
+// mapping runs with the buffer locked and must not call back into it.
+func (b *Buffer) RemapColors(mapping func(Color) Color) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	remap := func(c *Cell) {
+		c.Foreground = mapping(c.Foreground)
+		c.Background = mapping(c.Background)
+		if c.HasUnderlineColor {
+			c.UnderlineColor = mapping(c.UnderlineColor)
+		}
+	}
+	for _, lines := range [][][]Cell{b.screen, b.scrollback} {
+		for _, line := range lines {
+			for x := range line {
+				remap(&line[x])
+			}
+		}
+	}
+	for _, infos := range [][]LineInfo{b.lineInfos, b.scrollbackInfo} {
+		for i := range infos {
+			remap(&infos[i].DefaultCell)
+		}
+	}
+	remap(&b.screenInfo.DefaultCell)
+
+	b.markFullDamage()
+	b.markDirty()
+}
+
 // --- Dirty Flag ---
 
 // IsDirty returns true if the buffer has changed since last render
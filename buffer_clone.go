@@ -0,0 +1,222 @@
+package purfecterm
+
+// --- Buffer Copies ---
+
+// Clone returns an independent deep copy of the buffer: screen, scrollback,
+// line attributes, palettes, glyphs, sprites, crop rectangles, splits,
+// cursor, attributes and modes. The copy shares no slices or maps with b,
+// so either can be changed without affecting the other.
+//
+// Callbacks (dirty, title, cursor, selection, ...) and the paste filter
+// belong to whoever set them and are not copied; the clone starts with
+// none.
+func (b *Buffer) Clone() *Buffer {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	c := &Buffer{
+		cols:        b.cols,
+		rows:        b.rows,
+		logicalCols: b.logicalCols,
+		logicalRows: b.logicalRows,
+
+		cursorX:       b.cursorX,
+		cursorY:       b.cursorY,
+		cursorVisible: b.cursorVisible,
+		cursorShape:   b.cursorShape,
+		cursorBlink:   b.cursorBlink,
+
+		bracketedPasteMode: b.bracketedPasteMode,
+		newlineMode:        b.newlineMode,
+		focusReporting:     b.focusReporting,
+		reverseScreen:      b.reverseScreen,
+
+		paletteOverrides: cloneMap(b.paletteOverrides),
+		dynamicColors:    cloneMap(b.dynamicColors),
+
+		transparentBackground: b.transparentBackground,
+
+		syncOutput:       b.syncOutput,
+		syncOutputStart:  b.syncOutputStart,
+		syncDirtyPending: b.syncDirtyPending,
+
+		mouseTrackingMode: b.mouseTrackingMode,
+		mouseEncodingMode: b.mouseEncodingMode,
+
+		currentFg:                b.currentFg,
+		currentBg:                b.currentBg,
+		currentBold:              b.currentBold,
+		currentItalic:            b.currentItalic,
+		currentUnderline:         b.currentUnderline,
+		currentUnderlineStyle:    b.currentUnderlineStyle,
+		currentUnderlineColor:    b.currentUnderlineColor,
+		currentHasUnderlineColor: b.currentHasUnderlineColor,
+		currentReverse:           b.currentReverse,
+		currentBlink:             b.currentBlink,
+		currentBlinkRapid:        b.currentBlinkRapid,
+		currentStrikethrough:     b.currentStrikethrough,
+		currentFaint:             b.currentFaint,
+		currentConceal:           b.currentConceal,
+		currentOverline:          b.currentOverline,
+		currentFlexWidth:         b.currentFlexWidth,
+
+		flexWidthMode:      b.flexWidthMode,
+		wideCharMode:       b.wideCharMode,
+		visualWidthWrap:    b.visualWidthWrap,
+		ambiguousWidthMode: b.ambiguousWidthMode,
+
+		screen:     cloneLines(b.screen),
+		lineInfos:  append([]LineInfo(nil), b.lineInfos...),
+		screenInfo: b.screenInfo,
+
+		scrollback:          cloneLines(b.scrollback),
+		scrollbackInfo:      append([]LineInfo(nil), b.scrollbackInfo...),
+		maxScrollback:       b.maxScrollback,
+		scrollbackByteLimit: b.scrollbackByteLimit,
+		scrollbackBytes:     b.scrollbackBytes,
+		scrollOffset:        b.scrollOffset,
+		scrollbackDisabled:  b.scrollbackDisabled,
+		scrollLineStep:      b.scrollLineStep,
+		scrollPageStep:      b.scrollPageStep,
+
+		hideScrollbackBoundary: b.hideScrollbackBoundary,
+		horizOffset:            b.horizOffset,
+
+		lastKeyboardActivity: b.lastKeyboardActivity,
+		cursorDrawnLastFrame: b.cursorDrawnLastFrame,
+		lastCursorMoveDir:    b.lastCursorMoveDir,
+		lastManualVertScroll: b.lastManualVertScroll,
+
+		lastHorizCursorMoveDir:  b.lastHorizCursorMoveDir,
+		lastManualHorizScroll:   b.lastManualHorizScroll,
+		lastScrollCausingEvent:  b.lastScrollCausingEvent,
+		horizMemos:              append([]HorizMemo(nil), b.horizMemos...),
+		isAbsoluteHorizPosition: b.isAbsoluteHorizPosition,
+
+		autoScrollDisabled: b.autoScrollDisabled,
+		autoWrapMode:       b.autoWrapMode,
+		smartWordWrap:      b.smartWordWrap,
+		wordWrapBoundaries: append([]rune(nil), b.wordWrapBoundaries...),
+		expandTabs:         b.expandTabs,
+		pendingWrapMode:    b.pendingWrapMode,
+
+		scrollTop:    b.scrollTop,
+		scrollBottom: b.scrollBottom,
+		originMode:   b.originMode,
+
+		selectionActive:  b.selectionActive,
+		selStartX:        b.selStartX,
+		selStartY:        b.selStartY,
+		selEndX:          b.selEndX,
+		selEndY:          b.selEndY,
+		autoCopyOnSelect: b.autoCopyOnSelect,
+
+		searchMatches: append([]Match(nil), b.searchMatches...),
+		bookmarks:     append([]Bookmark(nil), b.bookmarks...),
+
+		resizeAnchor:      b.resizeAnchor,
+		scrollbackEvicted: b.scrollbackEvicted,
+
+		savedCursorX: b.savedCursorX,
+		savedCursorY: b.savedCursorY,
+
+		dirty: b.dirty,
+		title: b.title,
+
+		darkTheme:          b.darkTheme,
+		preferredDarkTheme: b.preferredDarkTheme,
+
+		columnMode132: b.columnMode132,
+		columnMode40:  b.columnMode40,
+		lineDensity:   b.lineDensity,
+
+		currentBGP:   b.currentBGP,
+		currentXFlip: b.currentXFlip,
+		currentYFlip: b.currentYFlip,
+
+		currentFont: b.currentFont,
+		fontSlots:   cloneMap(b.fontSlots),
+		scriptFonts: cloneMap(b.scriptFonts),
+
+		spriteUnitX:  b.spriteUnitX,
+		spriteUnitY:  b.spriteUnitY,
+		hiddenLayers: cloneMap(b.hiddenLayers),
+
+		widthCrop:  b.widthCrop,
+		heightCrop: b.heightCrop,
+
+		splitContentWidth: b.splitContentWidth,
+		splitWarning:      b.LastSplitWarning(),
+
+		damageLast: cloneLines(b.damageLast),
+		damageFull: b.damageFull,
+	}
+
+	if b.palettes != nil {
+		c.palettes = make(map[int]*Palette, len(b.palettes))
+		for n, p := range b.palettes {
+			cp := *p
+			cp.Entries = append([]PaletteEntry(nil), p.Entries...)
+			c.palettes[n] = &cp
+		}
+	}
+	if b.customGlyphs != nil {
+		c.customGlyphs = make(map[rune]*CustomGlyph, len(b.customGlyphs))
+		for r, g := range b.customGlyphs {
+			cg := *g
+			cg.Pixels = append([]int(nil), g.Pixels...)
+			c.customGlyphs[r] = &cg
+		}
+	}
+	if b.sprites != nil {
+		c.sprites = make(map[int]*Sprite, len(b.sprites))
+		for id, s := range b.sprites {
+			cs := *s
+			cs.Runes = make([][]rune, len(s.Runes))
+			for i, row := range s.Runes {
+				cs.Runes[i] = append([]rune(nil), row...)
+			}
+			c.sprites[id] = &cs
+		}
+	}
+	if b.cropRects != nil {
+		c.cropRects = make(map[int]*CropRectangle, len(b.cropRects))
+		for id, r := range b.cropRects {
+			cr := *r
+			c.cropRects[id] = &cr
+		}
+	}
+	if b.screenSplits != nil {
+		c.screenSplits = make(map[int]*ScreenSplit, len(b.screenSplits))
+		for id, s := range b.screenSplits {
+			cs := *s
+			c.screenSplits[id] = &cs
+		}
+	}
+
+	return c
+}
+
+// cloneLines deep-copies a slice of cell lines
+func cloneLines(lines [][]Cell) [][]Cell {
+	if lines == nil {
+		return nil
+	}
+	out := make([][]Cell, len(lines))
+	for i, line := range lines {
+		out[i] = append([]Cell(nil), line...)
+	}
+	return out
+}
+
+// cloneMap copies a map of plain values (nil stays nil)
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	out := make(map[K]V, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
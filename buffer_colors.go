@@ -0,0 +1,109 @@
+package purfecterm
+
+// --- Program-Set Colors (OSC 4/10/11/12 and their resets) ---
+
+// DynamicColor names a scheme color a program can replace at runtime.
+// The values follow xterm's numbering from OSC 10.
+type DynamicColor int
+
+const (
+	DynamicForeground DynamicColor = iota // OSC 10 / 110
+	DynamicBackground                     // OSC 11 / 111
+	DynamicCursor                         // OSC 12 / 112
+)
+
+// SetPaletteColor replaces 256-color palette index (0-255) with c until it
+// is reset, as OSC 4 does. Indices 0-15 also change the ANSI colors.
+// Other indices are ignored.
+func (b *Buffer) SetPaletteColor(index int, c Color) {
+	if index < 0 || index >= 256 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.paletteOverrides == nil {
+		b.paletteOverrides = make(map[int]Color)
+	}
+	b.paletteOverrides[index] = c
+	b.markFullDamage()
+	b.markDirty()
+}
+
+// ResetPaletteColors returns the given ANSI color indexes to the scheme's
+// colors, or all of them when none are given (OSC 104)
+func (b *Buffer) ResetPaletteColors(indexes ...int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(indexes) == 0 {
+		b.paletteOverrides = nil
+	}
+	for _, i := range indexes {
+		delete(b.paletteOverrides, i)
+	}
+	b.markFullDamage()
+	b.markDirty()
+}
+
+// SetDynamicColor replaces the scheme's default foreground, background or
+// cursor color with c until it is reset (OSC 10/11/12)
+func (b *Buffer) SetDynamicColor(which DynamicColor, c Color) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.dynamicColors == nil {
+		b.dynamicColors = make(map[DynamicColor]Color)
+	}
+	b.dynamicColors[which] = c
+	b.markFullDamage()
+	b.markDirty()
+}
+
+// ResetDynamicColor returns a dynamic color to the scheme's (OSC 110/111/112)
+func (b *Buffer) ResetDynamicColor(which DynamicColor) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.dynamicColors, which)
+	b.markFullDamage()
+	b.markDirty()
+}
+
+// applyColorOverridesLocked returns s with the program-set colors in
+// place of the scheme's, in both dark and light modes. s's palettes are
+// copied, not modified. Caller must hold the lock.
+func (b *Buffer) applyColorOverridesLocked(s ColorScheme) ColorScheme {
+	if len(b.paletteOverrides) > 0 {
+		s.DarkPalette = overridePalette(s.DarkPalette, b.paletteOverrides)
+		s.LightPalette = overridePalette(s.LightPalette, b.paletteOverrides)
+	}
+	if c, ok := b.dynamicColors[DynamicForeground]; ok {
+		s.DarkForeground, s.LightForeground = c, c
+	}
+	if c, ok := b.dynamicColors[DynamicBackground]; ok {
+		s.DarkBackground, s.LightBackground = c, c
+	}
+	if c, ok := b.dynamicColors[DynamicCursor]; ok {
+		s.Cursor = c
+	}
+	return s
+}
+
+// overridePalette returns a 16-color copy of palette (filled out from
+// ANSIColors if short) with the overridden entries replaced. An override
+// past index 15 extends the copy to all 256 colors.
+func overridePalette(palette []Color, overrides map[int]Color) []Color {
+	size := 16
+	for i := range overrides {
+		if i >= size {
+			size = 256
+		}
+	}
+	out := make([]Color, size)
+	for i := 16; i < size; i++ {
+		out[i] = PaletteColor(i)
+	}
+	copy(out, ANSIColors)
+	copy(out, palette)
+	for i, c := range overrides {
+		out[i] = c
+	}
+	return out
+}
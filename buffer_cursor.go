@@ -4,18 +4,50 @@ import "time"
 
 // --- Cursor Position Methods ---
 
-// GetCursor returns the current cursor position
+// GetCursor returns the current cursor position. After the last column of
+// a row is written the column is one past the margin, or the last column in
+// pending-wrap mode (see SetPendingWrapMode).
 func (b *Buffer) GetCursor() (x, y int) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	return b.cursorX, b.cursorY
+	return b.wrapPendingColumnInternal(), b.cursorY
 }
 
 // SetCursor sets the cursor position (clamped to valid range)
 func (b *Buffer) SetCursor(x, y int) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	oldX, oldY := b.cursorX, b.cursorY
 	b.setCursorInternal(x, y)
+	b.unlockNotifyCursor(oldX, oldY)
+}
+
+// SetCursorColumn moves the cursor to column col on its current row (CHA,
+// HPA). Like SetCursorVisual, col is a visual column unless flex mode is on.
+func (b *Buffer) SetCursorColumn(col int) {
+	b.mu.Lock()
+	oldX, oldY := b.cursorX, b.cursorY
+	if !b.flexWidthMode {
+		col = b.visualToLogicalLocked(b.cursorY, col)
+	}
+	b.setCursorInternal(col, b.cursorY)
+	b.unlockNotifyCursor(oldX, oldY)
+}
+
+// SetCursorRow moves the cursor to row (0-based, clamped) keeping its
+// column (VPA). The horizontal move direction is left alone.
+func (b *Buffer) SetCursorRow(row int) {
+	b.mu.Lock()
+	oldX, oldY := b.cursorX, b.cursorY
+	if row < 0 {
+		row = 0
+	}
+	if effectiveRows := b.EffectiveRows(); row >= effectiveRows {
+		row = effectiveRows - 1
+	}
+	b.trackCursorYMove(row)
+	b.cursorY = row
+	b.markDirty()
+	b.unlockNotifyCursor(oldX, oldY)
 }
 
 // trackCursorYMove tracks cursor movement direction for auto-scroll.
@@ -78,10 +110,36 @@ func (b *Buffer) IsCursorVisible() bool {
 	return b.cursorVisible
 }
 
-// SetCursorStyle sets the cursor shape and blink mode
+// Cursor blink modes, the blink value of SetCursorStyle/GetCursorStyle
+const (
+	CursorBlinkNone = 0 // Steady: DECSCUSR 2, 4, 6
+	CursorBlinkSlow = 1 // DECSCUSR 0, 1, 3, 5; DECRST 12
+	CursorBlinkFast = 2 // DECSET 12
+)
+
+// CursorBlinkInterval returns how long the cursor stays on (and then off)
+// in a blink mode: 500ms slow, 250ms fast, and 0 for a steady cursor,
+// which widgets keep drawn solid
+func CursorBlinkInterval(blink int) time.Duration {
+	switch blink {
+	case CursorBlinkSlow:
+		return 500 * time.Millisecond
+	case CursorBlinkFast:
+		return 250 * time.Millisecond
+	}
+	return 0
+}
+
+// SetCursorStyle sets the cursor shape and blink mode (CursorBlinkNone,
+// CursorBlinkSlow or CursorBlinkFast; other values are clamped to that range)
 func (b *Buffer) SetCursorStyle(shape, blink int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if blink < CursorBlinkNone {
+		blink = CursorBlinkNone
+	} else if blink > CursorBlinkFast {
+		blink = CursorBlinkFast
+	}
 	b.cursorShape = shape
 	b.cursorBlink = blink
 	b.markDirty()
@@ -107,11 +165,12 @@ func (b *Buffer) SaveCursor() {
 // RestoreCursor restores the saved cursor position
 func (b *Buffer) RestoreCursor() {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	oldX, oldY := b.cursorX, b.cursorY
 	b.cursorX = b.savedCursorX
 	b.trackCursorYMove(b.savedCursorY)
 	b.cursorY = b.savedCursorY
 	b.markDirty()
+	b.unlockNotifyCursor(oldX, oldY)
 }
 
 // --- Cursor Auto-Scroll ---
@@ -292,7 +351,8 @@ func (b *Buffer) IsAutoScrollDisabled() bool {
 // MoveCursorUp moves cursor up n rows
 func (b *Buffer) MoveCursorUp(n int) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	oldX, oldY := b.cursorX, b.cursorY
+	b.settleWrapPendingInternal()
 	newY := b.cursorY - n
 	if newY < 0 {
 		newY = 0
@@ -300,12 +360,14 @@ func (b *Buffer) MoveCursorUp(n int) {
 	b.trackCursorYMove(newY)
 	b.cursorY = newY
 	b.markDirty()
+	b.unlockNotifyCursor(oldX, oldY)
 }
 
 // MoveCursorDown moves cursor down n rows
 func (b *Buffer) MoveCursorDown(n int) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	oldX, oldY := b.cursorX, b.cursorY
+	b.settleWrapPendingInternal()
 	newY := b.cursorY + n
 	effectiveRows := b.EffectiveRows()
 	if newY >= effectiveRows {
@@ -314,12 +376,13 @@ func (b *Buffer) MoveCursorDown(n int) {
 	b.trackCursorYMove(newY)
 	b.cursorY = newY
 	b.markDirty()
+	b.unlockNotifyCursor(oldX, oldY)
 }
 
 // MoveCursorForward moves cursor right n columns (CSI C)
 func (b *Buffer) MoveCursorForward(n int) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	oldX, oldY := b.cursorX, b.cursorY
 	b.setHorizMoveDir(1, false) // Moving right
 	b.cursorX += n
 	effectiveCols := b.EffectiveCols()
@@ -327,16 +390,19 @@ func (b *Buffer) MoveCursorForward(n int) {
 		b.cursorX = effectiveCols - 1
 	}
 	b.markDirty()
+	b.unlockNotifyCursor(oldX, oldY)
 }
 
 // MoveCursorBackward moves cursor left n columns (CSI D)
 func (b *Buffer) MoveCursorBackward(n int) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	oldX, oldY := b.cursorX, b.cursorY
+	b.settleWrapPendingInternal()
 	b.setHorizMoveDir(-1, false) // Moving left
 	b.cursorX -= n
 	if b.cursorX < 0 {
 		b.cursorX = 0
 	}
 	b.markDirty()
+	b.unlockNotifyCursor(oldX, oldY)
 }
@@ -0,0 +1,69 @@
+package purfecterm
+
+// --- Damage Tracking (remote mirroring) ---
+
+// CellSpan is a run of changed cells on one visible row.
+// A span with FullScreen set is a marker meaning the whole visible grid was
+// invalidated (clear, scroll, resize); TakeDamage follows it with one span
+// per row carrying that row's complete contents.
+type CellSpan struct {
+	Row        int
+	StartCol   int
+	Cells      []Cell
+	FullScreen bool
+}
+
+// markFullDamage flags the next TakeDamage to report the whole screen.
+// Must be called with the lock held.
+func (b *Buffer) markFullDamage() {
+	b.damageFull = true
+}
+
+// TakeDamage returns the visible cells that changed since the previous call,
+// diffed against the grid retained from that call, and resets the damage.
+// The first call, and any call after a full-screen event, returns a
+// FullScreen marker followed by every visible row.
+func (b *Buffer) TakeDamage() []CellSpan {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	grid := make([][]Cell, b.rows)
+	for y := 0; y < b.rows; y++ {
+		row := make([]Cell, b.cols)
+		for x := 0; x < b.cols; x++ {
+			row[x] = b.getVisibleCellInternal(x, y)
+		}
+		grid[y] = row
+	}
+
+	full := b.damageFull || len(b.damageLast) != b.rows
+	if !full && b.rows > 0 && len(b.damageLast[0]) != b.cols {
+		full = true
+	}
+
+	var spans []CellSpan
+	if full {
+		spans = append(spans, CellSpan{FullScreen: true})
+		for y, row := range grid {
+			spans = append(spans, CellSpan{Row: y, Cells: append([]Cell(nil), row...)})
+		}
+	} else {
+		for y, row := range grid {
+			prev := b.damageLast[y]
+			start := -1
+			for x := 0; x <= len(row); x++ {
+				changed := x < len(row) && row[x] != prev[x]
+				if changed && start < 0 {
+					start = x
+				} else if !changed && start >= 0 {
+					spans = append(spans, CellSpan{Row: y, StartCol: start, Cells: append([]Cell(nil), row[start:x]...)})
+					start = -1
+				}
+			}
+		}
+	}
+
+	b.damageLast = grid
+	b.damageFull = false
+	return spans
+}
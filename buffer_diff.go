@@ -0,0 +1,67 @@
+package purfecterm
+
+// --- Buffer Comparison ---
+
+// CellDiff is one visible cell where two buffers differ. X and Y are
+// screen coordinates; Got is the receiver's cell and Want the other's.
+type CellDiff struct {
+	X, Y int
+	Got  Cell
+	Want Cell
+}
+
+// visibleCells copies the visible screen, one slice per row
+func (b *Buffer) visibleCells() [][]Cell {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	rows := make([][]Cell, b.rows)
+	for y := range rows {
+		rows[y] = make([]Cell, b.cols)
+		for x := range rows[y] {
+			rows[y][x] = b.getVisibleCellInternal(x, y)
+		}
+	}
+	return rows
+}
+
+// DiffVisible compares the visible screens of b and other cell by cell and
+// returns every difference in row-major order. When the sizes differ, cells
+// that exist in only one buffer are reported against an EmptyCell. Meant for
+// conformance tests that check a screen against a golden buffer.
+func (b *Buffer) DiffVisible(other *Buffer) []CellDiff {
+	// Snapshot each buffer under its own lock so two buffers are never
+	// locked together
+	got := b.visibleCells()
+	want := other.visibleCells()
+
+	cellAt := func(rows [][]Cell, x, y int) Cell {
+		if y < len(rows) && x < len(rows[y]) {
+			return rows[y][x]
+		}
+		return EmptyCell()
+	}
+
+	var diffs []CellDiff
+	height := max(len(got), len(want))
+	for y := 0; y < height; y++ {
+		width := 0
+		if y < len(got) {
+			width = len(got[y])
+		}
+		if y < len(want) {
+			width = max(width, len(want[y]))
+		}
+		for x := 0; x < width; x++ {
+			g, w := cellAt(got, x, y), cellAt(want, x, y)
+			if g != w {
+				diffs = append(diffs, CellDiff{X: x, Y: y, Got: g, Want: w})
+			}
+		}
+	}
+	return diffs
+}
+
+// EqualVisible reports whether b and other show identical visible screens
+func (b *Buffer) EqualVisible(other *Buffer) bool {
+	return len(b.DiffVisible(other)) == 0
+}
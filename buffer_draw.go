@@ -0,0 +1,114 @@
+package purfecterm
+
+// --- Drawing Primitives ---
+
+// BoxStyle selects the box-drawing runes used by DrawBox.
+// The styles mirror the CLI adapter's BorderStyle options.
+type BoxStyle int
+
+const (
+	BoxSingle  BoxStyle = iota // Single-line box drawing characters
+	BoxDouble                  // Double-line box drawing characters
+	BoxHeavy                   // Heavy/thick box drawing characters
+	BoxRounded                 // Rounded corners (single line)
+)
+
+// boxRunes holds the corner and edge runes for one BoxStyle
+type boxRunes struct {
+	topLeft, topRight, bottomLeft, bottomRight rune
+	horizontal, vertical                       rune
+}
+
+var boxStyles = map[BoxStyle]boxRunes{
+	BoxSingle:  {'┌', '┐', '└', '┘', '─', '│'},
+	BoxDouble:  {'╔', '╗', '╚', '╝', '═', '║'},
+	BoxHeavy:   {'┏', '┓', '┗', '┛', '━', '┃'},
+	BoxRounded: {'╭', '╮', '╰', '╯', '─', '│'},
+}
+
+// putCellInternal stores a cell on the logical screen, extending the line
+// as needed. Positions outside the logical screen are ignored.
+// Must be called with the lock held.
+func (b *Buffer) putCellInternal(x, y int, cell Cell) {
+	if x < 0 || y < 0 || x >= b.EffectiveCols() || y >= len(b.screen) {
+		return
+	}
+	b.ensureLineLength(y, x+1)
+	b.screen[y][x] = cell
+}
+
+// SetCell stores cell exactly as given at (x, y) on the logical screen, for
+// compositors that draw without escape sequences. The cursor does not move,
+// nothing wraps, and positions outside the screen are ignored.
+func (b *Buffer) SetCell(x, y int, cell Cell) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.putCellInternal(x, y, cell)
+	b.markDirty()
+}
+
+// SetCellRange stores cells left to right starting at (x, y), like SetCell
+// for each one. The run is clipped at the edges of the screen rather than
+// wrapping onto the next line.
+func (b *Buffer) SetCellRange(x, y int, cells []Cell) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, cell := range cells {
+		b.putCellInternal(x+i, y, cell)
+	}
+	b.markDirty()
+}
+
+// FillRect fills a w×h rectangle at (x, y) on the logical screen with ch,
+// using attrs for every other cell attribute. The cursor does not move and
+// the rectangle is clipped to the screen.
+func (b *Buffer) FillRect(x, y, w, h int, ch rune, attrs Cell) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cell := attrs
+	cell.Char = ch
+	cell.Combining = ""
+	cell.Continuation = false
+	for row := y; row < y+h; row++ {
+		for col := x; col < x+w; col++ {
+			b.putCellInternal(col, row, cell)
+		}
+	}
+	b.markDirty()
+}
+
+// DrawBox draws a w×h box outline at (x, y) on the logical screen in the
+// given style, using the current text attributes. The interior is left
+// untouched and the cursor does not move. Boxes smaller than 2×2 are ignored.
+func (b *Buffer) DrawBox(x, y, w, h int, style BoxStyle) {
+	if w < 2 || h < 2 {
+		return
+	}
+	runes, ok := boxStyles[style]
+	if !ok {
+		runes = boxStyles[BoxSingle]
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cell := b.currentDefaultCell()
+	put := func(col, row int, ch rune) {
+		cell.Char = ch
+		b.putCellInternal(col, row, cell)
+	}
+
+	right, bottom := x+w-1, y+h-1
+	for col := x + 1; col < right; col++ {
+		put(col, y, runes.horizontal)
+		put(col, bottom, runes.horizontal)
+	}
+	for row := y + 1; row < bottom; row++ {
+		put(x, row, runes.vertical)
+		put(right, row, runes.vertical)
+	}
+	put(x, y, runes.topLeft)
+	put(right, y, runes.topRight)
+	put(x, bottom, runes.bottomLeft)
+	put(right, bottom, runes.bottomRight)
+	b.markDirty()
+}
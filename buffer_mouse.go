@@ -29,7 +29,7 @@ const (
 //   - button: button value (MouseButton* constants, with modifier flags ORed in)
 //   - x, y: 1-based cell coordinates
 //   - press: true for press/motion, false for release
-//   - encodingMode: 0 for X10, 1006 for SGR
+//   - encodingMode: 0 for X10, 1006 for SGR, 1015 for urxvt
 //
 // Returns the escape sequence bytes, or nil if the event cannot be encoded.
 func EncodeMouseEvent(button, x, y int, press bool, encodingMode int) []byte {
@@ -41,6 +41,13 @@ func EncodeMouseEvent(button, x, y int, press bool, encodingMode int) []byte {
 		}
 		return []byte(fmt.Sprintf("\x1b[<%d;%d;%d%c", button, x, y, suffix))
 
+	case 1015: // urxvt encoding: ESC [ cb ; x ; y M (decimal, X10 button codes)
+		cb := button + 32
+		if !press {
+			cb = MouseButtonRelease + 32
+		}
+		return []byte(fmt.Sprintf("\x1b[%d;%d;%dM", cb, x, y))
+
 	default: // X10 encoding: ESC [ M cb cx cy
 		cb := button + 32
 		if !press {
@@ -55,3 +62,39 @@ func EncodeMouseEvent(button, x, y int, press bool, encodingMode int) []byte {
 		return []byte{'\x1b', '[', 'M', byte(cb), byte(cx), byte(cy)}
 	}
 }
+
+// MouseMode returns the mouse tracking mode (0, 9, 1000, 1002 or 1003) and
+// encoding mode (0, 1006 or 1015) enabled by the application
+func (b *Buffer) MouseMode() (tracking, encoding int) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.mouseTrackingMode, b.mouseEncodingMode
+}
+
+// EncodeMouseEvent encodes a mouse event for the application using the
+// active tracking and encoding modes. x and y are 1-based cell coordinates.
+// Returns nil when the active tracking mode does not report this event:
+// tracking is off, X10 mode (9) only reports presses without modifiers, and
+// motion is only reported under 1002 (with a button held) or 1003.
+func (b *Buffer) EncodeMouseEvent(button, x, y int, press bool) []byte {
+	tracking, encoding := b.MouseMode()
+	motion := button&MouseMotionFlag != 0
+	switch tracking {
+	case 0:
+		return nil
+	case 9:
+		if !press || motion {
+			return nil
+		}
+		button &^= MouseModShift | MouseModAlt | MouseModControl
+	case 1000:
+		if motion {
+			return nil
+		}
+	case 1002:
+		if motion && button&3 == MouseButtonNone {
+			return nil
+		}
+	}
+	return EncodeMouseEvent(button, x, y, press, encoding)
+}
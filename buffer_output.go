@@ -11,6 +11,34 @@ func (b *Buffer) WriteChar(ch rune) {
 	b.writeCharInternal(ch)
 }
 
+// WriteRun writes a run of characters at the cursor under a single lock
+// acquisition. The result is the same as calling WriteChar for each rune,
+// including wrapping and flex-width handling; the parser uses it for text
+// between control sequences.
+func (b *Buffer) WriteRun(runes []rune) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range runes {
+		b.writeCharInternal(ch)
+	}
+}
+
+// InsertText writes s at the cursor under a single lock acquisition, with
+// the same wrapping, width and combining-mark handling as WriteChar. It
+// never interprets s: control characters (C0, DEL and C1) are skipped
+// rather than acted on, so text from an untrusted source can't move the
+// cursor or start an escape sequence. Invalid UTF-8 is written as U+FFFD.
+func (b *Buffer) InsertText(s string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range s {
+		if ch < 0x20 || (ch >= 0x7F && ch < 0xA0) {
+			continue
+		}
+		b.writeCharInternal(ch)
+	}
+}
+
 // getPreviousCellWidth returns the width of the previous cell for ambiguous auto-matching.
 // If there's no previous cell or it doesn't have FlexWidth set, returns 1.0.
 func (b *Buffer) getPreviousCellWidth() float64 {
@@ -43,6 +71,38 @@ func (b *Buffer) getPreviousCellWidth() float64 {
 	return 1.0 // Default to 1.0 if no valid previous cell
 }
 
+// pastRightMarginInternal reports whether the cursor is past the last
+// column, where writing the last character of a row leaves it, measured the
+// way writeCharInternal decides to wrap
+func (b *Buffer) pastRightMarginInternal() bool {
+	cols := b.EffectiveCols()
+	if b.currentFlexWidth && !b.visualWidthWrap {
+		return b.cursorX >= cols
+	}
+	return b.getLineVisualWidth(b.cursorY, b.cursorX) >= float64(cols)
+}
+
+// wrapPendingColumnInternal returns the logical column the cursor reports
+// in pending-wrap mode: the last column while a wrap is pending, otherwise
+// the cursor's own
+func (b *Buffer) wrapPendingColumnInternal() int {
+	if !b.pendingWrapMode || !b.pastRightMarginInternal() {
+		return b.cursorX
+	}
+	last := b.EffectiveCols() - 1
+	if b.currentFlexWidth && !b.visualWidthWrap {
+		return last
+	}
+	return b.visualToLogicalLocked(b.cursorY, last)
+}
+
+// settleWrapPendingInternal drops a pending wrap before anything but a
+// printable character, in pending-wrap mode: the cursor is then really on
+// the last column, so e.g. BS lands on the column before it
+func (b *Buffer) settleWrapPendingInternal() {
+	b.cursorX = b.wrapPendingColumnInternal()
+}
+
 // getLineVisualWidth calculates the accumulated visual width of a line up to (but not including) col.
 // Returns the sum of CellWidth values for cells 0 to col-1.
 func (b *Buffer) getLineVisualWidth(row, col int) float64 {
@@ -52,11 +112,7 @@ func (b *Buffer) getLineVisualWidth(row, col int) float64 {
 	line := b.screen[row]
 	width := 0.0
 	for i := 0; i < col && i < len(line); i++ {
-		if line[i].CellWidth > 0 {
-			width += line[i].CellWidth
-		} else {
-			width += 1.0 // Default for cells without width set
-		}
+		width += line[i].VisualWidth()
 	}
 	return width
 }
@@ -80,15 +136,15 @@ func (b *Buffer) GetTotalLineVisualWidth(row int) float64 {
 }
 
 func (b *Buffer) writeCharInternal(ch rune) {
-	// Handle combining characters (Hebrew vowel points, diacritics, etc.)
+	// Handle combining characters (Hebrew vowel points, diacritics, etc.) and
+	// zero-width joiners/variation selectors
 	// These should be appended to the previous cell, not placed in a new cell
-	if IsCombiningMark(ch) {
+	if IsCombiningMark(ch) || IsZeroWidth(ch) {
 		b.appendCombiningMark(ch)
 		return
 	}
 
 	effectiveCols := b.EffectiveCols()
-	effectiveRows := b.EffectiveRows()
 
 	// Check if this character has a custom glyph defined
 	hasCustomGlyph := b.customGlyphs[ch] != nil
@@ -146,6 +202,12 @@ func (b *Buffer) writeCharInternal(ch rune) {
 
 	if shouldWrap {
 		if b.autoWrapMode {
+			// Erase fill of the line being wrapped, carried onto the next line
+			fill := b.currentDefaultCell()
+			if b.cursorY < len(b.lineInfos) {
+				fill = b.lineInfos[b.cursorY].DefaultCell
+			}
+
 			// Check for smart word wrap
 			if b.smartWordWrap && b.cursorY < len(b.screen) {
 				line := b.screen[b.cursorY]
@@ -161,30 +223,32 @@ func (b *Buffer) writeCharInternal(ch rune) {
 				}
 
 				// Look backwards for a word boundary character AFTER the leading indent
-				// Word boundaries: space, hyphen, comma, semicolon, emdash (U+2014)
+				// (see SetWordWrapBoundaries)
 				wrapPoint := -1
 				for i := len(line) - 1; i > leadingSpaces; i-- {
-					ch := line[i].Char
-					if ch == ' ' || ch == '-' || ch == ',' || ch == ';' || ch == '—' {
+					if b.isWordWrapBoundaryInternal(line[i].Char) {
 						wrapPoint = i
 						break
 					}
 				}
 
-				// Move to next line
+				// Move to next line, tracking where the wrapped line ends up:
+				// one row up when the region scrolled, or nowhere separate when
+				// the cursor is stuck on the last row below the region
 				b.setHorizMoveDir(-1, false)
-				b.trackCursorYMove(b.cursorY + 1)
-				b.cursorY++
-				if b.cursorY >= effectiveRows {
-					b.scrollUpInternal()
-					b.cursorY = effectiveRows - 1
+				top, bottom := b.scrollRegionInternal()
+				wrappedY := b.cursorY
+				b.indexInternal()
+				switch {
+				case b.cursorY == wrappedY+1:
+				case wrappedY == bottom && top < bottom:
+					wrappedY--
+				default:
+					wrappedY = -1
 				}
 
-				// Ensure screen has enough rows
-				for b.cursorY >= len(b.screen) {
-					b.screen = append(b.screen, b.makeEmptyLine())
-					b.lineInfos = append(b.lineInfos, b.makeDefaultLineInfo())
-				}
+				// Ensures the screen has enough rows
+				b.inheritLineFillInternal(fill)
 
 				// Create indent cells (spaces with default attributes)
 				indentCells := make([]Cell, leadingSpaces)
@@ -196,13 +260,17 @@ func (b *Buffer) writeCharInternal(ch rune) {
 					}
 				}
 
-				if wrapPoint > leadingSpaces && wrapPoint < len(line)-1 {
+				if wrappedY < 0 {
+					// The line could not advance, so overwrite it from the
+					// left margin as a standard wrap would
+					b.cursorX = 0
+				} else if wrapPoint > leadingSpaces && wrapPoint < len(line)-1 {
 					// Found a valid word boundary - move cells after it to new line
 					cellsToMove := make([]Cell, len(line)-wrapPoint-1)
 					copy(cellsToMove, line[wrapPoint+1:])
 
 					// Trim the current line (keep the boundary char)
-					b.screen[b.cursorY-1] = line[:wrapPoint+1]
+					b.screen[wrappedY] = line[:wrapPoint+1]
 
 					// Place indent + moved cells at the start of the new line
 					newLine := append(indentCells, cellsToMove...)
@@ -222,12 +290,8 @@ func (b *Buffer) writeCharInternal(ch rune) {
 				// Standard auto-wrap: move to next line
 				b.setHorizMoveDir(-1, false)
 				b.cursorX = 0
-				b.trackCursorYMove(b.cursorY + 1)
-				b.cursorY++
-				if b.cursorY >= effectiveRows {
-					b.scrollUpInternal()
-					b.cursorY = effectiveRows - 1
-				}
+				b.indexInternal()
+				b.inheritLineFillInternal(fill)
 			}
 		} else {
 			// Auto-wrap disabled (DECAWM off): stay at last column, overwrite character
@@ -241,8 +305,15 @@ func (b *Buffer) writeCharInternal(ch rune) {
 		b.lineInfos = append(b.lineInfos, b.makeDefaultLineInfo())
 	}
 
+	// Wide-char mode stores a wide glyph as two cells (glyph + continuation)
+	widePair := b.wideCharMode && !b.currentFlexWidth && charWidth >= 2.0
+
 	// Ensure line is long enough for the cursor position
-	b.ensureLineLength(b.cursorY, b.cursorX+1)
+	if widePair {
+		b.ensureLineLength(b.cursorY, b.cursorX+2)
+	} else {
+		b.ensureLineLength(b.cursorY, b.cursorX+1)
+	}
 
 	fg := b.currentFg
 	bg := b.currentBg
@@ -255,6 +326,7 @@ func (b *Buffer) writeCharInternal(ch rune) {
 		Foreground:        fg,
 		Background:        bg,
 		Bold:              b.currentBold,
+		Faint:             b.currentFaint,
 		Italic:            b.currentItalic,
 		Underline:         b.currentUnderline,
 		UnderlineStyle:    b.currentUnderlineStyle,
@@ -262,7 +334,10 @@ func (b *Buffer) writeCharInternal(ch rune) {
 		HasUnderlineColor: b.currentHasUnderlineColor,
 		Reverse:           b.currentReverse,
 		Blink:             b.currentBlink,
+		BlinkRapid:        b.currentBlinkRapid,
 		Strikethrough:     b.currentStrikethrough,
+		Overline:          b.currentOverline,
+		Conceal:           b.currentConceal,
 		FlexWidth:         b.currentFlexWidth,
 		BGP:               b.currentBGP,
 		XFlip:             b.currentXFlip,
@@ -273,7 +348,13 @@ func (b *Buffer) writeCharInternal(ch rune) {
 	// Use the calculated charWidth (already accounts for custom glyphs and ambiguous width mode)
 	cell.CellWidth = charWidth
 
-	if !b.currentFlexWidth {
+	if b.wideCharMode && !b.currentFlexWidth {
+		b.breakWidePairLocked(b.cursorY, b.cursorX)
+		if widePair {
+			b.breakWidePairLocked(b.cursorY, b.cursorX+1)
+		}
+	}
+	if !b.currentFlexWidth && !widePair {
 		b.standardOverwriteFixup(b.cursorY, b.cursorX, charWidth)
 	}
 	b.screen[b.cursorY][b.cursorX] = cell
@@ -282,6 +363,15 @@ func (b *Buffer) writeCharInternal(ch rune) {
 		b.setHorizMoveDir(1, false) // Character output moves cursor right
 	}
 	b.cursorX++
+	if widePair {
+		cont := cell
+		cont.Char = 0
+		cont.Combining = ""
+		cont.CellWidth = 0
+		cont.Continuation = true
+		b.screen[b.cursorY][b.cursorX] = cont
+		b.cursorX++
+	}
 	b.markDirty()
 }
 
@@ -313,11 +403,30 @@ func (b *Buffer) appendCombiningMark(ch rune) {
 		return
 	}
 
+	// Attach to a wide glyph rather than its continuation cell
+	if prevX > 0 && b.screen[prevY][prevX].Continuation {
+		prevX--
+	}
+
 	// Append the combining mark to the previous cell
 	b.screen[prevY][prevX].Combining += string(ch)
 	b.markDirty()
 }
 
+// inheritLineFillInternal gives the line the cursor just wrapped onto the
+// erase fill of the line it continues, so a background set by EL keeps
+// running across the wrap instead of stopping mid-line. Lines that already
+// hold content keep their own fill.
+func (b *Buffer) inheritLineFillInternal(fill Cell) {
+	for b.cursorY >= len(b.screen) {
+		b.screen = append(b.screen, b.makeEmptyLine())
+		b.lineInfos = append(b.lineInfos, b.makeDefaultLineInfo())
+	}
+	if len(b.screen[b.cursorY]) == 0 {
+		b.lineInfos[b.cursorY].DefaultCell = fill
+	}
+}
+
 // ensureLineLength ensures a line has at least the specified length,
 // filling gaps with the line's default cell
 func (b *Buffer) ensureLineLength(row, length int) {
@@ -336,6 +445,17 @@ func (b *Buffer) ensureLineLength(row, length int) {
 	} else {
 		fillCell = EmptyCell()
 	}
+	// Reserve the full width on first growth so a line is allocated once
+	// rather than doubling as characters arrive one at a time
+	if cap(line) < length {
+		size := b.EffectiveCols()
+		if size < length {
+			size = length
+		}
+		grown := make([]Cell, len(line), size)
+		copy(grown, line)
+		line = grown
+	}
 	// Extend line
 	for len(line) < length {
 		line = append(line, fillCell)
@@ -350,13 +470,7 @@ func (b *Buffer) Newline() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	b.cursorX = 0
-	b.trackCursorYMove(b.cursorY + 1)
-	b.cursorY++
-	effectiveRows := b.EffectiveRows()
-	if b.cursorY >= effectiveRows {
-		b.scrollUpInternal()
-		b.cursorY = effectiveRows - 1
-	}
+	b.indexInternal()
 	b.markDirty()
 }
 
@@ -369,17 +483,16 @@ func (b *Buffer) CarriageReturn() {
 	b.markDirty()
 }
 
-// LineFeed moves cursor down one line
+// LineFeed moves cursor down one line, scrolling at the bottom of the scroll region
 func (b *Buffer) LineFeed() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.trackCursorYMove(b.cursorY + 1)
-	b.cursorY++
-	effectiveRows := b.EffectiveRows()
-	if b.cursorY >= effectiveRows {
-		b.scrollUpInternal()
-		b.cursorY = effectiveRows - 1
+	b.settleWrapPendingInternal()
+	if b.newlineMode {
+		b.setHorizMoveDir(-1, false) // Moving left
+		b.cursorX = 0
 	}
+	b.indexInternal()
 	b.markDirty()
 }
 
@@ -387,19 +500,36 @@ func (b *Buffer) LineFeed() {
 func (b *Buffer) Tab() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 	b.setHorizMoveDir(1, false) // Moving right
-	b.cursorX = ((b.cursorX / 8) + 1) * 8
+	target := ((b.cursorX / 8) + 1) * 8
 	effectiveCols := b.EffectiveCols()
-	if b.cursorX >= effectiveCols {
-		b.cursorX = effectiveCols - 1
+	if target >= effectiveCols {
+		target = effectiveCols - 1
 	}
+	b.tabToInternal(target, target-b.cursorX)
 	b.markDirty()
 }
 
+// tabToInternal finishes a tab: it moves the cursor to logical column
+// target, or with expand tabs on writes the columns spaces that get it
+// there
+func (b *Buffer) tabToInternal(target, columns int) {
+	if !b.expandTabs {
+		b.cursorX = target
+		return
+	}
+	row := b.cursorY
+	for i := 0; i < columns && b.cursorY == row; i++ {
+		b.writeCharInternal(' ')
+	}
+}
+
 // Backspace moves cursor left one position
 func (b *Buffer) Backspace() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 	b.setHorizMoveDir(-1, false) // Moving left
 	if b.cursorX > 0 {
 		if b.flexWidthMode {
@@ -436,6 +566,7 @@ func (b *Buffer) scrollUpInternal() {
 	// Set direction directly since most cursor movements bypass setCursorInternal
 	b.lastCursorMoveDir = 1 // Down
 
+	b.markFullDamage()
 	b.markDirty()
 }
 
@@ -459,40 +590,75 @@ func (b *Buffer) ScrollDown(n int) {
 		b.screen[0] = b.makeEmptyLine()
 		b.lineInfos[0] = b.makeDefaultLineInfo()
 	}
+	b.markFullDamage()
 	b.markDirty()
 }
 
 // --- Screen Clearing ---
 
-// ClearScreen clears the entire screen and resets view to show top
+// ClearScreen clears the entire screen, homes the cursor and resets view to
+// show top. It is the "clear" action of the widgets; ED (CSI 2 J) uses
+// EraseScreen, which keeps the cursor and view.
 func (b *Buffer) ClearScreen() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.updateScreenInfo() // Update screen default attributes
-	b.initScreen()
+	b.eraseScreenInternal(true)
 
 	// Reset cursor to top-left
 	b.trackCursorYMove(0)
 	b.cursorX = 0
 	b.cursorY = 0
+}
 
-	// Reset scroll to show logical row 0 at the top of the visible area
-	// When logicalRows > rows, we need scrollOffset = logicalHiddenAbove to see row 0
-	effectiveRows := b.EffectiveRows()
-	if effectiveRows > b.rows {
-		b.scrollOffset = effectiveRows - b.rows
-	} else {
-		b.scrollOffset = 0
+// EraseScreen clears every row of the logical screen, as ED 2 does on a
+// real terminal: scroll margins are ignored and the cursor stays where it
+// is. With resetScroll the view also returns to logical row 0; without it
+// a user scrolled into history keeps their place.
+func (b *Buffer) EraseScreen(resetScroll bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.eraseScreenInternal(resetScroll)
+}
+
+func (b *Buffer) eraseScreenInternal(resetScroll bool) {
+	b.updateScreenInfo() // Update screen default attributes
+	b.initScreen()
+
+	if resetScroll {
+		// Reset scroll to show logical row 0 at the top of the visible area
+		// When logicalRows > rows, we need scrollOffset = logicalHiddenAbove to see row 0
+		effectiveRows := b.EffectiveRows()
+		if effectiveRows > b.rows {
+			b.scrollOffset = effectiveRows - b.rows
+		} else {
+			b.scrollOffset = 0
+		}
 	}
 
 	b.markDirty()
 }
 
+// ClearScrollRegion blanks only the rows inside the active scroll region
+// (the whole screen when none is set), leaving rows outside the margins,
+// the cursor and the view untouched
+func (b *Buffer) ClearScrollRegion() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	top, bottom := b.scrollRegionInternal()
+	for y := top; y <= bottom && y < len(b.screen) && y < len(b.lineInfos); y++ {
+		b.screen[y] = b.makeEmptyLine()
+		b.lineInfos[y] = b.makeDefaultLineInfo()
+	}
+	b.markFullDamage()
+	b.markDirty()
+}
+
 // ClearToEndOfLine clears from cursor to end of line
 // This updates the line's default cell and truncates the line at cursor position
 func (b *Buffer) ClearToEndOfLine() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 
 	if b.cursorY >= len(b.screen) {
 		return
@@ -517,6 +683,7 @@ func (b *Buffer) ClearToEndOfLine() {
 func (b *Buffer) ClearToStartOfLine() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 
 	if b.cursorY >= len(b.screen) {
 		return
@@ -620,44 +787,70 @@ func (b *Buffer) ClearToStartOfScreen() {
 
 // --- Line Insert/Delete ---
 
-// InsertLines inserts n blank lines at cursor
+// InsertLines inserts n blank lines at cursor (IL). Lines shift down within
+// the scroll region only; those pushed past the bottom margin are lost. The
+// cursor must be inside the region, otherwise nothing happens.
 func (b *Buffer) InsertLines(n int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	screenLen := len(b.screen)
-	for i := 0; i < n && screenLen > 0; i++ {
-		if b.cursorY < screenLen-1 {
-			copy(b.screen[b.cursorY+1:], b.screen[b.cursorY:screenLen-1])
-			copy(b.lineInfos[b.cursorY+1:], b.lineInfos[b.cursorY:screenLen-1])
-		}
-		b.screen[b.cursorY] = b.makeEmptyLine()
-		b.lineInfos[b.cursorY] = b.makeDefaultLineInfo()
+	top, bottom, ok := b.lineEditSpanInternal()
+	if !ok || n <= 0 {
+		return
+	}
+	n = min(n, bottom-top+1)
+	copy(b.screen[top+n:bottom+1], b.screen[top:bottom+1-n])
+	copy(b.lineInfos[top+n:bottom+1], b.lineInfos[top:bottom+1-n])
+	for y := top; y < top+n; y++ {
+		b.screen[y] = b.makeEmptyLine()
+		b.lineInfos[y] = b.makeDefaultLineInfo()
 	}
+	b.markFullDamage()
 	b.markDirty()
 }
 
-// DeleteLines deletes n lines at cursor
+// DeleteLines deletes n lines at cursor (DL). Lines below shift up within
+// the scroll region only, and blank lines fill in at the bottom margin. The
+// cursor must be inside the region, otherwise nothing happens.
 func (b *Buffer) DeleteLines(n int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	screenLen := len(b.screen)
-	for i := 0; i < n && screenLen > 0; i++ {
-		if b.cursorY < screenLen-1 {
-			copy(b.screen[b.cursorY:], b.screen[b.cursorY+1:])
-			copy(b.lineInfos[b.cursorY:], b.lineInfos[b.cursorY+1:])
-		}
-		b.screen[screenLen-1] = b.makeEmptyLine()
-		b.lineInfos[screenLen-1] = b.makeDefaultLineInfo()
+	top, bottom, ok := b.lineEditSpanInternal()
+	if !ok || n <= 0 {
+		return
+	}
+	n = min(n, bottom-top+1)
+	copy(b.screen[top:bottom+1-n], b.screen[top+n:bottom+1])
+	copy(b.lineInfos[top:bottom+1-n], b.lineInfos[top+n:bottom+1])
+	for y := bottom + 1 - n; y <= bottom; y++ {
+		b.screen[y] = b.makeEmptyLine()
+		b.lineInfos[y] = b.makeDefaultLineInfo()
 	}
+	b.markFullDamage()
 	b.markDirty()
 }
 
+// lineEditSpanInternal returns the rows IL/DL may shift: from the cursor
+// row to the bottom margin. ok is false when the cursor is outside the
+// scroll region.
+func (b *Buffer) lineEditSpanInternal() (top, bottom int, ok bool) {
+	regionTop, regionBottom := b.scrollRegionInternal()
+	if b.cursorY < regionTop || b.cursorY > regionBottom {
+		return 0, 0, false
+	}
+	bottom = min(regionBottom, len(b.screen)-1, len(b.lineInfos)-1)
+	if b.cursorY > bottom {
+		return 0, 0, false
+	}
+	return b.cursorY, bottom, true
+}
+
 // --- Character Insert/Delete ---
 
 // DeleteChars deletes n characters at cursor
 func (b *Buffer) DeleteChars(n int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 
 	if b.cursorY >= len(b.screen) {
 		return
@@ -669,6 +862,16 @@ func (b *Buffer) DeleteChars(n int) {
 		return // Nothing to delete
 	}
 
+	// Never split a wide-char pair: a delete starting on a continuation
+	// takes its glyph too, and one ending on a glyph takes its continuation
+	if line[b.cursorX].Continuation && b.cursorX > 0 {
+		b.cursorX--
+		n++
+	}
+	if end := b.cursorX + n; end < lineLen && line[end].Continuation {
+		n++
+	}
+
 	// Shift characters left
 	if b.cursorX+n < lineLen {
 		copy(line[b.cursorX:], line[b.cursorX+n:])
@@ -684,6 +887,7 @@ func (b *Buffer) DeleteChars(n int) {
 func (b *Buffer) InsertChars(n int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 
 	if b.cursorY >= len(b.screen) {
 		return
@@ -718,6 +922,7 @@ func (b *Buffer) InsertChars(n int) {
 func (b *Buffer) EraseChars(n int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 
 	if b.cursorY >= len(b.screen) {
 		return
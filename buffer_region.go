@@ -0,0 +1,208 @@
+package purfecterm
+
+// --- Scroll Region (DECSTBM) Methods ---
+
+// SetScrollRegion sets the top and bottom scroll margins (0-indexed, inclusive)
+// An invalid region (top >= bottom, or outside the screen) resets to full screen
+func (b *Buffer) SetScrollRegion(top, bottom int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	effectiveRows := b.EffectiveRows()
+	if top < 0 || bottom >= effectiveRows || top >= bottom {
+		b.scrollTop = 0
+		b.scrollBottom = 0
+		return
+	}
+	b.scrollTop = top
+	b.scrollBottom = bottom
+}
+
+// ResetScrollRegion restores the scroll region to the full screen
+func (b *Buffer) ResetScrollRegion() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.scrollTop = 0
+	b.scrollBottom = 0
+}
+
+// GetScrollRegion returns the active scroll margins (0-indexed, inclusive)
+func (b *Buffer) GetScrollRegion() (top, bottom int) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.scrollRegionInternal()
+}
+
+// scrollRegionInternal resolves the scroll margins against the current
+// logical screen height. A region left stale by a resize falls back to the
+// full screen.
+func (b *Buffer) scrollRegionInternal() (top, bottom int) {
+	last := b.EffectiveRows() - 1
+	if b.scrollBottom > last || b.scrollTop >= b.scrollBottom {
+		return 0, last
+	}
+	return b.scrollTop, b.scrollBottom
+}
+
+// isFullScrollRegion returns true if the margins cover the whole screen
+func (b *Buffer) isFullScrollRegion(top, bottom int) bool {
+	return top == 0 && bottom == b.EffectiveRows()-1
+}
+
+// scrollRegionUpInternal scrolls the lines between top and bottom up by one.
+// A full-screen region scrolls normally (the top line goes to scrollback);
+// a partial region discards its top line.
+func (b *Buffer) scrollRegionUpInternal(top, bottom int) {
+	if b.isFullScrollRegion(top, bottom) {
+		b.scrollUpInternal()
+		return
+	}
+	if bottom >= len(b.screen) {
+		bottom = len(b.screen) - 1
+	}
+	if top >= bottom {
+		return
+	}
+	copy(b.screen[top:bottom], b.screen[top+1:bottom+1])
+	copy(b.lineInfos[top:bottom], b.lineInfos[top+1:bottom+1])
+	b.screen[bottom] = b.makeEmptyLine()
+	b.lineInfos[bottom] = b.makeDefaultLineInfo()
+	b.markFullDamage()
+	b.markDirty()
+}
+
+// scrollRegionDownInternal scrolls the lines between top and bottom down by
+// one, discarding the bottom line and inserting a blank line at top.
+func (b *Buffer) scrollRegionDownInternal(top, bottom int) {
+	if bottom >= len(b.screen) {
+		bottom = len(b.screen) - 1
+	}
+	if top >= bottom {
+		return
+	}
+	copy(b.screen[top+1:bottom+1], b.screen[top:bottom])
+	copy(b.lineInfos[top+1:bottom+1], b.lineInfos[top:bottom])
+	b.screen[top] = b.makeEmptyLine()
+	b.lineInfos[top] = b.makeDefaultLineInfo()
+	b.markFullDamage()
+	b.markDirty()
+}
+
+// indexInternal moves the cursor down one line, scrolling the region up
+// when the cursor is on the bottom margin
+func (b *Buffer) indexInternal() {
+	top, bottom := b.scrollRegionInternal()
+	if b.cursorY == bottom {
+		b.scrollRegionUpInternal(top, bottom)
+		return
+	}
+	if b.cursorY < b.EffectiveRows()-1 {
+		b.trackCursorYMove(b.cursorY + 1)
+		b.cursorY++
+	}
+}
+
+// reverseIndexInternal moves the cursor up one line, scrolling the region
+// down when the cursor is on the top margin
+func (b *Buffer) reverseIndexInternal() {
+	top, bottom := b.scrollRegionInternal()
+	if b.cursorY == top {
+		b.scrollRegionDownInternal(top, bottom)
+		return
+	}
+	if b.cursorY > 0 {
+		b.trackCursorYMove(b.cursorY - 1)
+		b.cursorY--
+	}
+}
+
+// Index moves the cursor down one line within the scroll region (IND)
+func (b *Buffer) Index() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
+	b.indexInternal()
+	b.markDirty()
+}
+
+// ReverseIndex moves the cursor up one line within the scroll region (RI)
+func (b *Buffer) ReverseIndex() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
+	b.reverseIndexInternal()
+	b.markDirty()
+}
+
+// NextLine moves the cursor to the start of the next line within the scroll region (NEL)
+func (b *Buffer) NextLine() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setHorizMoveDir(-1, false) // Moving left
+	b.cursorX = 0
+	b.indexInternal()
+	b.markDirty()
+}
+
+// SetOriginMode sets DECOM. While it is on, rows given to CUP/VPA count
+// from the top margin and stay inside the scroll region, and cursor reports
+// are relative to the top margin. Either way the cursor moves home.
+func (b *Buffer) SetOriginMode(enabled bool) {
+	b.mu.Lock()
+	oldX, oldY := b.cursorX, b.cursorY
+	was := b.originMode
+	b.originMode = enabled
+	b.setCursorInternal(0, b.originRowInternal(0))
+	x, y, onCursor, onMode := b.cursorX, b.cursorY, b.onCursorMove, b.onModeChange
+	b.mu.Unlock()
+	if onCursor != nil && (x != oldX || y != oldY) {
+		onCursor(x, y)
+	}
+	if onMode != nil && was != enabled {
+		onMode("origin", enabled)
+	}
+}
+
+// IsOriginMode returns whether DECOM is on
+func (b *Buffer) IsOriginMode() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.originMode
+}
+
+// originRow maps a 0-based row from a CUP or VPA to a screen row,
+// honoring origin mode
+func (b *Buffer) originRow(row int) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.originRowInternal(row)
+}
+
+// originRowInternal is originRow with the lock already held
+func (b *Buffer) originRowInternal(row int) int {
+	if !b.originMode {
+		return row
+	}
+	top, bottom := b.scrollRegionInternal()
+	return min(top+max(row, 0), bottom)
+}
+
+// ReportedCursorPosition returns the cursor position as a hosted program
+// should be told it (DSR CPR and similar replies): 1-based, with the row
+// relative to the top margin under origin mode, and the column a visual
+// column unless flex mode has the program addressing logical cells. A
+// pending wrap in pending-wrap mode reports the last column, as GetCursor
+// does.
+func (b *Buffer) ReportedCursorPosition() (row, col int) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	row = b.cursorY
+	if b.originMode {
+		top, _ := b.scrollRegionInternal()
+		row -= top
+	}
+	col = b.wrapPendingColumnInternal()
+	if !b.flexWidthMode {
+		col = b.logicalToVisualLocked(b.cursorY, col)
+	}
+	return row + 1, col + 1
+}
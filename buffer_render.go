@@ -0,0 +1,91 @@
+package purfecterm
+
+import "strings"
+
+// --- Headless Rendering ---
+
+// RenderPlain returns the visible screen (respecting scroll offsets) as
+// newline-separated rows with trailing spaces trimmed. It is meant for tests
+// and text screenshots: what a user sees, without attributes.
+func (b *Buffer) RenderPlain() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.renderPlainInternal(-1, -1, false)
+}
+
+// VisibleText returns exactly what is on screen as text: like RenderPlain
+// (scroll offset, horizontal offset, trailing blanks trimmed), but cut to
+// the screen crop and to the visible half of double-width and
+// double-height lines. Rows and columns the crop hides entirely are left
+// out; a partly cropped cell is kept.
+func (b *Buffer) VisibleText() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.renderPlainInternal(-1, -1, true)
+}
+
+// RenderWithCursor is like RenderPlain but wraps the cursor cell in square
+// brackets (e.g. "ab[c]"), so text after it on that row shifts right by two.
+// Nothing is marked when the cursor is scrolled out of view.
+func (b *Buffer) RenderWithCursor() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	effectiveRows := b.EffectiveRows()
+	logicalHiddenAbove := 0
+	if effectiveRows > b.rows {
+		logicalHiddenAbove = effectiveRows - b.rows
+	}
+	cx := b.cursorX - b.horizOffset
+	cy := b.cursorY - logicalHiddenAbove + b.getEffectiveScrollOffset()
+	return b.renderPlainInternal(cx, cy, false)
+}
+
+// renderPlainInternal renders the visible grid, marking the cell at
+// (cursorX, cursorY) when it is on screen. With clip, only what the screen
+// crop and doubled lines leave visible is rendered. Must be called with
+// the lock held.
+func (b *Buffer) renderPlainInternal(cursorX, cursorY int, clip bool) string {
+	rows, cols := b.rows, b.cols
+	if clip {
+		if b.heightCrop > 0 && b.spriteUnitY > 0 {
+			rows = min(rows, (b.heightCrop+b.spriteUnitY-1)/b.spriteUnitY)
+		}
+		if b.widthCrop > 0 && b.spriteUnitX > 0 {
+			cols = min(cols, (b.widthCrop+b.spriteUnitX-1)/b.spriteUnitX)
+		}
+	}
+
+	var out strings.Builder
+	for y := 0; y < rows; y++ {
+		lineCols := cols
+		if clip && b.getVisibleLineInfoInternal(y).Attribute != LineAttrNormal {
+			// Each cell of a doubled line covers two columns; renderers
+			// draw b.cols/2 of them, and a crop can cut the last in half
+			lineCols = b.cols / 2
+			if cols < b.cols {
+				lineCols = (cols + 1) / 2
+			}
+		}
+		var line strings.Builder
+		for x := 0; x < lineCols; x++ {
+			cell := b.getVisibleCellInternal(x, y)
+			if cell.Continuation {
+				continue
+			}
+			text := " "
+			if cell.Char != 0 && !cell.Conceal {
+				text = cell.String()
+			}
+			if x == cursorX && y == cursorY {
+				text = "[" + text + "]"
+			}
+			line.WriteString(text)
+		}
+		if y > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(strings.TrimRight(line.String(), " "))
+	}
+	return out.String()
+}
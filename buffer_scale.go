@@ -6,9 +6,10 @@ package purfecterm
 // This corresponds to DECCOLM (ESC [ ? 3 h / ESC [ ? 3 l)
 func (b *Buffer) Set132ColumnMode(enabled bool) {
 	b.mu.Lock()
+	was := b.columnMode132
 	b.columnMode132 = enabled
 	b.markDirty()
-	b.mu.Unlock()
+	b.unlockNotifyMode("132column", was, enabled)
 	b.notifyScaleChange()
 }
 
@@ -23,9 +24,10 @@ func (b *Buffer) Get132ColumnMode() bool {
 // This is a custom extension
 func (b *Buffer) Set40ColumnMode(enabled bool) {
 	b.mu.Lock()
+	was := b.columnMode40
 	b.columnMode40 = enabled
 	b.markDirty()
-	b.mu.Unlock()
+	b.unlockNotifyMode("40column", was, enabled)
 	b.notifyScaleChange()
 }
 
@@ -46,10 +46,43 @@ func (b *Buffer) getMaxScrollOffsetInternal() int {
 	return baseMax
 }
 
+// scrollOffsetForRowInternal returns the scroll offset that puts the
+// buffer-absolute row bufferY on visible row screenY (the inverse of
+// bufferToScreenY), clamped to the scrollable range
+func (b *Buffer) scrollOffsetForRowInternal(bufferY, screenY int) int {
+	effectiveRows := b.EffectiveRows()
+	logicalHiddenAbove := 0
+	if effectiveRows > b.rows {
+		logicalHiddenAbove = effectiveRows - b.rows
+	}
+	totalScrollableAbove := len(b.scrollback) + logicalHiddenAbove
+
+	// Effective offset that puts bufferY on screenY, then undo the magnetic
+	// zone adjustment made by getEffectiveScrollOffset
+	offset := totalScrollableAbove - bufferY + screenY
+	if offset > logicalHiddenAbove {
+		offset += b.getMagneticThreshold()
+	}
+
+	maxOffset := b.getMaxScrollOffsetInternal()
+	if offset > maxOffset {
+		offset = maxOffset
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
+
 // SetScrollOffset sets how many lines we're scrolled back
 func (b *Buffer) SetScrollOffset(offset int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.setScrollOffsetInternal(offset)
+}
+
+// setScrollOffsetInternal is SetScrollOffset with the lock already held
+func (b *Buffer) setScrollOffsetInternal(offset int) {
 	maxOffset := b.getMaxScrollOffsetInternal()
 	if offset < 0 {
 		offset = 0
@@ -57,6 +90,9 @@ func (b *Buffer) SetScrollOffset(offset int) {
 	if offset > maxOffset {
 		offset = maxOffset
 	}
+	if offset != b.scrollOffset {
+		b.markFullDamage()
+	}
 	b.scrollOffset = offset
 	b.markDirty()
 }
@@ -68,6 +104,85 @@ func (b *Buffer) GetScrollOffset() int {
 	return b.scrollOffset
 }
 
+// ScrollViewWheel scrolls the view for mouse wheel notches that aren't
+// forwarded to the program: positive notches move back into the
+// scrollback, negative ones toward the live screen, each by the line step
+// (see SetScrollLineStep). Scrolling down into the magnetic zone snaps to
+// the boundary, and the scroll counts as manual (see NotifyManualVertScroll).
+func (b *Buffer) ScrollViewWheel(notches int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setScrollOffsetInternal(b.scrollOffset + notches*b.scrollLineStepInternal())
+	if notches < 0 {
+		b.normalizeScrollOffsetInternal()
+	}
+	b.lastManualVertScroll = time.Now()
+}
+
+// ScrollViewPages scrolls the view by whole pages (see SetScrollPageStep):
+// positive back into the scrollback, negative toward the live screen
+func (b *Buffer) ScrollViewPages(pages int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setScrollOffsetInternal(b.scrollOffset + pages*b.scrollPageStepInternal())
+}
+
+// SetScrollLineStep sets how many lines a single mouse wheel notch scrolls.
+// Zero or negative restores the default of 3 lines.
+func (b *Buffer) SetScrollLineStep(lines int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lines < 0 {
+		lines = 0
+	}
+	b.scrollLineStep = lines
+}
+
+// GetScrollLineStep returns how many lines a single mouse wheel notch scrolls
+func (b *Buffer) GetScrollLineStep() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.scrollLineStepInternal()
+}
+
+// scrollLineStepInternal is GetScrollLineStep with the lock already held
+func (b *Buffer) scrollLineStepInternal() int {
+	if b.scrollLineStep <= 0 {
+		return defaultScrollLineStep
+	}
+	return b.scrollLineStep
+}
+
+// SetScrollPageStep sets how many lines a page scroll (Shift+PageUp/PageDown)
+// moves. Zero or negative restores the default of one screen minus a line.
+func (b *Buffer) SetScrollPageStep(lines int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lines < 0 {
+		lines = 0
+	}
+	b.scrollPageStep = lines
+}
+
+// GetScrollPageStep returns how many lines a page scroll moves.
+// Defaults to rows-1 so one line of context stays visible.
+func (b *Buffer) GetScrollPageStep() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.scrollPageStepInternal()
+}
+
+// scrollPageStepInternal is GetScrollPageStep with the lock already held
+func (b *Buffer) scrollPageStepInternal() int {
+	if b.scrollPageStep > 0 {
+		return b.scrollPageStep
+	}
+	if b.rows > 1 {
+		return b.rows - 1
+	}
+	return 1
+}
+
 // GetEffectiveScrollOffset returns the scroll offset adjusted for the magnetic zone.
 // Use this for rendering sprites, splits, and other positioned elements that should
 // remain stable during the magnetic zone.
@@ -85,7 +200,12 @@ func (b *Buffer) GetEffectiveScrollOffset() int {
 func (b *Buffer) NormalizeScrollOffset() bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	return b.normalizeScrollOffsetInternal()
+}
 
+// normalizeScrollOffsetInternal is NormalizeScrollOffset with the lock
+// already held
+func (b *Buffer) normalizeScrollOffsetInternal() bool {
 	effectiveRows := b.EffectiveRows()
 
 	// Calculate how much of the logical screen is hidden above
@@ -108,6 +228,26 @@ func (b *Buffer) NormalizeScrollOffset() bool {
 	return false
 }
 
+// SetShowScrollbackBoundary sets whether renderers draw the dashed line
+// between scrollback and the logical screen (shown by default)
+func (b *Buffer) SetShowScrollbackBoundary(show bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.hideScrollbackBoundary != !show {
+		b.hideScrollbackBoundary = !show
+		b.markFullDamage()
+		b.markDirty()
+	}
+}
+
+// GetShowScrollbackBoundary returns whether the scrollback boundary line
+// should be drawn
+func (b *Buffer) GetShowScrollbackBoundary() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return !b.hideScrollbackBoundary
+}
+
 // GetScrollbackBoundaryVisibleRow returns the visible row (0-indexed from top of display)
 // where the boundary between scrollback and logical screen is located.
 // Returns -1 if the boundary is not currently visible (either fully in scrollback or fully in logical screen).
@@ -116,12 +256,33 @@ func (b *Buffer) NormalizeScrollOffset() bool {
 func (b *Buffer) GetScrollbackBoundaryVisibleRow() int {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
+	return b.scrollBoundaryInternal().VisibleRow
+}
 
-	scrollbackSize := len(b.scrollback)
+// ScrollBoundary describes the line between scrollback and the logical
+// screen as the view scrolls back, for renderers that want more than
+// GetScrollbackBoundaryVisibleRow (e.g. a faint line that strengthens
+// while the view is held in the magnetic zone).
+type ScrollBoundary struct {
+	VisibleRow       int     // Screen row of the boundary line, or -1 when not shown
+	InMagneticZone   bool    // Scrolled back, but the view is still held at the logical screen
+	MagneticProgress float64 // How far through the magnetic zone: 0 before it, rising to 1 at its end and past it
+}
+
+// ScrollBoundaryState returns the scrollback boundary's position and how
+// far the view has scrolled into the magnetic zone
+func (b *Buffer) ScrollBoundaryState() ScrollBoundary {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.scrollBoundaryInternal()
+}
+
+func (b *Buffer) scrollBoundaryInternal() ScrollBoundary {
+	none := ScrollBoundary{VisibleRow: -1}
 
 	// If no scrollback, no boundary to show
-	if scrollbackSize == 0 {
-		return -1
+	if len(b.scrollback) == 0 {
+		return none
 	}
 
 	effectiveRows := b.EffectiveRows()
@@ -137,14 +298,18 @@ func (b *Buffer) GetScrollbackBoundaryVisibleRow() int {
 
 	// Boundary at or below row 0 means we're viewing logical screen only
 	if boundaryRow <= 0 {
-		return -1
+		return none
 	}
 
 	// Magnetic zone: suppress boundary when it would appear in the first few rows
 	// This creates the "sticky" feel at the transition from logical screen to scrollback
 	magneticThreshold := b.getMagneticThreshold()
 	if boundaryRow <= magneticThreshold {
-		return -1
+		return ScrollBoundary{
+			VisibleRow:       -1,
+			InMagneticZone:   true,
+			MagneticProgress: float64(boundaryRow) / float64(magneticThreshold),
+		}
 	}
 
 	// Past magnetic zone - subtract threshold so boundary position matches content
@@ -153,16 +318,17 @@ func (b *Buffer) GetScrollbackBoundaryVisibleRow() int {
 	// Check effective boundary is in visible range (1 to rows-1)
 	// Row 0 would mean boundary at very top (no scrollback visible)
 	// Row >= rows would mean boundary below visible area
-	if effectiveBoundaryRow <= 0 || effectiveBoundaryRow >= b.rows {
-		return -1
+	past := ScrollBoundary{VisibleRow: -1, MagneticProgress: 1}
+	if effectiveBoundaryRow > 0 && effectiveBoundaryRow < b.rows {
+		past.VisibleRow = effectiveBoundaryRow
 	}
-
-	return effectiveBoundaryRow
+	return past
 }
 
 // GetCursorVisiblePosition returns the visible (x, y) position of the cursor
 // accounting for scroll offset and magnetic zone. Returns (-1, -1) if the cursor
-// is not currently visible.
+// is not currently visible. A cursor on a wide-char continuation cell is
+// reported on the glyph that covers it, since renderers skip continuations.
 func (b *Buffer) GetCursorVisiblePosition() (x, y int) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -187,8 +353,16 @@ func (b *Buffer) GetCursorVisiblePosition() (x, y int) {
 		return -1, -1
 	}
 
+	cursorX := b.wrapPendingColumnInternal()
+	if b.cursorY < len(b.screen) {
+		line := b.screen[b.cursorY]
+		for cursorX > 0 && cursorX < len(line) && line[cursorX].Continuation {
+			cursorX--
+		}
+	}
+
 	// X position needs to account for horizontal scroll
-	visibleX := b.cursorX - b.horizOffset
+	visibleX := cursorX - b.horizOffset
 
 	// Check if cursor is within visible horizontal area
 	if visibleX < 0 || visibleX >= b.cols {
@@ -521,11 +695,22 @@ func (b *Buffer) getMaxHorizOffsetInternal() int {
 		longest = splitWidth
 	}
 
+	return horizScrollRange(longest, cols, currentOffset)
+}
+
+// horizScrollRange computes the maximum horizontal scroll offset.
+// contentBasedMax is how far the widest content (longest line or split
+// content) extends past the visible columns, so that scrolling fully right
+// lines up its last column with the right edge. The current offset is kept
+// as a valid maximum even when it exceeds contentBasedMax: scrolling
+// vertically from wide scrollback to a narrower logical screen must not snap
+// the view left. Once the user scrolls left past contentBasedMax, they can't
+// scroll right again.
+func horizScrollRange(longest, cols, currentOffset int) int {
 	contentBasedMax := 0
 	if longest > cols {
 		contentBasedMax = longest - cols
 	}
-
 	if currentOffset > contentBasedMax {
 		return currentOffset
 	}
@@ -652,16 +837,5 @@ func (b *Buffer) GetMaxHorizOffset() int {
 		longest = splitWidth
 	}
 
-	contentBasedMax := 0
-	if longest > cols {
-		contentBasedMax = longest - cols
-	}
-
-	// Preserve current scroll position as valid - don't snap left when
-	// scrolling vertically from wide scrollback to narrower logical screen.
-	// Once user scrolls left past contentBasedMax, they can't scroll right again.
-	if currentOffset > contentBasedMax {
-		return currentOffset
-	}
-	return contentBasedMax
+	return horizScrollRange(longest, cols, currentOffset)
 }
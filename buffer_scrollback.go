@@ -2,8 +2,10 @@ package purfecterm
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // --- Scrollback Management Methods ---
@@ -12,8 +14,10 @@ import (
 func (b *Buffer) ClearScrollback() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.shiftBookmarksInternal(len(b.scrollback))
 	b.scrollback = nil
 	b.scrollbackInfo = nil
+	b.scrollbackBytes = 0
 	b.scrollOffset = 0
 	b.markDirty()
 }
@@ -47,17 +51,30 @@ func (b *Buffer) Reset() {
 	b.currentFg = DefaultForeground
 	b.currentBg = DefaultBackground
 	b.currentBold = false
+	b.currentFaint = false
 	b.currentItalic = false
 	b.currentUnderline = false
 	b.currentReverse = false
 	b.currentBlink = false
+	b.currentBlinkRapid = false
 	b.currentStrikethrough = false
+	b.currentOverline = false
+	b.currentConceal = false
 	b.currentFlexWidth = false
 
 	// Reset modes
 	b.bracketedPasteMode = false
+	b.newlineMode = false
+	b.focusReporting = false
+	b.reverseScreen = false
+	b.paletteOverrides = nil
+	b.dynamicColors = nil
+	b.stopSyncOutputTimerInternal()
+	b.syncOutput = false
+	b.syncDirtyPending = false
 	b.mouseTrackingMode = 0
 	b.mouseEncodingMode = 0
+	b.originMode = false
 	b.flexWidthMode = false
 	b.visualWidthWrap = false
 	b.ambiguousWidthMode = AmbiguousWidthAuto
@@ -68,6 +85,8 @@ func (b *Buffer) Reset() {
 	b.columnMode132 = false
 	b.columnMode40 = false
 	b.lineDensity = 25
+	b.scrollTop = 0
+	b.scrollBottom = 0
 
 	// Reset theme to user preference
 	themeChanged := b.darkTheme != b.preferredDarkTheme
@@ -89,6 +108,36 @@ func (b *Buffer) Reset() {
 	}
 }
 
+// SoftReset performs a DECSTR soft terminal reset: text attributes return
+// to default, auto-wrap is enabled, the scroll region covers the full screen,
+// origin mode is off, the cursor is shown with its default style and the
+// saved cursor returns to home. Screen content, scrollback and the cursor
+// position are kept.
+func (b *Buffer) SoftReset() {
+	b.mu.Lock()
+
+	b.resetAttributesInternal()
+	b.currentFlexWidth = b.flexWidthMode
+	b.currentBGP = -1
+	b.currentXFlip = false
+	b.currentYFlip = false
+
+	wasAutoWrap := b.autoWrapMode
+	b.autoWrapMode = true
+	b.scrollTop = 0
+	b.scrollBottom = 0
+	b.originMode = false
+
+	b.cursorVisible = true
+	b.cursorShape = 0
+	b.cursorBlink = 0
+	b.savedCursorX = 0
+	b.savedCursorY = 0
+
+	b.markDirty()
+	b.unlockNotifyMode("autowrap", wasAutoWrap, true)
+}
+
 // SaveScrollbackText returns the scrollback and screen content as plain text
 func (b *Buffer) SaveScrollbackText() string {
 	b.mu.RLock()
@@ -119,6 +168,62 @@ func (b *Buffer) SaveScrollbackText() string {
 	return result.String()
 }
 
+// ScrollbackReader returns a reader yielding the same text as
+// SaveScrollbackText (scrollback, then screen, one line per newline) without
+// building it all in memory. Lines are fetched one at a time, each under a
+// short read lock, so output written meanwhile can shift which lines a
+// reader sees; read a quiet buffer (or a Clone) for an exact snapshot.
+func (b *Buffer) ScrollbackReader() io.Reader {
+	return &scrollbackReader{b: b}
+}
+
+// scrollbackReader streams lines for ScrollbackReader
+type scrollbackReader struct {
+	b       *Buffer
+	next    int    // Index of the next line, scrollback first, then screen
+	pending []byte // Unread text of the current line
+	buf     []byte // Storage reused across lines
+}
+
+func (r *scrollbackReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(r.pending) == 0 {
+		line, ok := r.b.plainLineText(r.next, r.buf[:0])
+		if !ok {
+			return 0, io.EOF
+		}
+		r.next++
+		r.buf, r.pending = line, line
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// plainLineText appends line i of scrollback+screen as text with a trailing
+// newline to dst, reporting false past the last line
+func (b *Buffer) plainLineText(i int, dst []byte) ([]byte, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var line []Cell
+	if i < len(b.scrollback) {
+		line = b.scrollback[i]
+	} else if i -= len(b.scrollback); i < len(b.screen) {
+		line = b.screen[i]
+	} else {
+		return dst, false
+	}
+	for _, cell := range line {
+		if cell.Char != 0 {
+			dst = utf8.AppendRune(dst, cell.Char)
+		}
+	}
+	return append(dst, '\n'), true
+}
+
 // SaveScrollbackANS returns the scrollback and screen with full ANSI/PawScript codes preserved.
 // The output format:
 // 1. TOP: Custom palette definitions (OSC 7000), custom glyph definitions (OSC 7001)
@@ -214,7 +319,7 @@ func (b *Buffer) SaveScrollbackANS() string {
 
 	// Track current attributes to minimize escape sequences
 	var lastFg, lastBg Color
-	var lastBold, lastItalic, lastUnderline, lastReverse, lastBlink, lastStrikethrough bool
+	var lastBold, lastItalic, lastUnderline, lastReverse, lastBlink, lastStrikethrough, lastOverline, lastFaint, lastConceal, lastBlinkRapid bool
 	var lastFlexWidth bool // Track flex width mode state
 	var lastAmbiguousWide bool                                // Track if ambiguous width is set to wide
 	var lastBGP int = -1
@@ -254,7 +359,10 @@ func (b *Buffer) SaveScrollbackANS() string {
 			needsReset := false
 			if cell.Bold != lastBold || cell.Italic != lastItalic ||
 				cell.Underline != lastUnderline || cell.Reverse != lastReverse ||
-				cell.Blink != lastBlink || cell.Strikethrough != lastStrikethrough {
+				cell.Blink != lastBlink || cell.BlinkRapid != lastBlinkRapid ||
+				cell.Strikethrough != lastStrikethrough ||
+				cell.Overline != lastOverline || cell.Faint != lastFaint ||
+				cell.Conceal != lastConceal {
 				needsReset = true
 			}
 
@@ -268,6 +376,10 @@ func (b *Buffer) SaveScrollbackANS() string {
 				lastReverse = false
 				lastBlink = false
 				lastStrikethrough = false
+				lastOverline = false
+				lastFaint = false
+				lastConceal = false
+				lastBlinkRapid = false
 				// Reset doesn't affect BGP/flip, but we track them separately
 			}
 
@@ -276,6 +388,10 @@ func (b *Buffer) SaveScrollbackANS() string {
 				result.WriteString("\x1b[1m")
 				lastBold = true
 			}
+			if cell.Faint && !lastFaint {
+				result.WriteString("\x1b[2m")
+				lastFaint = true
+			}
 			if cell.Italic && !lastItalic {
 				result.WriteString("\x1b[3m")
 				lastItalic = true
@@ -289,13 +405,26 @@ func (b *Buffer) SaveScrollbackANS() string {
 				lastReverse = true
 			}
 			if cell.Blink && !lastBlink {
-				result.WriteString("\x1b[5m")
+				if cell.BlinkRapid {
+					result.WriteString("\x1b[6m")
+					lastBlinkRapid = true
+				} else {
+					result.WriteString("\x1b[5m")
+				}
 				lastBlink = true
 			}
 			if cell.Strikethrough && !lastStrikethrough {
 				result.WriteString("\x1b[9m")
 				lastStrikethrough = true
 			}
+			if cell.Conceal && !lastConceal {
+				result.WriteString("\x1b[8m")
+				lastConceal = true
+			}
+			if cell.Overline && !lastOverline {
+				result.WriteString("\x1b[53m")
+				lastOverline = true
+			}
 
 			// Set colors
 			if cell.Foreground != lastFg {
@@ -386,6 +515,10 @@ func (b *Buffer) SaveScrollbackANS() string {
 		lastReverse = false
 		lastBlink = false
 		lastStrikethrough = false
+		lastOverline = false
+		lastFaint = false
+		lastConceal = false
+		lastBlinkRapid = false
 
 		// If background was dirty, clear the next line to prevent bleeding
 		if hasNonDefaultBg {
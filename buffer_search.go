@@ -0,0 +1,95 @@
+package purfecterm
+
+// --- Search Highlight Methods ---
+
+// Match is a run of cells on one line found by a search.
+// Row is buffer-absolute like selection coordinates (0 = oldest scrollback
+// line); StartCol and EndCol are inclusive.
+type Match struct {
+	Row      int
+	StartCol int
+	EndCol   int
+}
+
+// contains reports whether the buffer-absolute cell (x, bufferY) is in m
+func (m Match) contains(x, bufferY int) bool {
+	return bufferY == m.Row && x >= m.StartCol && x <= m.EndCol
+}
+
+// SetSearchMatches stores the ranges to draw with the scheme's
+// SearchHighlight background, replacing any previous matches.
+func (b *Buffer) SetSearchMatches(matches []Match) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.searchMatches = append([]Match(nil), matches...)
+	b.markFullDamage()
+	b.markDirty()
+}
+
+// ClearSearchMatches removes all search highlights
+func (b *Buffer) ClearSearchMatches() {
+	b.SetSearchMatches(nil)
+}
+
+// GetSearchMatches returns a copy of the stored search matches
+func (b *Buffer) GetSearchMatches() []Match {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]Match(nil), b.searchMatches...)
+}
+
+// IsCellInSearchHighlight checks if a cell at screen coordinates is part of
+// a stored search match
+func (b *Buffer) IsCellInSearchHighlight(screenX, screenY int) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.searchMatches) == 0 {
+		return false
+	}
+	bufferY := b.screenToBufferY(screenY)
+	for _, m := range b.searchMatches {
+		if m.contains(screenX, bufferY) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrollToMatch sets the scroll offset so the match's row is centered
+// vertically (as far as the scrollable range allows)
+func (b *Buffer) ScrollToMatch(m Match) {
+	b.EnsureVisibleBufferY(m.Row, PositionCenter)
+}
+
+// Position says where on the visible area EnsureVisibleBufferY puts a row
+type Position int
+
+const (
+	PositionTop    Position = iota // First visible row
+	PositionCenter                 // Middle visible row
+	PositionBottom                 // Last visible row
+)
+
+// EnsureVisibleBufferY sets the scroll offset so the buffer-absolute row
+// bufferY (as from a Match or selection) lands on the visible row given by
+// where. Near either end of the scrollable range the offset is clamped, so
+// the row is shown but may sit elsewhere on screen.
+func (b *Buffer) EnsureVisibleBufferY(bufferY int, where Position) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	screenY := 0
+	switch where {
+	case PositionCenter:
+		screenY = b.rows / 2
+	case PositionBottom:
+		screenY = b.rows - 1
+	}
+
+	offset := b.scrollOffsetForRowInternal(bufferY, screenY)
+	if offset != b.scrollOffset {
+		b.markFullDamage()
+	}
+	b.scrollOffset = offset
+	b.markDirty()
+}
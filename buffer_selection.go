@@ -80,17 +80,58 @@ func (b *Buffer) UpdateSelection(x, y int) {
 	b.markDirty()
 }
 
-// EndSelection finalizes the selection
+// EndSelection finalizes the selection. The selection remains active until
+// cleared; with auto-copy on select enabled its text is passed to the
+// selection changed callback.
 func (b *Buffer) EndSelection() {
-	// Selection remains active until cleared
+	b.mu.RLock()
+	fn := b.onSelectionChanged
+	report := b.autoCopyOnSelect && b.selectionActive
+	b.mu.RUnlock()
+	if fn != nil && report {
+		fn(b.GetSelectedText())
+	}
 }
 
-// ClearSelection clears any active selection
+// ClearSelection clears any active selection. With auto-copy on select
+// enabled, clearing an active selection passes "" to the selection changed
+// callback.
 func (b *Buffer) ClearSelection() {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	report := b.autoCopyOnSelect && b.selectionActive
+	fn := b.onSelectionChanged
 	b.selectionActive = false
 	b.markDirty()
+	b.mu.Unlock()
+	if fn != nil && report {
+		fn("")
+	}
+}
+
+// SetAutoCopyOnSelect enables "copy on select": when on, EndSelection hands
+// the selected text to the selection changed callback so an adapter can put
+// it on the clipboard (the X11 primary selection on Linux).
+func (b *Buffer) SetAutoCopyOnSelect(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.autoCopyOnSelect = enabled
+}
+
+// GetAutoCopyOnSelect reports whether copy on select is enabled
+func (b *Buffer) GetAutoCopyOnSelect() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.autoCopyOnSelect
+}
+
+// SetSelectionChangedCallback sets a callback to be invoked, with auto-copy
+// on select enabled, when a selection is finished or cleared.
+// text is the current GetSelectedText, or "" after a clear. The callback
+// runs without the buffer lock held.
+func (b *Buffer) SetSelectionChangedCallback(fn func(text string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onSelectionChanged = fn
 }
 
 // HasSelection returns true if there's an active selection
@@ -164,7 +205,28 @@ func (b *Buffer) getCellByAbsoluteY(x, bufferY int) Cell {
 	return b.getLogicalCell(x, logicalY)
 }
 
-// GetSelectedText returns the text in the current selection
+// lineAttributeByAbsoluteY gets a line's attribute using buffer-absolute Y
+func (b *Buffer) lineAttributeByAbsoluteY(bufferY int) LineAttribute {
+	if bufferY < 0 {
+		return LineAttrNormal
+	}
+	if bufferY < len(b.scrollback) {
+		if bufferY < len(b.scrollbackInfo) {
+			return b.scrollbackInfo[bufferY].Attribute
+		}
+		return LineAttrNormal
+	}
+	logicalY := bufferY - len(b.scrollback)
+	if logicalY >= 0 && logicalY < len(b.lineInfos) {
+		return b.lineInfos[logicalY].Attribute
+	}
+	return LineAttrNormal
+}
+
+// GetSelectedText returns the text in the current selection. Columns are
+// logical cells, so a double-width or double-height line (which shows only
+// the first half of its cells, each two columns wide) yields each
+// character once and nothing past the visible half.
 func (b *Buffer) GetSelectedText() string {
 	sx, sy, ex, ey, active := b.GetSelection()
 	if !active {
@@ -189,9 +251,20 @@ func (b *Buffer) GetSelectedText() string {
 		if bufferY == ey {
 			endX = ex + 1
 		}
+		// A selection starting on a continuation includes its wide glyph
+		if startX > 0 && b.getCellByAbsoluteY(startX, bufferY).Continuation {
+			startX--
+		}
+		lineCols := b.cols
+		if b.lineAttributeByAbsoluteY(bufferY) != LineAttrNormal {
+			lineCols = b.cols / 2
+		}
 		var lineRunes []rune
-		for x := startX; x < endX && x < b.cols; x++ {
+		for x := startX; x < endX && x < lineCols; x++ {
 			cell := b.getCellByAbsoluteY(x, bufferY)
+			if cell.Continuation {
+				continue
+			}
 			lineRunes = append(lineRunes, cell.Char)
 		}
 		line := string(lineRunes)
@@ -1,5 +1,11 @@
 package purfecterm
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // --- Screen Split Methods ---
 
 // DeleteAllScreenSplits removes all screen splits.
@@ -19,6 +25,8 @@ func (b *Buffer) DeleteScreenSplit(id int) {
 }
 
 // SetScreenSplit creates or updates a screen split.
+// Out-of-range values are clamped and described by LastSplitWarning, as is
+// another split already starting at the same screenY.
 // screenY: Y coordinate in sprite units where this split begins on screen
 // bufferRow, bufferCol: 0-indexed logical screen coordinates to draw from
 // topFineScroll, leftFineScroll: 0 to (subdivisions-1), higher = more clipped
@@ -28,6 +36,42 @@ func (b *Buffer) SetScreenSplit(id int, screenY, bufferRow, bufferCol, topFineSc
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	var warnings []string
+	effectiveRows := b.EffectiveRows()
+
+	// Clamp the split start to the logical screen (in sprite units)
+	maxScreenY := effectiveRows*b.spriteUnitY - 1
+	if screenY < 0 {
+		warnings = append(warnings, fmt.Sprintf("split %d: screenY %d is negative; clamped to 0", id, screenY))
+		screenY = 0
+	} else if screenY > maxScreenY {
+		warnings = append(warnings, fmt.Sprintf("split %d: screenY %d exceeds the logical screen height (%d units); clamped to %d",
+			id, screenY, maxScreenY+1, maxScreenY))
+		screenY = maxScreenY
+	}
+
+	// Clamp the buffer row to the logical screen. bufferCol is left alone:
+	// a split may start partway across a line wider than the screen.
+	if bufferRow < 0 || bufferRow >= effectiveRows {
+		clamped := max(0, min(bufferRow, effectiveRows-1))
+		warnings = append(warnings, fmt.Sprintf("split %d: bufferRow %d is outside the logical screen (%d rows); clamped to %d",
+			id, bufferRow, effectiveRows, clamped))
+		bufferRow = clamped
+	}
+
+	// Splits sharing a start line hide one another
+	var sameStart []int
+	for otherID, other := range b.screenSplits {
+		if otherID != id && other.ScreenY == screenY {
+			sameStart = append(sameStart, otherID)
+		}
+	}
+	sort.Ints(sameStart)
+	for _, otherID := range sameStart {
+		warnings = append(warnings, fmt.Sprintf("split %d: split %d also starts at screenY %d; only one will be visible",
+			id, otherID, screenY))
+	}
+
 	// Clamp fine scroll values
 	if topFineScroll < 0 {
 		topFineScroll = 0
@@ -42,6 +86,12 @@ func (b *Buffer) SetScreenSplit(id int, screenY, bufferRow, bufferCol, topFineSc
 		leftFineScroll = b.spriteUnitX - 1
 	}
 
+	// Updating an existing split keeps its independent scroll position
+	scrollOffset := 0
+	if existing := b.screenSplits[id]; existing != nil {
+		scrollOffset = existing.ScrollOffset
+	}
+
 	b.screenSplits[id] = &ScreenSplit{
 		ScreenY:        screenY,
 		BufferRow:      bufferRow,
@@ -50,8 +100,43 @@ func (b *Buffer) SetScreenSplit(id int, screenY, bufferRow, bufferCol, topFineSc
 		LeftFineScroll: leftFineScroll,
 		CharWidthScale: charWidthScale,
 		LineDensity:    lineDensity,
+		ScrollOffset:   scrollOffset,
+	}
+	b.setSplitWarning(strings.Join(warnings, "; "))
+	b.markDirty()
+}
+
+// SetScreenSplitScroll sets how many lines a split is scrolled back, in
+// addition to the main view's scroll offset, so a split can show older
+// content while the main screen stays live.
+// Returns false if the split doesn't exist
+func (b *Buffer) SetScreenSplitScroll(id int, offset int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	split := b.screenSplits[id]
+	if split == nil {
+		return false
 	}
+	if offset < 0 {
+		offset = 0
+	}
+	// Replace the split rather than modify it: renderers hold pointers from
+	// GetScreenSplitsSorted and read them without the lock
+	updated := *split
+	updated.ScrollOffset = offset
+	b.screenSplits[id] = &updated
 	b.markDirty()
+	return true
+}
+
+// splitScrollOffset combines the main scroll offset with a split's own
+// offset, clamped to the scrollable range. Must be called with the lock held.
+func (b *Buffer) splitScrollOffset(splitScroll int) int {
+	offset := b.scrollOffset + splitScroll
+	if maxOffset := b.getMaxScrollOffsetInternal(); offset > maxOffset {
+		offset = maxOffset
+	}
+	return offset
 }
 
 // GetScreenSplit returns a screen split by ID, or nil if not found.
@@ -85,15 +170,59 @@ func (b *Buffer) GetScreenSplitsSorted() []*ScreenSplit {
 		}
 	}
 
+	// Splits sharing a start line hide one another
+	byScreenY := make(map[int][]int)
+	for id, split := range b.screenSplits {
+		byScreenY[split.ScreenY] = append(byScreenY[split.ScreenY], id)
+	}
+	var warnings []string
+	for i, split := range splits {
+		ids := byScreenY[split.ScreenY]
+		if len(ids) < 2 || (i > 0 && splits[i-1].ScreenY == split.ScreenY) {
+			continue
+		}
+		sort.Ints(ids)
+		warnings = append(warnings, fmt.Sprintf("splits %v all start at screenY %d; only one will be visible",
+			ids, split.ScreenY))
+	}
+	if len(warnings) > 0 {
+		b.setSplitWarning(strings.Join(warnings, "; "))
+	}
+
 	return splits
 }
 
+// LastSplitWarning returns the most recent split diagnostics, joined with
+// "; ". SetScreenSplit reports each value it clamped and any other split
+// starting at the same ScreenY, and returns "" here when its call was
+// clean. GetScreenSplitsSorted reports splits sharing a ScreenY, leaving
+// the diagnostic alone when there are none.
+func (b *Buffer) LastSplitWarning() string {
+	b.splitWarningMu.Lock()
+	defer b.splitWarningMu.Unlock()
+	return b.splitWarning
+}
+
+// setSplitWarning records a split diagnostic. It has its own lock so
+// GetScreenSplitsSorted can report while holding only the read lock.
+func (b *Buffer) setSplitWarning(warning string) {
+	b.splitWarningMu.Lock()
+	defer b.splitWarningMu.Unlock()
+	b.splitWarning = warning
+}
+
 // GetCellForSplit returns a cell for split rendering.
 // screenX/screenY: position within the split region (0 = first cell of split)
 // bufferRow/bufferCol: buffer offset for this split (0-indexed)
 // The cell is fetched from the logical screen at position (screenX + bufferCol, screenY + bufferRow)
 // accounting for the current scroll offset.
 func (b *Buffer) GetCellForSplit(screenX, screenY, bufferRow, bufferCol int) Cell {
+	return b.GetCellForScrolledSplit(screenX, screenY, bufferRow, bufferCol, 0)
+}
+
+// GetCellForScrolledSplit is GetCellForSplit for a split scrolled back by
+// its own ScrollOffset (splitScroll), on top of the current scroll offset.
+func (b *Buffer) GetCellForScrolledSplit(screenX, screenY, bufferRow, bufferCol, splitScroll int) Cell {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -116,15 +245,16 @@ func (b *Buffer) GetCellForSplit(screenX, screenY, bufferRow, bufferCol int) Cel
 
 	// Total scrollable area above visible
 	totalScrollableAbove := scrollbackSize + logicalHiddenAbove
+	scrollOffset := b.splitScrollOffset(splitScroll)
 
-	if b.scrollOffset == 0 {
+	if scrollOffset == 0 {
 		// Not scrolled - show bottom of logical screen
 		logicalY := logicalHiddenAbove + actualY
 		return b.getLogicalCell(actualX, logicalY)
 	}
 
 	// Scrolled up
-	absoluteY := totalScrollableAbove - b.scrollOffset + actualY
+	absoluteY := totalScrollableAbove - scrollOffset + actualY
 
 	if absoluteY < scrollbackSize {
 		return b.getScrollbackCell(actualX, absoluteY)
@@ -136,6 +266,12 @@ func (b *Buffer) GetCellForSplit(screenX, screenY, bufferRow, bufferCol int) Cel
 
 // GetLineAttributeForSplit returns the line attribute for split rendering.
 func (b *Buffer) GetLineAttributeForSplit(screenY, bufferRow int) LineAttribute {
+	return b.GetLineAttributeForScrolledSplit(screenY, bufferRow, 0)
+}
+
+// GetLineAttributeForScrolledSplit is GetLineAttributeForSplit for a split
+// scrolled back by its own ScrollOffset (splitScroll).
+func (b *Buffer) GetLineAttributeForScrolledSplit(screenY, bufferRow, splitScroll int) LineAttribute {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -154,8 +290,9 @@ func (b *Buffer) GetLineAttributeForSplit(screenY, bufferRow int) LineAttribute
 	}
 
 	totalScrollableAbove := scrollbackSize + logicalHiddenAbove
+	scrollOffset := b.splitScrollOffset(splitScroll)
 
-	if b.scrollOffset == 0 {
+	if scrollOffset == 0 {
 		logicalY := logicalHiddenAbove + actualY
 		if logicalY >= 0 && logicalY < len(b.lineInfos) {
 			return b.lineInfos[logicalY].Attribute
@@ -163,7 +300,7 @@ func (b *Buffer) GetLineAttributeForSplit(screenY, bufferRow int) LineAttribute
 		return LineAttrNormal
 	}
 
-	absoluteY := totalScrollableAbove - b.scrollOffset + actualY
+	absoluteY := totalScrollableAbove - scrollOffset + actualY
 
 	if absoluteY < scrollbackSize {
 		// Scrollback lines don't have special attributes
@@ -181,6 +318,12 @@ func (b *Buffer) GetLineAttributeForSplit(screenY, bufferRow int) LineAttribute
 // This is the line length minus the BufferCol offset (content before BufferCol is excluded).
 // Used to know when to stop rendering (no more content on line).
 func (b *Buffer) GetLineLengthForSplit(screenY, bufferRow, bufferCol int) int {
+	return b.GetLineLengthForScrolledSplit(screenY, bufferRow, bufferCol, 0)
+}
+
+// GetLineLengthForScrolledSplit is GetLineLengthForSplit for a split
+// scrolled back by its own ScrollOffset (splitScroll).
+func (b *Buffer) GetLineLengthForScrolledSplit(screenY, bufferRow, bufferCol, splitScroll int) int {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -199,15 +342,16 @@ func (b *Buffer) GetLineLengthForSplit(screenY, bufferRow, bufferCol int) int {
 	}
 
 	totalScrollableAbove := scrollbackSize + logicalHiddenAbove
+	scrollOffset := b.splitScrollOffset(splitScroll)
 
 	var lineLen int
-	if b.scrollOffset == 0 {
+	if scrollOffset == 0 {
 		logicalY := logicalHiddenAbove + actualY
 		if logicalY >= 0 && logicalY < len(b.screen) {
 			lineLen = len(b.screen[logicalY])
 		}
 	} else {
-		absoluteY := totalScrollableAbove - b.scrollOffset + actualY
+		absoluteY := totalScrollableAbove - scrollOffset + actualY
 		if absoluteY < scrollbackSize {
 			if absoluteY >= 0 && absoluteY < len(b.scrollback) {
 				lineLen = len(b.scrollback[absoluteY])
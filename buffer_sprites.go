@@ -53,6 +53,10 @@ func (b *Buffer) SetSprite(id int, x, y float64, zIndex, fgp, flipCode int, xSca
 	sprite.YScale = yScale
 	sprite.CropRect = cropRect
 	sprite.SetRunes(runes)
+	if old := b.sprites[id]; old != nil {
+		sprite.Layer = old.Layer // Updates keep the sprite's layer and opacity
+		sprite.Alpha = old.Alpha
+	}
 
 	b.sprites[id] = sprite
 	b.markDirty()
@@ -110,8 +114,151 @@ func (b *Buffer) MoveSpriteAndRunes(id int, x, y float64, runes []rune) bool {
 	return true
 }
 
+// SetSpriteLayer moves an existing sprite into a layer group
+// Returns false if sprite doesn't exist
+func (b *Buffer) SetSpriteLayer(id, layer int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sprite := b.sprites[id]
+	if sprite == nil {
+		return false
+	}
+	sprite.Layer = layer
+	b.markDirty()
+	return true
+}
+
+// SetSpriteAlpha sets an existing sprite's opacity, from 0 (invisible) to
+// 1 (opaque); stepping it each frame fades the sprite in or out.
+// Returns false if sprite doesn't exist
+func (b *Buffer) SetSpriteAlpha(id int, alpha float64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sprite := b.sprites[id]
+	if sprite == nil {
+		return false
+	}
+	sprite.Alpha = alpha
+	b.markDirty()
+	return true
+}
+
+// SetSpriteZ changes an existing sprite's Z-index; negative values draw
+// it behind the text. Returns false if sprite doesn't exist
+func (b *Buffer) SetSpriteZ(id, z int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sprite := b.sprites[id]
+	if sprite == nil {
+		return false
+	}
+	sprite.ZIndex = z
+	b.markDirty()
+	return true
+}
+
+// BringSpriteToFront raises a sprite above every other sprite on its side
+// of the text (in front or behind), without crossing to the other side.
+// Returns false if sprite doesn't exist
+func (b *Buffer) BringSpriteToFront(id int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sprite := b.sprites[id]
+	if sprite == nil {
+		return false
+	}
+	behind := sprite.ZIndex < 0
+	z := sprite.ZIndex
+	for _, other := range b.sprites {
+		if other != sprite && (other.ZIndex < 0) == behind && other.ZIndex >= z {
+			z = other.ZIndex + 1
+		}
+	}
+	if behind && z >= 0 {
+		// No room below zero: push the rest of the group down instead
+		b.shiftSpriteGroupLocked(sprite, true, -(z + 1))
+		z = -1
+	}
+	sprite.ZIndex = z
+	b.markDirty()
+	return true
+}
+
+// SendSpriteToBack lowers a sprite below every other sprite on its side of
+// the text (in front or behind), without crossing to the other side.
+// Returns false if sprite doesn't exist
+func (b *Buffer) SendSpriteToBack(id int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sprite := b.sprites[id]
+	if sprite == nil {
+		return false
+	}
+	behind := sprite.ZIndex < 0
+	z := sprite.ZIndex
+	for _, other := range b.sprites {
+		if other != sprite && (other.ZIndex < 0) == behind && other.ZIndex <= z {
+			z = other.ZIndex - 1
+		}
+	}
+	if !behind && z < 0 {
+		// No room above zero: push the rest of the group up instead
+		b.shiftSpriteGroupLocked(sprite, false, -z)
+		z = 0
+	}
+	sprite.ZIndex = z
+	b.markDirty()
+	return true
+}
+
+// shiftSpriteGroupLocked adds delta to the Z-index of every sprite other
+// than except on one side of the text (behind: negative Z)
+func (b *Buffer) shiftSpriteGroupLocked(except *Sprite, behind bool, delta int) {
+	for _, other := range b.sprites {
+		if other != except && (other.ZIndex < 0) == behind {
+			other.ZIndex += delta
+		}
+	}
+}
+
+// SetLayerVisible shows or hides every sprite in a layer at once without
+// deleting them. Layers are visible until hidden.
+func (b *Buffer) SetLayerVisible(layer int, visible bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if visible {
+		delete(b.hiddenLayers, layer)
+	} else {
+		if b.hiddenLayers == nil {
+			b.hiddenLayers = make(map[int]bool)
+		}
+		b.hiddenLayers[layer] = true
+	}
+	b.markDirty()
+}
+
+// IsLayerVisible returns whether sprites in a layer are rendered
+func (b *Buffer) IsLayerVisible(layer int) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return !b.hiddenLayers[layer]
+}
+
+// DeleteSpritesInLayer removes all sprites in a layer
+func (b *Buffer) DeleteSpritesInLayer(layer int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, sprite := range b.sprites {
+		if sprite.Layer == layer {
+			delete(b.sprites, id)
+		}
+	}
+	b.markDirty()
+}
+
 // GetSpritesForRendering returns sprites sorted by Z-index and ID for rendering
 // Returns two slices: behind (negative Z) and front (non-negative Z)
+// Sprites in hidden layers are left out.
 func (b *Buffer) GetSpritesForRendering() (behind, front []*Sprite) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -120,6 +267,9 @@ func (b *Buffer) GetSpritesForRendering() (behind, front []*Sprite) {
 	front = make([]*Sprite, 0)
 
 	for _, sprite := range b.sprites {
+		if b.hiddenLayers[sprite.Layer] {
+			continue
+		}
 		if sprite.ZIndex < 0 {
 			behind = append(behind, sprite)
 		} else {
@@ -264,6 +414,17 @@ func (b *Buffer) SetCropRect(id int, minX, minY, maxX, maxY float64) {
 	b.markDirty()
 }
 
+// SetInvertedCropRect creates or updates a crop rectangle that masks out
+// its inside: sprites using it are drawn everywhere except within it
+func (b *Buffer) SetInvertedCropRect(id int, minX, minY, maxX, maxY float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	crop := NewCropRectangle(id, minX, minY, maxX, maxY)
+	crop.Inverted = true
+	b.cropRects[id] = crop
+	b.markDirty()
+}
+
 // GetCropRect returns a crop rectangle by ID, or nil if not found
 func (b *Buffer) GetCropRect(id int) *CropRectangle {
 	b.mu.RLock()
@@ -0,0 +1,96 @@
+package purfecterm
+
+import "time"
+
+// syncOutputTimeout bounds how long a synchronized update (DEC 2026) may
+// hold back redraws, so an application that never ends its frame cannot
+// freeze the display
+const syncOutputTimeout = 500 * time.Millisecond
+
+// --- Synchronized Output (DEC 2026) ---
+
+// SetSynchronizedOutput begins (true) or ends (false) a synchronized update.
+// While active, changes mark the buffer dirty but the dirty callback is held
+// back; ending the update fires it once if anything changed. An update not
+// ended within syncOutputTimeout ends by itself, even if no more output
+// arrives.
+func (b *Buffer) SetSynchronizedOutput(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if enabled {
+		if !b.syncOutput {
+			b.syncOutput = true
+			b.syncOutputStart = time.Now()
+			var timer *time.Timer
+			timer = time.AfterFunc(syncOutputTimeout, func() {
+				b.mu.Lock()
+				defer b.mu.Unlock()
+				// timer is read under the lock, after it was stored
+				b.syncOutputTimedOutInternal(timer)
+			})
+			b.syncOutputTimer = timer
+		}
+		return
+	}
+	b.endSyncOutputInternal()
+}
+
+// syncOutputTimedOutInternal ends the synchronized update timer belongs
+// to, if it is still in progress, and redraws whatever it held back. Must
+// be called with the lock held.
+func (b *Buffer) syncOutputTimedOutInternal(timer *time.Timer) {
+	if !b.syncOutput || b.syncOutputTimer != timer {
+		return
+	}
+	b.syncOutputTimer = nil
+	b.syncOutput = false
+	b.syncDirtyPending = false
+	b.markDirty()
+}
+
+// IsSynchronizedOutput returns whether a synchronized update is in progress
+func (b *Buffer) IsSynchronizedOutput() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.syncOutput
+}
+
+// endSyncOutputInternal ends a synchronized update and fires the held-back
+// dirty callback. Must be called with the lock held.
+func (b *Buffer) endSyncOutputInternal() {
+	if !b.syncOutput {
+		return
+	}
+	b.stopSyncOutputTimerInternal()
+	b.syncOutput = false
+	if b.syncDirtyPending {
+		b.syncDirtyPending = false
+		b.markDirty()
+	}
+}
+
+// holdDirtyForSync reports whether markDirty should defer its callback
+// because a synchronized update is active; an update that outlived
+// syncOutputTimeout is ended instead. Must be called with the lock held.
+func (b *Buffer) holdDirtyForSync() bool {
+	if !b.syncOutput {
+		return false
+	}
+	if time.Since(b.syncOutputStart) > syncOutputTimeout {
+		b.stopSyncOutputTimerInternal()
+		b.syncOutput = false
+		b.syncDirtyPending = false
+		return false
+	}
+	b.syncDirtyPending = true
+	return true
+}
+
+// stopSyncOutputTimerInternal cancels the timeout of the current
+// synchronized update. Must be called with the lock held.
+func (b *Buffer) stopSyncOutputTimerInternal() {
+	if b.syncOutputTimer != nil {
+		b.syncOutputTimer.Stop()
+		b.syncOutputTimer = nil
+	}
+}
@@ -1,5 +1,7 @@
 package purfecterm
 
+import "math"
+
 // UnderlineStyle represents different underline rendering styles
 type UnderlineStyle int
 
@@ -19,6 +21,7 @@ type Cell struct {
 	Foreground     Color
 	Background     Color
 	Bold           bool
+	Faint          bool           // SGR 2: foreground drawn at reduced brightness
 	Italic         bool
 	Underline      bool           // Legacy: true if any underline style is active
 	UnderlineStyle UnderlineStyle // Underline style (None, Single, Double, Curly, Dotted, Dashed)
@@ -26,13 +29,29 @@ type Cell struct {
 	HasUnderlineColor bool        // True if UnderlineColor is explicitly set
 	Reverse        bool
 	Blink          bool    // When true, character animates (bobbing wave instead of traditional blink)
+	BlinkRapid     bool    // With Blink: rapid blink (SGR 6) rather than slow (SGR 5); animates at twice the rate
 	Strikethrough  bool    // When true, draw a line through the character
+	Overline       bool    // When true, draw a line along the top of the cell
+	Conceal        bool    // SGR 8: glyph is hidden (drawn as a blank) but kept for copy
 	FlexWidth      bool    // When true, cell uses East Asian Width for variable width rendering
 	CellWidth      float64 // Visual width in cell units (0.5, 1.0, 1.5, 2.0) - only used when FlexWidth is true
 	BGP            int     // Base Glyph Palette index (-1 = use foreground color code as palette)
 	XFlip          bool    // Horizontal flip for custom glyphs
 	YFlip          bool    // Vertical flip for custom glyphs
 	Font           uint8   // Font slot 0..10: 0 = primary (SGR 10), 1..9 = alternates (SGR 11..19), 10 = fraktur (SGR 20). A renderer maps the slot to a family; unset slots inherit slot 0.
+	Continuation   bool    // Trailing column of a wide character in wide-char mode; occupies no width of its own and is not drawn
+}
+
+// VisualWidth returns the width the cell occupies in cell units: 0 for a
+// continuation cell, CellWidth when set, otherwise 1.0
+func (c *Cell) VisualWidth() float64 {
+	if c.Continuation {
+		return 0
+	}
+	if c.CellWidth > 0 {
+		return c.CellWidth
+	}
+	return 1.0
 }
 
 const (
@@ -45,6 +64,55 @@ const (
 	VTFrakturFont = "VTFRAKTUR"
 )
 
+// BlinkPhase maps a renderer's blink animation phase (radians, 0 to 2*PI)
+// to this cell's phase: rapid blink runs at twice the slow rate
+func (c *Cell) BlinkPhase(phase float64) float64 {
+	if c.BlinkRapid {
+		return math.Mod(phase*2, 2*math.Pi)
+	}
+	return phase
+}
+
+// BlinkAnimation holds the timing and shape of blinking text. Adapters
+// advance a phase (radians, 0 to 2*PI) by PhaseStep on each timer tick and
+// pass it through Cell.BlinkPhase.
+type BlinkAnimation struct {
+	CycleMs    int     // Duration of one slow blink cycle in milliseconds
+	Amplitude  float64 // Bounce height in pixels, up and down (BlinkModeBounce)
+	PhaseShift float64 // Phase step in radians between neighbouring columns (BlinkModeBounce)
+	DutyCycle  float64 // Fraction of each cycle the text is shown (BlinkModeBlink)
+}
+
+// DefaultBlinkAnimation is a ~1.5 second bobbing wave, 3 pixels high, and
+// an even on/off split for BlinkModeBlink
+var DefaultBlinkAnimation = BlinkAnimation{
+	CycleMs:    1500,
+	Amplitude:  3.0,
+	PhaseShift: 0.5,
+	DutyCycle:  0.5,
+}
+
+// PhaseStep returns how far the phase advances in one tick of tickMs
+// milliseconds. A non-positive CycleMs uses the default cycle.
+func (a BlinkAnimation) PhaseStep(tickMs int) float64 {
+	cycle := a.CycleMs
+	if cycle <= 0 {
+		cycle = DefaultBlinkAnimation.CycleMs
+	}
+	return 2 * math.Pi * float64(tickMs) / float64(cycle)
+}
+
+// BounceOffset returns the vertical offset in pixels of a bouncing cell in
+// column col, given the cell's phase (see Cell.BlinkPhase)
+func (a BlinkAnimation) BounceOffset(phase float64, col int) float64 {
+	return math.Sin(phase+float64(col)*a.PhaseShift) * a.Amplitude
+}
+
+// Visible reports whether on/off blinking text is shown at the cell's phase
+func (a BlinkAnimation) Visible(phase float64) bool {
+	return phase < 2*math.Pi*a.DutyCycle
+}
+
 // String returns the full character including any combining marks
 func (c *Cell) String() string {
 	if c.Combining == "" {
@@ -144,6 +212,17 @@ func IsCombiningMark(r rune) bool {
 	return false
 }
 
+// IsZeroWidth returns true if the rune has no display width of its own and
+// modifies the character before it: zero-width non-joiner/joiner
+// (U+200C/U+200D) and variation selectors (U+FE00-U+FE0F, U+E0100-U+E01EF).
+// Like combining marks, these attach to the previous cell so emoji ZWJ and
+// presentation sequences (e.g. VS16) reach the renderer intact.
+func IsZeroWidth(r rune) bool {
+	return r == 0x200C || r == 0x200D ||
+		(r >= 0xFE00 && r <= 0xFE0F) ||
+		(r >= 0xE0100 && r <= 0xE01EF)
+}
+
 // EastAsianWidth represents the Unicode East Asian Width property
 type EastAsianWidth int
 
@@ -645,6 +724,8 @@ type Sprite struct {
 	XScale   float64   // Horizontal scale multiplier
 	YScale   float64   // Vertical scale multiplier
 	CropRect int       // Crop rectangle ID (-1 = no cropping)
+	Layer    int       // Layer group, shown or hidden as a whole (see Buffer.SetLayerVisible)
+	Alpha    float64   // Opacity from 0 (invisible) to 1 (opaque), for fades
 	Runes    [][]rune  // 2D array of characters (rows of runes, for multi-tile sprites)
 }
 
@@ -660,10 +741,22 @@ func NewSprite(id int) *Sprite {
 		XScale:   1.0,
 		YScale:   1.0,
 		CropRect: -1,
+		Alpha:    1.0,
 		Runes:    nil,
 	}
 }
 
+// Opacity returns Alpha clamped to 0..1, the value renderers draw with
+func (s *Sprite) Opacity() float64 {
+	switch {
+	case s.Alpha < 0:
+		return 0
+	case s.Alpha > 1:
+		return 1
+	}
+	return s.Alpha
+}
+
 // SetRunes parses rune data, splitting on newline (rune 10) for multi-row sprites
 func (s *Sprite) SetRunes(runes []rune) {
 	s.Runes = make([][]rune, 0)
@@ -693,11 +786,14 @@ func (s *Sprite) GetYFlip() bool {
 	return s.FlipCode == 2 || s.FlipCode == 3
 }
 
-// CropRectangle defines a rectangular clipping area for sprites
+// CropRectangle defines a rectangular clipping area for sprites.
+// Normally only the inside of the rectangle is drawn; an Inverted crop
+// masks the inside out instead, leaving a hole in the sprite.
 type CropRectangle struct {
 	ID               int
 	MinX, MinY       float64
 	MaxX, MaxY       float64
+	Inverted         bool
 }
 
 // NewCropRectangle creates a new crop rectangle
@@ -716,6 +812,17 @@ func (cr *CropRectangle) Contains(x, y float64) bool {
 	return x >= cr.MinX && x <= cr.MaxX && y >= cr.MinY && y <= cr.MaxY
 }
 
+// HidesArea reports whether the crop suppresses the whole area from
+// (x0, y0) to (x1, y1), given in the same units as the rectangle. A normal
+// crop hides areas entirely outside it and an inverted crop areas entirely
+// inside it; partly covered areas are drawn.
+func (cr *CropRectangle) HidesArea(x0, y0, x1, y1 float64) bool {
+	if cr.Inverted {
+		return x0 >= cr.MinX && x1 <= cr.MaxX && y0 >= cr.MinY && y1 <= cr.MaxY
+	}
+	return x1 <= cr.MinX || x0 >= cr.MaxX || y1 <= cr.MinY || y0 >= cr.MaxY
+}
+
 // LineAttribute defines the display mode for a line (VT100 DECDHL/DECDWL)
 type LineAttribute int
 
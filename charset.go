@@ -0,0 +1,104 @@
+package purfecterm
+
+// --- Character Set Designation (SCS) ---
+
+// Charset designators as used in SCS sequences (ESC ( F, ESC ) F, ESC * F,
+// ESC + F)
+const (
+	CharsetASCII       byte = 'B' // US ASCII (default)
+	CharsetUK          byte = 'A' // United Kingdom: '#' is a pound sign
+	CharsetDECGraphics byte = '0' // DEC Special Graphics (line drawing)
+)
+
+// decSpecialGraphics maps the 0x5F-0x7E range of the DEC Special Graphics
+// set to their Unicode equivalents
+var decSpecialGraphics = [...]rune{
+	' ', // 0x5F _ blank
+	'◆', // 0x60 ` diamond
+	'▒', // 0x61 a checkerboard
+	'␉', // 0x62 b HT
+	'␌', // 0x63 c FF
+	'␍', // 0x64 d CR
+	'␊', // 0x65 e LF
+	'°', // 0x66 f degree
+	'±', // 0x67 g plus/minus
+	'␤', // 0x68 h NL
+	'␋', // 0x69 i VT
+	'┘', // 0x6A j lower right corner
+	'┐', // 0x6B k upper right corner
+	'┌', // 0x6C l upper left corner
+	'└', // 0x6D m lower left corner
+	'┼', // 0x6E n crossing lines
+	'⎺', // 0x6F o scan line 1
+	'⎻', // 0x70 p scan line 3
+	'─', // 0x71 q horizontal line (scan line 5)
+	'⎼', // 0x72 r scan line 7
+	'⎽', // 0x73 s scan line 9
+	'├', // 0x74 t left tee
+	'┤', // 0x75 u right tee
+	'┴', // 0x76 v bottom tee
+	'┬', // 0x77 w top tee
+	'│', // 0x78 x vertical line
+	'≤', // 0x79 y less than or equal
+	'≥', // 0x7A z greater than or equal
+	'π', // 0x7B { pi
+	'≠', // 0x7C | not equal
+	'£', // 0x7D } pound sign
+	'·', // 0x7E ~ centered dot
+}
+
+// translateCharset maps a printable ASCII byte through the given charset
+func translateCharset(charset byte, b byte) rune {
+	switch charset {
+	case CharsetDECGraphics:
+		if b >= 0x5F && b <= 0x7E {
+			return decSpecialGraphics[b-0x5F]
+		}
+	case CharsetUK:
+		if b == '#' {
+			return '£'
+		}
+	}
+	return rune(b)
+}
+
+// handleCharset completes an SCS sequence (ESC ( F designates G0,
+// ESC ) F G1, ESC * F G2, ESC + F G3). Unknown sets fall back to ASCII.
+func (p *Parser) handleCharset(b byte) {
+	switch b {
+	case CharsetDECGraphics, CharsetUK:
+	default:
+		b = CharsetASCII
+	}
+	p.charsets[p.charsetSlot] = b
+	p.state = stateGround
+}
+
+// activeCharset returns the charset invoked into GL: G1 after SO, else G0
+// (a pending single shift is not included; see graphicCharset)
+func (p *Parser) activeCharset() byte {
+	if p.shiftOut {
+		return p.charsets[1]
+	}
+	return p.charsets[0]
+}
+
+// graphicCharset returns the charset for the next graphic character and
+// ends any single shift: SS2 (ESC N) and SS3 (ESC O) take G2 or G3 for
+// just that one character
+func (p *Parser) graphicCharset() byte {
+	if p.singleShift != 0 {
+		slot := p.singleShift
+		p.singleShift = 0
+		return p.charsets[slot]
+	}
+	return p.activeCharset()
+}
+
+// resetCharsets designates ASCII into G0-G3, invokes G0 and cancels any
+// single shift
+func (p *Parser) resetCharsets() {
+	p.charsets = [4]byte{CharsetASCII, CharsetASCII, CharsetASCII, CharsetASCII}
+	p.shiftOut = false
+	p.singleShift = 0
+}
@@ -0,0 +1,55 @@
+package purfecterm
+
+import "testing"
+
+// ESC ( 0 selects DEC line drawing for G0; ESC ( B restores ASCII. SO/SI
+// switch to and from a G1 line-drawing designation.
+func TestDECSpecialGraphics(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b(0lqk\x1b(Bq"))
+	if got := b.RenderPlain(); got != "┌─┐q\n" {
+		t.Fatalf("G0 line drawing = %q", got)
+	}
+
+	b2 := NewBuffer(20, 2, 100)
+	p2 := NewParser(b2)
+	p2.Parse([]byte("\x1b)0x\x0ex\x0fx"))
+	if got := b2.RenderPlain(); got != "x│x\n" {
+		t.Fatalf("SO/SI with G1 line drawing = %q", got)
+	}
+}
+
+// SS2/SS3 take G2/G3 for exactly one character, then GL (G0 here) is
+// back in effect; ESC * and ESC + designate G2 and G3.
+func TestSingleShift(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b*0q\x1bNqq"))
+	if got := b.RenderPlain(); got != "q─q\n" {
+		t.Fatalf("SS2 with G2 line drawing = %q", got)
+	}
+
+	b2 := NewBuffer(20, 2, 100)
+	p2 := NewParser(b2)
+	p2.Parse([]byte("\x1b+A\x1bO#"))
+	p2.Parse([]byte("#")) // The shift was used up in the previous call
+	if got := b2.RenderPlain(); got != "£#\n" {
+		t.Fatalf("SS3 with G3 UK = %q", got)
+	}
+
+	// Controls between the shift and the character leave it pending;
+	// a non-ASCII character ends it
+	b3 := NewBuffer(20, 2, 100)
+	p3 := NewParser(b3)
+	p3.Parse([]byte("\x1b*0\x1bN\rx\x1bNéx"))
+	if got := b3.RenderPlain(); got != "│éx\n" {
+		t.Fatalf("pending/ended shift = %q", got)
+	}
+
+	// RIS cancels a pending shift and clears the designations
+	p3.Parse([]byte("\x1bN\x1bcq"))
+	if got := b3.GetCell(0, 0).Char; got != 'q' {
+		t.Fatalf("after RIS got %q, want plain q", got)
+	}
+}
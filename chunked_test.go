@@ -0,0 +1,37 @@
+package purfecterm
+
+import "testing"
+
+// An SGR split across Parse calls still applies to the next character.
+func TestParseSplitSequence(t *testing.T) {
+	b := NewBuffer(10, 2, 0)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b[3"))
+	if !p.InEscapeSequence() {
+		t.Fatal("parser should be waiting for the rest of the CSI")
+	}
+	p.Parse([]byte("1mX"))
+	if p.InEscapeSequence() {
+		t.Fatal("CSI should be complete")
+	}
+
+	c := b.GetCell(0, 0)
+	if c.Char != 'X' || c.Foreground != StandardColor(1) {
+		t.Fatalf("cell = %q fg %+v, want red 'X'", c.Char, c.Foreground)
+	}
+}
+
+// Reset drops a stuck partial sequence so the next bytes print as text.
+func TestParserReset(t *testing.T) {
+	b := NewBuffer(10, 2, 0)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b]2;unterminated"))
+	p.Reset()
+	if p.InEscapeSequence() {
+		t.Fatal("Reset should return to the ground state")
+	}
+	p.Parse([]byte("ok"))
+	if c := b.GetCell(0, 0).Char; c != 'o' {
+		t.Fatalf("cell 0,0 = %q, want 'o'", c)
+	}
+}
@@ -0,0 +1,23 @@
+package cli
+
+import "testing"
+
+// Capabilities returns a live struct whose dimensions follow Resize.
+func TestCLICapabilitiesFollowResize(t *testing.T) {
+	term, err := New(Options{Cols: 20, Rows: 5, Embedded: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	caps := term.Capabilities()
+	if w, h := caps.GetSize(); w != 20 || h != 5 {
+		t.Fatalf("initial size = %dx%d, want 20x5", w, h)
+	}
+	if caps.TermType != "xterm-256color" || caps.ColorDepth != 24 {
+		t.Fatalf("unexpected caps: %q depth %d", caps.TermType, caps.ColorDepth)
+	}
+
+	term.Resize(40, 12)
+	if w, h := caps.GetSize(); w != 40 || h != 12 {
+		t.Fatalf("after resize size = %dx%d, want 40x12", w, h)
+	}
+}
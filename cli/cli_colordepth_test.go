@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phroun/purfecterm"
+)
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want int
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, 256},
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, 24},
+		{map[string]string{"TERM": "xterm-direct"}, 24},
+		{map[string]string{"TERM": "xterm"}, 16},
+		{map[string]string{"TERM": "linux"}, 16},
+		{map[string]string{"TERM": "dumb"}, 0},
+		{map[string]string{}, 256},
+		{map[string]string{"WT_SESSION": "1"}, 24},
+	}
+	for _, tc := range tests {
+		getenv := func(k string) string { return tc.env[k] }
+		if got := DetectColorDepth(getenv); got != tc.want {
+			t.Errorf("DetectColorDepth(%v) = %d, want %d", tc.env, got, tc.want)
+		}
+	}
+}
+
+// On a 256-color host, true color cells are drawn with the nearest
+// palette entry instead of 24-bit SGR.
+func TestCLIRenderColorDepth(t *testing.T) {
+	for _, tc := range []struct {
+		depth      int
+		want, omit string
+	}{
+		{24, "38;2;255;0;0", "38;5;"},
+		{256, "38;5;196", "38;2;"},
+		{16, ";31;", "38;"},
+	} {
+		term, err := New(Options{Cols: 10, Rows: 2, Embedded: true, ColorDepth: tc.depth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := term.HostColorDepth(); got != tc.depth {
+			t.Fatalf("HostColorDepth = %d, want %d", got, tc.depth)
+		}
+		term.FeedString("\x1b[38;2;255;0;0mX")
+		out := term.RenderToString()
+		if !strings.Contains(out, tc.want) || strings.Contains(out, tc.omit) {
+			t.Errorf("depth %d: output %q should contain %q and not %q", tc.depth, out, tc.want, tc.omit)
+		}
+	}
+
+	if c := purfecterm.TrueColor(1, 2, 3).Downsample(0); !c.IsDefault() {
+		t.Errorf("depth 0 should drop colors, got %+v", c)
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// SetWorkingDir and AppendEnv reach the child: its output shows the
+// injected variable and directory.
+func TestCLIChildEnvAndDir(t *testing.T) {
+	term, err := New(Options{Cols: 60, Rows: 5, Embedded: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := term.SetWorkingDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := term.SetWorkingDir(dir + "/missing"); err == nil {
+		t.Fatal("a missing directory should be rejected")
+	}
+	term.AppendEnv("PURFECT_TEST=injected")
+
+	if err := term.RunCommand("/bin/sh", "-c", "echo $PURFECT_TEST; pwd; sleep 1"); err != nil {
+		t.Skipf("no PTY available: %v", err)
+	}
+	defer term.Close()
+
+	deadline := time.Now().Add(3 * time.Second)
+	var text string
+	for time.Now().Before(deadline) {
+		text = term.SaveScrollbackText()
+		if strings.Contains(text, "injected") && strings.Contains(text, dir) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("child output missing env/dir, got %q", text)
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// A panic inside RunGuarded stops the terminal, running its restore path,
+// before the panic reaches the caller
+func TestCLIRunGuardedPanic(t *testing.T) {
+	term, err := New(Options{Cols: 20, Rows: 3, Embedded: true, ForwardTitle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var host bytes.Buffer
+	term.hostOut = &host
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the original panic", r)
+			}
+		}()
+		RunGuarded(term, func() error {
+			term.FeedString("\x1b]2;job\x07")
+			panic("boom")
+		})
+	}()
+
+	if !term.stopped {
+		t.Fatal("terminal not stopped after the panic")
+	}
+	if got := host.String(); !strings.HasSuffix(got, "\x1b[23;2t") {
+		t.Errorf("host output %q, want the title restored by Stop", got)
+	}
+
+	// Stopping again (a deferred Stop in the caller, say) is harmless
+	if err := term.Stop(); err != nil {
+		t.Errorf("second Stop: %v", err)
+	}
+}
+
+// Without a panic RunGuarded returns fn's error, stopped all the same
+func TestCLIRunGuardedError(t *testing.T) {
+	term, err := New(Options{Cols: 20, Rows: 3, Embedded: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := errors.New("done")
+	if got := RunGuarded(term, func() error { return want }); got != want {
+		t.Errorf("RunGuarded = %v, want %v", got, want)
+	}
+	if !term.stopped {
+		t.Error("terminal not stopped")
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// After the first frame, changing one cell repaints just that cell: one
+// cursor move and the character, not the whole row.
+func TestCLIRenderSingleCellDiff(t *testing.T) {
+	term, err := New(Options{Cols: 20, Rows: 3, Embedded: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var host bytes.Buffer
+	term.hostOut = &host
+	r := NewRenderer(term)
+
+	term.FeedString("hello world")
+	r.Render()
+	host.Reset()
+
+	term.FeedString("\x1b[1;3HX")
+	r.Render()
+	out := host.String()
+	if !strings.HasPrefix(out, "\x1b[?25l\x1b[1;3H\x1b[") || !strings.HasSuffix(out, "mX\x1b[0m") {
+		t.Fatalf("single-cell frame = %q, want a move, one SGR and X", out)
+	}
+	if strings.Contains(out, "lo") || strings.Count(out, "H") != 1 {
+		t.Fatalf("frame rewrote more than the changed cell: %q", out)
+	}
+	if r.LastFrameBytes() != len(out) {
+		t.Fatalf("LastFrameBytes = %d, want %d", r.LastFrameBytes(), len(out))
+	}
+}
+
+// The frame writer picks the shortest way to reach each cell.
+func TestFrameWriterMoves(t *testing.T) {
+	tests := []struct {
+		fromRow, fromCol, row, col int
+		want                       string
+	}{
+		{-1, 0, 4, 9, "\x1b[5;10H"},
+		{-1, 0, 0, 0, "\x1b[H"},
+		{4, 9, 4, 9, ""},
+		{4, 2, 4, 3, "\x1b[C"},
+		{4, 2, 4, 30, "\x1b[28C"},
+		{4, 20, 4, 18, "\x1b[2D"},
+		{4, 20, 4, 0, "\r"},
+		{4, 60, 4, 1, "\r\x1b[C"},
+		{4, 7, 5, 7, "\x1b[B"},
+		{4, 7, 2, 7, "\x1b[2A"},
+		{2, 70, 30, 3, "\x1b[31;4H"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		w := newFrameWriter(&out)
+		w.row, w.col = tt.fromRow, tt.fromCol
+		w.moveTo(tt.row, tt.col)
+		if out.String() != tt.want {
+			t.Errorf("%d,%d -> %d,%d: got %q, want %q", tt.fromRow, tt.fromCol, tt.row, tt.col, out.String(), tt.want)
+		}
+	}
+}
+
+// When the window spans the host width, trailing blanks become one EL.
+func TestCLIRenderTrailingBlanksUseEL(t *testing.T) {
+	cols, _ := getHostTerminalSize()
+	term, err := New(Options{Cols: cols, Rows: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var host bytes.Buffer
+	term.hostOut = &host
+	term.FeedString("abc")
+	NewRenderer(term).Render()
+
+	out := host.String()
+	if !strings.Contains(out, "abc\x1b[K") || strings.Contains(out, "    ") {
+		t.Fatalf("trailing blanks should be cleared with EL, got %q", out)
+	}
+}
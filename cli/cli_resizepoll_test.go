@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+// The poller reports a change only when a reading differs from the one
+// before it, starting from the size when it was created.
+func TestSizePollerDetectsChanges(t *testing.T) {
+	readings := [][2]int{
+		{80, 24},  // Initial size
+		{80, 24},  // Unchanged
+		{100, 24}, // Wider
+		{100, 24}, // Unchanged
+		{100, 30}, // Taller
+		{80, 24},  // Back to the start
+		{80, 24},
+	}
+	want := []bool{false, true, false, true, true, false}
+
+	i := 0
+	p := newSizePoller(func() (int, int) {
+		r := readings[i]
+		i++
+		return r[0], r[1]
+	})
+	for n, w := range want {
+		if got := p.poll(); got != w {
+			t.Errorf("poll %d (size %v) = %v, want %v", n+1, readings[n+1], got, w)
+		}
+	}
+}
+
+func TestPollResizeCallsOnChange(t *testing.T) {
+	sizes := make(chan [2]int, 1)
+	sizes <- [2]int{80, 24}
+	current := [2]int{80, 24}
+	size := func() (int, int) {
+		select {
+		case current = <-sizes:
+		default:
+		}
+		return current[0], current[1]
+	}
+
+	changed := make(chan struct{}, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go pollResize(size, func() { changed <- struct{}{} }, stop)
+
+	sizes <- [2]int{120, 40}
+	select {
+	case <-changed:
+	case <-time.After(10 * resizePollInterval):
+		t.Fatal("resize was not detected")
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func scrollKeysTerminal(t *testing.T, keys map[string]ScrollAction) *Terminal {
+	t.Helper()
+	term, err := New(Options{Cols: 20, Rows: 4, Embedded: true, ScrollKeys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	term.SetFocused(true)
+	term.FeedString(strings.Repeat("line\r\n", 30))
+	return term
+}
+
+// Options.ScrollKeys rebinds scrollback navigation and replaces the
+// defaults; without it the Shift bindings apply.
+func TestCLIScrollKeys(t *testing.T) {
+	term := scrollKeysTerminal(t, nil)
+	term.HandleKeyString("S-Up")
+	if got := term.GetScrollOffset(); got != 1 {
+		t.Fatalf("default S-Up: scroll offset %d, want 1", got)
+	}
+	term.HandleKeyString("S-End")
+	if got := term.GetScrollOffset(); got != 0 {
+		t.Fatalf("default S-End: scroll offset %d, want 0", got)
+	}
+
+	term = scrollKeysTerminal(t, map[string]ScrollAction{
+		"C-M-Up":   ScrollLineUp,
+		"C-M-Home": ScrollTop,
+	})
+	term.HandleKeyString("C-M-Up")
+	term.HandleKeyString("C-M-Up")
+	if got := term.GetScrollOffset(); got != 2 {
+		t.Fatalf("rebound C-M-Up twice: scroll offset %d, want 2", got)
+	}
+	term.HandleKeyString("C-M-Home")
+	top := term.GetScrollOffset()
+	if top <= 2 {
+		t.Fatalf("rebound C-M-Home: scroll offset %d, want the top", top)
+	}
+
+	// S-Up is no longer bound: it goes to the child and, like any input,
+	// returns the view to the bottom
+	term.HandleKeyString("S-Up")
+	if got := term.GetScrollOffset(); got != 0 {
+		t.Fatalf("unbound S-Up: scroll offset %d, want 0", got)
+	}
+
+	if keys := DefaultScrollKeys(); keys["S-PageUp"] != ScrollPageUp || len(keys) != 6 {
+		t.Fatalf("DefaultScrollKeys = %v", keys)
+	}
+}
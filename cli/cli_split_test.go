@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+// The divider takes one column or row; an odd remainder goes to pane b.
+func TestSplitLayout(t *testing.T) {
+	a, b, d := splitLayout(80, 24, SplitVertical)
+	if a != (Rect{0, 0, 39, 24}) || d != (Rect{39, 0, 1, 24}) || b != (Rect{40, 0, 40, 24}) {
+		t.Errorf("vertical 80x24: a=%v divider=%v b=%v", a, d, b)
+	}
+	a, b, d = splitLayout(80, 25, SplitHorizontal)
+	if a != (Rect{0, 0, 80, 12}) || d != (Rect{0, 12, 80, 1}) || b != (Rect{0, 13, 80, 12}) {
+		t.Errorf("horizontal 80x25: a=%v divider=%v b=%v", a, d, b)
+	}
+}
+
+// Each pane has its own buffer: output fed to one never reaches the other,
+// and only the focused pane takes keys.
+func TestSplitPanesAreIndependent(t *testing.T) {
+	s, err := NewSplit(SplitOptions{Cols: 81, Rows: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	s.hostOut = &out
+
+	a, b := s.Pane(0), s.Pane(1)
+	if ac, _ := a.GetSize(); ac != 40 {
+		t.Errorf("pane a is %d columns, want 40", ac)
+	}
+	if x, _ := b.GetOffset(); x != 41 {
+		t.Errorf("pane b starts at column %d, want 41", x)
+	}
+
+	a.FeedString("hello from a")
+	for x := 0; x < 12; x++ {
+		if c := b.Buffer().GetCell(x, 0).Char; c != 0 && c != ' ' {
+			t.Fatalf("pane b cell %d,0 = %q after writing to pane a", x, c)
+		}
+	}
+	if c := a.Buffer().GetCell(0, 0).Char; c != 'h' {
+		t.Errorf("pane a cell 0,0 = %q, want 'h'", c)
+	}
+
+	s.handleKey(DefaultSplitFocusKey)
+	if s.Focused() != 1 || a.IsFocused() || !b.IsFocused() {
+		t.Errorf("focus key should move focus to pane b")
+	}
+
+	// Both panes and the divider reach the host in one frame
+	s.render()
+	frame := out.String()
+	if !strings.Contains(frame, "│") || !strings.Contains(frame, "hello from a") {
+		t.Errorf("frame is missing the divider or pane a's text: %q", frame)
+	}
+}
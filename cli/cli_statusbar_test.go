@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+// StatusBarFunc replaces the built-in status text and receives the
+// terminal's dimensions.
+func TestCLIStatusBarFunc(t *testing.T) {
+	var got StatusInfo
+	term, err := New(Options{
+		Cols: 20, Rows: 3, Embedded: true, ShowStatusBar: true,
+		StatusBarFunc: func(info StatusInfo) string {
+			got = info
+			return "custom-status-text-that-is-too-long"
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := term.RenderToString()
+	if !strings.Contains(out, "custom-status-text-t") {
+		t.Fatalf("status row missing custom text: %q", out)
+	}
+	if strings.Contains(out, "too-long") {
+		t.Fatal("status text was not truncated to the window width")
+	}
+	if got.Cols != 20 || got.Rows != 3 {
+		t.Fatalf("StatusInfo size = %dx%d, want 20x3", got.Cols, got.Rows)
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for the tee goroutine and the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+// The output tee receives the child's raw escape bytes verbatim.
+func TestCLIOutputTee(t *testing.T) {
+	term, err := New(Options{Cols: 40, Rows: 5, Embedded: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out syncBuffer
+	term.SetOutputTee(&out)
+
+	if err := term.RunCommand("/bin/sh", "-c", `printf '\033[31mred\033[0m'; sleep 1`); err != nil {
+		t.Skipf("no PTY available: %v", err)
+	}
+	defer term.Close()
+
+	want := "\x1b[31mred\x1b[0m"
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(out.String(), want) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("tee missing raw bytes %q, got %q", want, out.String())
+}
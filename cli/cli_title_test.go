@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// With ForwardTitle, OSC 2 from the child saves the host title, sets the
+// new one on the host, and Stop restores the saved title.
+func TestCLIForwardTitle(t *testing.T) {
+	term, err := New(Options{Cols: 20, Rows: 3, Embedded: true, ForwardTitle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var host bytes.Buffer
+	term.hostOut = &host
+
+	term.FeedString("\x1b]2;build \x01x\x07")
+	if got := host.String(); got != "\x1b[22;2t\x1b]2;build x\x07" {
+		t.Fatalf("host output = %q", got)
+	}
+	if term.Buffer().GetTitle() != "build \x01x" {
+		t.Fatalf("buffer title = %q", term.Buffer().GetTitle())
+	}
+
+	host.Reset()
+	term.Stop()
+	if got := host.String(); got != "\x1b[23;2t" {
+		t.Fatalf("Stop output = %q, want title restore", got)
+	}
+}
+
+// overlapWriter fails the test if two writes are ever in progress at once
+type overlapWriter struct {
+	t    *testing.T
+	busy atomic.Bool
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	if !w.busy.CompareAndSwap(false, true) {
+		w.t.Error("host write started while another was in progress")
+		return len(p), nil
+	}
+	time.Sleep(100 * time.Microsecond)
+	w.busy.Store(false)
+	return len(p), nil
+}
+
+// A title forwarded from the parser goroutine is written between frames,
+// never in the middle of one the renderer is writing.
+func TestCLIForwardTitleBetweenFrames(t *testing.T) {
+	term, err := New(Options{Cols: 20, Rows: 3, Embedded: true, ForwardTitle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	term.hostOut = &overlapWriter{t: t}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			term.renderer.ForceFullRedraw()
+			term.renderer.Render()
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		term.FeedString("\x1b]2;title\x07")
+	}
+	wg.Wait()
+}
@@ -14,18 +14,20 @@ func TestCLIRendererVisualColumns(t *testing.T) {
 		t.Fatal(err)
 	}
 	term.FeedString("日abc")
+	term.SetFocused(true)
 
 	out := NewRenderer(term).RenderToString()
-	// 日 emits at column 1; 'a' must land at visual column 3 (not logical 2).
-	if !strings.Contains(out, "\033[1;3H") {
+	// 日 emits at column 1 and the host advances two columns, so 'a' follows
+	// it directly and lands at visual column 3 (not logical 2).
+	if !strings.Contains(out, "\033[H") || !strings.Contains(out, "日abc") {
 		t.Fatalf("'a' should emit at visual column 3, got %q", out)
 	}
 	// Nothing may address column 2 on row 1 — that is 日's right half.
-	if strings.Contains(out, "\033[1;2H") {
+	if strings.Contains(out, "\033[1;2H") || strings.Contains(out, "\033[2G") {
 		t.Fatalf("the wide glyph's right half must never be addressed, got %q", out)
 	}
 	// Hardware cursor after "日abc" sits at visual column 6 (2+1+1+1 -> col 6).
-	if !strings.Contains(out, "\033[1;6H") {
+	if !strings.HasSuffix(out, "\033[1;6H\033[?25h") {
 		t.Fatalf("cursor should park at visual column 6, got %q", out)
 	}
 }
@@ -0,0 +1,40 @@
+package cli
+
+import "strings"
+
+// DetectColorDepth guesses how many colors the host terminal shows from
+// its environment, read through getenv (normally os.Getenv):
+//
+//   - COLORTERM=truecolor or 24bit, a TERM naming direct/truecolor
+//     support, or Windows Terminal (WT_SESSION): 24 (true color)
+//   - a TERM containing "256color", or no TERM at all: 256
+//   - TERM=dumb: 0 (no color)
+//   - any other TERM: 16
+func DetectColorDepth(getenv func(string) string) int {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return 24
+	}
+	if getenv("WT_SESSION") != "" {
+		return 24
+	}
+
+	termType := strings.ToLower(getenv("TERM"))
+	switch {
+	case strings.Contains(termType, "direct"), strings.Contains(termType, "truecolor"),
+		strings.Contains(termType, "24bit"):
+		return 24
+	case strings.Contains(termType, "256color"), termType == "":
+		return 256
+	case termType == "dumb":
+		return 0
+	}
+	return 16
+}
+
+// HostColorDepth returns the number of colors rendering assumes the host
+// terminal shows (24, 256, 16, 8 or 0), from Options.ColorDepth or
+// DetectColorDepth
+func (t *Terminal) HostColorDepth() int {
+	return t.hostColorDepth
+}
@@ -11,9 +11,10 @@
 //   - Scrollback buffer with Shift+PageUp/PageDown navigation
 //   - Multiple border styles (single, double, heavy, rounded)
 //   - Optional status bar showing cursor position and scroll status
-//   - Window resizing that tracks the host terminal (SIGWINCH)
+//   - Window resizing that tracks the host terminal (SIGWINCH; polled on Windows)
 //   - Differential rendering for efficiency (only updates changed cells)
-//   - True color (24-bit) and 256-color support
+//   - True color (24-bit) and 256-color support, downsampled to what the host
+//     terminal shows (Options.ColorDepth, detected from COLORTERM/TERM)
 //   - Full attribute support: bold, italic, underline, strikethrough, blink, reverse
 //
 // # Basic Usage
@@ -46,6 +47,17 @@
 //	// Wait for shell to exit
 //	term.Wait()
 //
+// RunGuarded does the Start and Stop for you, and also restores the host
+// terminal if the function panics:
+//
+//	err = cli.RunGuarded(term, func() error {
+//	    if err := term.RunShell(); err != nil {
+//	        return err
+//	    }
+//	    term.Wait()
+//	    return nil
+//	})
+//
 // # Scrollback Navigation
 //
 // While running, the following keys navigate the scrollback buffer:
@@ -57,7 +69,32 @@
 //   - Shift+Home: Jump to top of scrollback
 //   - Shift+End: Jump to bottom (current output)
 //
-// Any regular input automatically scrolls to the bottom.
+// Any regular input automatically scrolls to the bottom. Options.ScrollKeys
+// rebinds these actions, e.g. for host terminals that keep Shift+PageUp:
+//
+//	opts.ScrollKeys = map[string]cli.ScrollAction{
+//	    "C-M-Up":       cli.ScrollLineUp,
+//	    "C-M-Down":     cli.ScrollLineDown,
+//	    "C-M-PageUp":   cli.ScrollPageUp,
+//	    "C-M-PageDown": cli.ScrollPageDown,
+//	}
+//
+// # Split Panes
+//
+// NewSplit runs two terminals in one host screen, side by side
+// (SplitVertical) or stacked (SplitHorizontal), with a one-cell divider.
+// Each pane is an embedded Terminal with its own buffer and PTY; Ctrl+]
+// (SplitOptions.FocusKey) switches which pane receives keyboard input.
+//
+//	split, err := cli.NewSplit(cli.SplitOptions{Orientation: cli.SplitVertical})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	split.Start()
+//	defer split.Stop()
+//	split.Pane(0).RunShell()
+//	split.Pane(1).RunCommand("top")
+//	split.Wait()
 //
 // # Architecture
 //
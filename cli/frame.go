@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/phroun/purfecterm"
+)
+
+// frameWriter emits the cells of one frame the way curses would: it tracks
+// where the host cursor is and which attributes are active, so each cell
+// costs only the bytes needed to reach it (the cheapest of CUP, CUF/CUB,
+// CUU/CUD or CR, or nothing when the cursor is already there) and an SGR
+// only when the attributes actually change.
+type frameWriter struct {
+	out *strings.Builder
+
+	// Host cursor position (0-based); row -1 means unknown
+	row, col int
+
+	// Colors the host can show (24, 256, 16, 8 or 0); others are downsampled
+	colorDepth int
+
+	// Active host attributes; firstAttr means nothing has been set yet
+	firstAttr     bool
+	fg, bg        purfecterm.Color
+	bold          bool
+	italic        bool
+	underline     bool
+	reverse       bool
+	blink         bool
+	strikethrough bool
+	fraktur       bool
+}
+
+func newFrameWriter(out *strings.Builder) *frameWriter {
+	return &frameWriter{out: out, row: -1, colorDepth: 24, firstAttr: true}
+}
+
+// invalidate forgets the cursor position, for output written around the
+// frameWriter (borders, status bar)
+func (w *frameWriter) invalidate() {
+	w.row = -1
+}
+
+// moveTo positions the host cursor at (row, col) with the shortest sequence
+func (w *frameWriter) moveTo(row, col int) {
+	if row == w.row && col == w.col {
+		return
+	}
+	best := cupSeq(row, col)
+	if w.row >= 0 {
+		var rel string
+		switch {
+		case row < w.row:
+			rel = csiN(w.row-row, 'A')
+		case row > w.row:
+			rel = csiN(row-w.row, 'B')
+		}
+		rel += horizSeq(w.col, col)
+		if len(rel) < len(best) {
+			best = rel
+		}
+	}
+	w.out.WriteString(best)
+	w.row, w.col = row, col
+}
+
+// cupSeq returns the absolute CUP sequence for (row, col), dropping
+// parameters that equal the default of 1
+func cupSeq(row, col int) string {
+	switch {
+	case row == 0 && col == 0:
+		return "\033[H"
+	case col == 0:
+		return "\033[" + strconv.Itoa(row+1) + "H"
+	}
+	return "\033[" + strconv.Itoa(row+1) + ";" + strconv.Itoa(col+1) + "H"
+}
+
+// horizSeq returns the shortest move within a row from column from to to
+func horizSeq(from, to int) string {
+	switch {
+	case to == from:
+		return ""
+	case to > from:
+		return csiN(to-from, 'C')
+	case to == 0:
+		return "\r"
+	}
+	back := csiN(from-to, 'D')
+	if cr := "\r" + csiN(to, 'C'); len(cr) < len(back) {
+		return cr
+	}
+	return back
+}
+
+// csiN returns CSI n final, omitting a count of 1
+func csiN(n int, final byte) string {
+	if n == 1 {
+		return "\033[" + string(final)
+	}
+	return "\033[" + strconv.Itoa(n) + string(final)
+}
+
+// setAttrs emits the SGR needed to switch from the active attributes to
+// those of cell with the resolved colors fg and bg, if any
+func (w *frameWriter) setAttrs(cell *purfecterm.Cell, fg, bg purfecterm.Color) {
+	var sgr []string
+	fg, bg = fg.Downsample(w.colorDepth), bg.Downsample(w.colorDepth)
+
+	// Check if we need to reset
+	needsReset := false
+	if !w.firstAttr {
+		if (w.bold && !cell.Bold) ||
+			(w.italic && !cell.Italic) ||
+			(w.underline && !cell.Underline) ||
+			(w.reverse && !cell.Reverse) ||
+			(w.blink && !cell.Blink) ||
+			(w.strikethrough && !cell.Strikethrough) ||
+			(w.fraktur && cell.Font != purfecterm.VTFrakturSlot) {
+			needsReset = true
+		}
+	}
+
+	if needsReset || w.firstAttr {
+		sgr = append(sgr, "0") // Reset
+		w.bold = false
+		w.italic = false
+		w.underline = false
+		w.reverse = false
+		w.blink = false
+		w.strikethrough = false
+		w.fraktur = false
+		w.fg = purfecterm.Color{}
+		w.bg = purfecterm.Color{}
+	}
+	w.firstAttr = false
+
+	// Add attributes
+	if cell.Bold && !w.bold {
+		sgr = append(sgr, "1")
+		w.bold = true
+	}
+	if cell.Italic && !w.italic {
+		sgr = append(sgr, "3")
+		w.italic = true
+	}
+	if cell.Underline && !w.underline {
+		sgr = append(sgr, "4")
+		w.underline = true
+	}
+	if cell.Blink && !w.blink {
+		sgr = append(sgr, "5")
+		w.blink = true
+	}
+	if cell.Strikethrough && !w.strikethrough {
+		sgr = append(sgr, "9")
+		w.strikethrough = true
+	}
+	// SGR 20 fraktur: a font-slot-10 (VTFRAKTUR) cell emits real fraktur.
+	if cell.Font == purfecterm.VTFrakturSlot && !w.fraktur {
+		sgr = append(sgr, "20")
+		w.fraktur = true
+	}
+
+	// Add colors
+	if fg != w.fg {
+		sgr = append(sgr, fg.ToSGRCode(true))
+		w.fg = fg
+	}
+	if bg != w.bg {
+		sgr = append(sgr, bg.ToSGRCode(false))
+		w.bg = bg
+	}
+
+	// Write SGR sequence if needed
+	if len(sgr) > 0 {
+		w.out.WriteString("\033[")
+		w.out.WriteString(strings.Join(sgr, ";"))
+		w.out.WriteString("m")
+	}
+}
+
+// writeCell emits cell at (row, col) and advances the tracked cursor by the
+// columns the host terminal moves
+func (w *frameWriter) writeCell(row, col int, cell *purfecterm.Cell, fg, bg purfecterm.Color) {
+	w.moveTo(row, col)
+	w.setAttrs(cell, fg, bg)
+
+	// Write character (concealed cells show blanks over their full width)
+	switch {
+	case cell.Conceal:
+		n := hostCellWidth(cell)
+		w.out.WriteString(strings.Repeat(" ", n))
+		w.col += n
+	case cell.Char == 0 || cell.Char == ' ':
+		w.out.WriteRune(' ')
+		w.col++
+	default:
+		w.out.WriteRune(cell.Char)
+		if cell.Combining != "" {
+			w.out.WriteString(cell.Combining)
+		}
+		w.col += hostCellWidth(cell)
+		// Hosts disagree on ambiguous-width glyphs; don't move relative to one
+		if cell.Char >= 0x80 && purfecterm.GetEastAsianWidth(cell.Char) < 0 {
+			w.invalidate()
+		}
+	}
+}
+
+// clearToEOL erases from (row, col) to the end of the host line with bg
+// (back color erase), standing in for a run of blank cells
+func (w *frameWriter) clearToEOL(row, col int, cell *purfecterm.Cell, fg, bg purfecterm.Color) {
+	w.moveTo(row, col)
+	w.setAttrs(cell, fg, bg)
+	w.out.WriteString("\033[K")
+}
+
+// isPlainBlank reports whether cell looks the same as an erased cell with
+// its background, so EL can draw it
+func isPlainBlank(cell *purfecterm.Cell) bool {
+	return (cell.Char == 0 || cell.Char == ' ') && cell.Combining == "" &&
+		!cell.Continuation && hostCellWidth(cell) == 1 &&
+		!cell.Underline && !cell.Strikethrough
+}
@@ -74,6 +74,9 @@ func (h *InputHandler) handleKey(key string) bool {
 
 	// Convert key to bytes for the callback
 	keyBytes := keyToBytes(key)
+	if key == "Enter" {
+		keyBytes = h.term.buffer.EnterKeySequence() // CR, or CR LF under LNM
+	}
 	if callback != nil && len(keyBytes) > 0 {
 		if callback(keyBytes) {
 			return true // Consumed by callback
@@ -100,61 +103,75 @@ func (h *InputHandler) handleKey(key string) bool {
 	return false
 }
 
+// ScrollAction is a scrollback navigation action bound to a key in
+// Options.ScrollKeys
+type ScrollAction int
+
+const (
+	ScrollLineUp   ScrollAction = iota // Scroll up one line
+	ScrollLineDown                     // Scroll down one line
+	ScrollPageUp                       // Scroll up one page
+	ScrollPageDown                     // Scroll down one page
+	ScrollTop                          // Jump to the top of scrollback
+	ScrollBottom                       // Jump to the bottom (current output)
+)
+
+// defaultScrollKeys are the bindings used when Options.ScrollKeys is nil
+var defaultScrollKeys = map[string]ScrollAction{
+	"S-PageUp":   ScrollPageUp,
+	"S-PageDown": ScrollPageDown,
+	"S-Up":       ScrollLineUp,
+	"S-Down":     ScrollLineDown,
+	"S-Home":     ScrollTop,
+	"S-End":      ScrollBottom,
+}
+
+// DefaultScrollKeys returns a copy of the default scrollback bindings
+// (Shift+PageUp/PageDown/Up/Down/Home/End), for extending in
+// Options.ScrollKeys
+func DefaultScrollKeys() map[string]ScrollAction {
+	keys := make(map[string]ScrollAction, len(defaultScrollKeys))
+	for k, a := range defaultScrollKeys {
+		keys[k] = a
+	}
+	return keys
+}
+
 // handleLocalKey handles keys that are processed by the CLI adapter locally
 // Returns true if the key was handled
 func (h *InputHandler) handleLocalKey(key string) bool {
-	switch key {
-	case "S-PageUp":
-		// Scroll up one page
-		_, rows := h.term.buffer.GetSize()
-		h.term.ScrollUp(rows - 1)
-		h.term.renderer.RequestRender()
-		return true
-
-	case "S-PageDown":
-		// Scroll down one page
-		_, rows := h.term.buffer.GetSize()
-		h.term.ScrollDown(rows - 1)
-		h.term.renderer.RequestRender()
-		return true
+	keys := h.term.options.ScrollKeys
+	if keys == nil {
+		keys = defaultScrollKeys
+	}
+	action, ok := keys[key]
+	if !ok {
+		return false
+	}
 
-	case "S-Up":
-		// Scroll up one line
+	switch action {
+	case ScrollPageUp:
+		h.term.buffer.ScrollViewPages(1)
+	case ScrollPageDown:
+		h.term.buffer.ScrollViewPages(-1)
+	case ScrollLineUp:
 		h.term.ScrollUp(1)
-		h.term.renderer.RequestRender()
-		return true
-
-	case "S-Down":
-		// Scroll down one line
+	case ScrollLineDown:
 		h.term.ScrollDown(1)
-		h.term.renderer.RequestRender()
-		return true
-
-	case "S-Home":
-		// Scroll to top
+	case ScrollTop:
 		h.term.ScrollToTop()
-		h.term.renderer.RequestRender()
-		return true
-
-	case "S-End":
-		// Scroll to bottom
+	case ScrollBottom:
 		h.term.ScrollToBottom()
-		h.term.renderer.RequestRender()
-		return true
+	default:
+		return false
 	}
-
-	return false
+	h.term.renderer.RequestRender()
+	return true
 }
 
 // sendToPTY sends data to the child process
 func (h *InputHandler) sendToPTY(data []byte) {
-	h.term.mu.Lock()
-	pty := h.term.pty
-	h.term.mu.Unlock()
-
-	if pty != nil {
-		pty.Write(data)
-	}
+	h.term.Write(data)
 }
 
 // keyToBytes converts a key name from direct-key-handler to bytes for PTY.
@@ -481,8 +498,7 @@ func (h *InputHandler) handleMouseKey(key string) bool {
 		}
 		btn |= purfecterm.MouseMotionFlag
 		btn |= mouseModsFromKey(key)
-		encodingMode := h.term.buffer.GetMouseEncodingMode()
-		data := purfecterm.EncodeMouseEvent(btn, innerX, innerY, true, encodingMode)
+		data := h.term.buffer.EncodeMouseEvent(btn, innerX, innerY, true)
 		if data != nil {
 			h.sendToPTY(data)
 		}
@@ -532,8 +548,7 @@ func (h *InputHandler) handleMouseKey(key string) bool {
 	}
 
 	btn |= mods
-	encodingMode := h.term.buffer.GetMouseEncodingMode()
-	data := purfecterm.EncodeMouseEvent(btn, innerX, innerY, press, encodingMode)
+	data := h.term.buffer.EncodeMouseEvent(btn, innerX, innerY, press)
 	if data != nil {
 		h.sendToPTY(data)
 	}
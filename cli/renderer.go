@@ -2,7 +2,7 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"sync"
 	"time"
@@ -21,7 +21,12 @@ type Renderer struct {
 	renderTicker *time.Ticker
 
 	// Output buffer for batching writes
-	output strings.Builder
+	output         strings.Builder
+	lastFrameBytes int // Bytes written by the last Render (see LastFrameBytes)
+
+	// Serializes writes to the host terminal, so a sequence written from
+	// another goroutine (see Terminal.forwardTitle) cannot split a frame
+	hostMu sync.Mutex
 
 	// Border characters
 	borderChars borderCharSet
@@ -40,6 +45,22 @@ type renderedCell struct {
 	reverse       bool
 	blink         bool
 	strikethrough bool
+	conceal       bool
+}
+
+// sameAs reports whether c draws identically to prev
+func (c renderedCell) sameAs(prev renderedCell) bool {
+	return prev.char == c.char &&
+		prev.combining == c.combining &&
+		prev.cellWidth == c.cellWidth &&
+		prev.fg == c.fg &&
+		prev.bg == c.bg &&
+		prev.bold == c.bold &&
+		prev.italic == c.italic &&
+		prev.underline == c.underline &&
+		prev.blink == c.blink &&
+		prev.strikethrough == c.strikethrough &&
+		prev.conceal == c.conceal
 }
 
 // borderCharSet contains the characters for drawing borders
@@ -79,6 +100,9 @@ var borderStyles = map[BorderStyle]borderCharSet{
 // takes two columns; fractional flex widths quantize to one (a host terminal
 // cannot render halves).
 func hostCellWidth(cell *purfecterm.Cell) int {
+	if cell.Continuation {
+		return 0 // Covered by the preceding wide glyph
+	}
 	if cell.CellWidth >= 1.5 {
 		return 2
 	}
@@ -105,6 +129,16 @@ func (r *Renderer) RequestRender() {
 	r.mu.Unlock()
 }
 
+// takeRenderRequest reports whether a render was requested, clearing the
+// request
+func (r *Renderer) takeRenderRequest() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	needsRender := r.renderNeeded
+	r.renderNeeded = false
+	return needsRender
+}
+
 // RenderLoop runs the main render loop
 func (r *Renderer) RenderLoop() {
 	// Render at ~60fps max, but only when needed
@@ -114,12 +148,7 @@ func (r *Renderer) RenderLoop() {
 	for {
 		select {
 		case <-r.renderTicker.C:
-			r.mu.Lock()
-			needsRender := r.renderNeeded
-			r.renderNeeded = false
-			r.mu.Unlock()
-
-			if needsRender {
+			if r.takeRenderRequest() {
 				r.Render()
 			}
 		case <-r.term.stopRender:
@@ -134,6 +163,7 @@ func (r *Renderer) Render() {
 	opts := r.term.options
 	buffer := r.term.buffer
 	r.term.mu.Unlock()
+	opts.Scheme = buffer.EffectiveScheme(opts.Scheme)
 
 	cols, rows := buffer.GetSize()
 	cursorX, cursorY := buffer.GetCursor()
@@ -174,36 +204,44 @@ func (r *Renderer) Render() {
 		newCells[y] = make([]renderedCell, cols)
 	}
 
-	// Current attributes for SGR optimization
-	var currentFg, currentBg purfecterm.Color
-	currentBold := false
-	currentItalic := false
-	currentUnderline := false
-	currentReverse := false
-	currentBlink := false
-	currentStrikethrough := false
-	currentFraktur := false
-	firstAttr := true
+	fw := newFrameWriter(&r.output)
+	fw.colorDepth = r.term.hostColorDepth
+
+	// Host EL clears to the right edge of the HOST screen, so it can only
+	// stand in for trailing blanks when the content reaches that edge
+	useEL := !opts.Embedded && contentStartX == 0
+	if useEL {
+		hostCols, _ := getHostTerminalSize()
+		useEL = cols >= hostCols
+	}
 
-	// Render each cell. vx tracks the VISUAL column where the cell lands on
+	// Render each row. vx tracks the VISUAL column where a cell lands on
 	// the host terminal (a wide cell advances it by two): cursor addressing
 	// into a real terminal must use visual columns, or everything after a
 	// wide glyph paints one column early and CUPs stomp glyph halves.
+	cells := make([]purfecterm.Cell, cols)
+	emitCols := make([]int, cols)
+	changed := make([]bool, cols)
 	for y := 0; y < rows; y++ {
 		rowChanged := needsFullRender
 		if !needsFullRender && len(prevCells[y]) != cols {
 			rowChanged = true
 		}
 
+		// Resolve the row and find which cells differ from the last frame
 		vx := 0
 		for x := 0; x < cols; x++ {
 			cell := buffer.GetVisibleCell(x, y)
-			emitCol := vx
+			cells[x] = cell
+			emitCols[x] = vx
 			vx += hostCellWidth(&cell)
+			changed[x] = false
+			if cell.Continuation {
+				continue
+			}
 
 			// Resolve colors based on theme
-			fg := opts.Scheme.ResolveColor(cell.Foreground, true, isDark)
-			bg := opts.Scheme.ResolveColor(cell.Background, false, isDark)
+			fg, bg := opts.Scheme.ResolveCellColors(&cell, isDark)
 
 			// Handle reverse video
 			if cell.Reverse {
@@ -211,7 +249,7 @@ func (r *Renderer) Render() {
 			}
 
 			// Store for next frame comparison
-			newCells[y][x] = renderedCell{
+			rc := renderedCell{
 				char:          cell.Char,
 				combining:     cell.Combining,
 				cellWidth:     cell.CellWidth,
@@ -223,112 +261,45 @@ func (r *Renderer) Render() {
 				reverse:       cell.Reverse,
 				blink:         cell.Blink,
 				strikethrough: cell.Strikethrough,
+				conceal:       cell.Conceal,
 			}
+			newCells[y][x] = rc
 
 			// Check if cell changed
-			if !rowChanged && !needsFullRender {
-				prev := prevCells[y][x]
-				if prev.char == cell.Char &&
-					prev.combining == cell.Combining &&
-					prev.cellWidth == cell.CellWidth &&
-					prev.fg == fg &&
-					prev.bg == bg &&
-					prev.bold == cell.Bold &&
-					prev.italic == cell.Italic &&
-					prev.underline == cell.Underline &&
-					prev.blink == cell.Blink &&
-					prev.strikethrough == cell.Strikethrough {
-					continue
-				}
-			}
-
-			// Move cursor to position (visual column)
-			r.output.WriteString(fmt.Sprintf("\033[%d;%dH", contentStartY+y+1, contentStartX+emitCol+1))
-
-			// Build SGR sequence for attributes
-			var sgr []string
-
-			// Check if we need to reset
-			needsReset := false
-			if !firstAttr {
-				if (currentBold && !cell.Bold) ||
-					(currentItalic && !cell.Italic) ||
-					(currentUnderline && !cell.Underline) ||
-					(currentReverse && !cell.Reverse) ||
-					(currentBlink && !cell.Blink) ||
-					(currentStrikethrough && !cell.Strikethrough) ||
-					(currentFraktur && cell.Font != purfecterm.VTFrakturSlot) {
-					needsReset = true
-				}
-			}
-
-			if needsReset || firstAttr {
-				sgr = append(sgr, "0") // Reset
-				currentBold = false
-				currentItalic = false
-				currentUnderline = false
-				currentReverse = false
-				currentBlink = false
-				currentStrikethrough = false
-				currentFraktur = false
-				currentFg = purfecterm.Color{}
-				currentBg = purfecterm.Color{}
-			}
-			firstAttr = false
-
-			// Add attributes
-			if cell.Bold && !currentBold {
-				sgr = append(sgr, "1")
-				currentBold = true
-			}
-			if cell.Italic && !currentItalic {
-				sgr = append(sgr, "3")
-				currentItalic = true
-			}
-			if cell.Underline && !currentUnderline {
-				sgr = append(sgr, "4")
-				currentUnderline = true
-			}
-			if cell.Blink && !currentBlink {
-				sgr = append(sgr, "5")
-				currentBlink = true
-			}
-			if cell.Strikethrough && !currentStrikethrough {
-				sgr = append(sgr, "9")
-				currentStrikethrough = true
-			}
-			// SGR 20 fraktur: a font-slot-10 (VTFRAKTUR) cell emits real fraktur.
-			if cell.Font == purfecterm.VTFrakturSlot && !currentFraktur {
-				sgr = append(sgr, "20")
-				currentFraktur = true
-			}
-
-			// Add colors
-			if fg != currentFg {
-				sgr = append(sgr, fg.ToSGRCode(true))
-				currentFg = fg
-			}
-			if bg != currentBg {
-				sgr = append(sgr, bg.ToSGRCode(false))
-				currentBg = bg
-			}
+			changed[x] = rowChanged || !rc.sameAs(prevCells[y][x])
+		}
 
-			// Write SGR sequence if needed
-			if len(sgr) > 0 {
-				r.output.WriteString("\033[")
-				r.output.WriteString(strings.Join(sgr, ";"))
-				r.output.WriteString("m")
+		// Trailing blanks sharing one background can be drawn with EL
+		tail := cols
+		if useEL {
+			for tail > 0 && !cells[tail-1].Continuation && isPlainBlank(&cells[tail-1]) &&
+				newCells[y][tail-1].bg == newCells[y][cols-1].bg {
+				tail--
 			}
+		}
 
-			// Write character
-			if cell.Char == 0 || cell.Char == ' ' {
-				r.output.WriteRune(' ')
-			} else {
-				r.output.WriteRune(cell.Char)
-				if cell.Combining != "" {
-					r.output.WriteString(cell.Combining)
+		for x := 0; x < cols; x++ {
+			if !changed[x] {
+				continue
+			}
+			rc := newCells[y][x]
+			if x >= tail {
+				// EL costs three bytes; use it once it beats the spaces
+				pending := 0
+				for _, c := range changed[x:] {
+					if c {
+						pending++
+					}
+				}
+				if pending > 3 {
+					fw.clearToEOL(contentStartY+y, contentStartX+emitCols[x], &cells[x], rc.fg, rc.bg)
+					break
 				}
 			}
+			fw.writeCell(contentStartY+y, contentStartX+emitCols[x], &cells[x], rc.fg, rc.bg)
+			if emitCols[x]+hostCellWidth(&cells[x]) >= vx {
+				fw.invalidate() // Right edge: the host may be holding a pending wrap
+			}
 		}
 	}
 
@@ -352,10 +323,31 @@ func (r *Renderer) Render() {
 	}
 
 	// Flush output
-	os.Stdout.WriteString(r.output.String())
+	r.writeHost(r.output.String())
 
 	// Store current frame
 	r.lastCells = newCells
+	r.mu.Lock()
+	r.lastFrameBytes = r.output.Len()
+	r.mu.Unlock()
+}
+
+// writeHost writes s to the host terminal in one piece, between frames
+func (r *Renderer) writeHost(s string) {
+	r.hostMu.Lock()
+	defer r.hostMu.Unlock()
+	r.term.mu.Lock()
+	out := r.term.hostOut
+	r.term.mu.Unlock()
+	io.WriteString(out, s)
+}
+
+// LastFrameBytes returns how many bytes the most recent Render wrote to the
+// host terminal, a measure of how well differential rendering is working
+func (r *Renderer) LastFrameBytes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastFrameBytes
 }
 
 // renderBorder draws the terminal window border
@@ -429,19 +421,30 @@ func (r *Renderer) renderBorder(x, y, innerCols, innerRows int, title string, sc
 	r.output.WriteRune(bc.bottomRight)
 }
 
-// renderStatusBar draws the status bar at the bottom
-func (r *Renderer) renderStatusBar(x, y, width int, scrollOffset int) {
-	r.output.WriteString(fmt.Sprintf("\033[%d;%dH", y+1, x+1))
-
-	// Status bar style: reversed colors
-	r.output.WriteString("\033[7m")
+// statusText builds the status bar text, padded or truncated to width.
+// Options.StatusBarFunc supplies the text when set; otherwise the built-in
+// cursor/scroll summary is used.
+func (r *Renderer) statusText(width int, scrollOffset int) string {
+	r.term.mu.Lock()
+	statusFunc := r.term.options.StatusBarFunc
+	title := r.term.options.Title
+	r.term.mu.Unlock()
 
 	cols, rows := r.term.buffer.GetSize()
 	cursorX, cursorY := r.term.buffer.GetCursor()
 
-	// Build status text
 	var status string
-	if scrollOffset > 0 {
+	if statusFunc != nil {
+		status = statusFunc(StatusInfo{
+			CursorX:        cursorX,
+			CursorY:        cursorY,
+			ScrollOffset:   scrollOffset,
+			ScrollbackSize: r.term.buffer.GetScrollbackSize(),
+			Title:          title,
+			Cols:           cols,
+			Rows:           rows,
+		})
+	} else if scrollOffset > 0 {
 		maxScroll := r.term.buffer.GetMaxScrollOffset()
 		percent := 100 - (scrollOffset * 100 / maxScroll)
 		status = fmt.Sprintf(" [%d%%] Lines: %d | Cursor: %d,%d | Size: %dx%d ",
@@ -451,12 +454,22 @@ func (r *Renderer) renderStatusBar(x, y, width int, scrollOffset int) {
 			r.term.buffer.GetScrollbackSize(), cursorX+1, cursorY+1, cols, rows)
 	}
 
-	// Pad to full width
-	if len(status) < width {
-		status = status + strings.Repeat(" ", width-len(status))
-	} else if len(status) > width {
-		status = status[:width]
+	// Pad or truncate to full width (counted in runes)
+	runes := []rune(status)
+	if len(runes) < width {
+		return status + strings.Repeat(" ", width-len(runes))
 	}
+	return string(runes[:width])
+}
+
+// renderStatusBar draws the status bar at the bottom
+func (r *Renderer) renderStatusBar(x, y, width int, scrollOffset int) {
+	r.output.WriteString(fmt.Sprintf("\033[%d;%dH", y+1, x+1))
+
+	// Status bar style: reversed colors
+	r.output.WriteString("\033[7m")
+
+	status := r.statusText(width, scrollOffset)
 
 	r.output.WriteString(status)
 	r.output.WriteString("\033[27m") // End reverse video
@@ -490,6 +503,7 @@ func (r *Renderer) RenderToString() string {
 	clipEnabled := r.term.clipEnabled
 	clipRect := r.term.clipRect
 	r.term.mu.Unlock()
+	opts.Scheme = buffer.EffectiveScheme(opts.Scheme)
 
 	cols, rows := buffer.GetSize()
 	cursorX, cursorY := buffer.GetCursor()
@@ -524,16 +538,8 @@ func (r *Renderer) RenderToString() string {
 		}
 	}
 
-	// Current attributes for SGR optimization
-	var currentFg, currentBg purfecterm.Color
-	currentBold := false
-	currentItalic := false
-	currentUnderline := false
-	currentReverse := false
-	currentBlink := false
-	currentStrikethrough := false
-	currentFraktur := false
-	firstAttr := true
+	fw := newFrameWriter(&output)
+	fw.colorDepth = r.term.hostColorDepth
 
 	// Render each cell (vx = visual column on the host terminal; see Render).
 	for y := 0; y < rows; y++ {
@@ -544,107 +550,24 @@ func (r *Renderer) RenderToString() string {
 			screenX := contentStartX + vx + 1
 			screenY := contentStartY + y + 1
 			vx += hostCellWidth(&cell)
+			if cell.Continuation {
+				continue
+			}
 			if clipEnabled && !clipRect.Contains(screenX-1, screenY-1) {
 				continue // Skip cells outside clip rectangle
 			}
 
 			// Resolve colors based on theme
-			fg := opts.Scheme.ResolveColor(cell.Foreground, true, isDark)
-			bg := opts.Scheme.ResolveColor(cell.Background, false, isDark)
+			fg, bg := opts.Scheme.ResolveCellColors(&cell, isDark)
 
 			// Handle reverse video
 			if cell.Reverse {
 				fg, bg = bg, fg
 			}
 
-			// Move cursor to position
-			output.WriteString(fmt.Sprintf("\033[%d;%dH", screenY, screenX))
-
-			// Build SGR sequence for attributes
-			var sgr []string
-
-			// Check if we need to reset
-			needsReset := false
-			if !firstAttr {
-				if (currentBold && !cell.Bold) ||
-					(currentItalic && !cell.Italic) ||
-					(currentUnderline && !cell.Underline) ||
-					(currentReverse && !cell.Reverse) ||
-					(currentBlink && !cell.Blink) ||
-					(currentStrikethrough && !cell.Strikethrough) ||
-					(currentFraktur && cell.Font != purfecterm.VTFrakturSlot) {
-					needsReset = true
-				}
-			}
-
-			if needsReset || firstAttr {
-				sgr = append(sgr, "0") // Reset
-				currentBold = false
-				currentItalic = false
-				currentUnderline = false
-				currentReverse = false
-				currentBlink = false
-				currentStrikethrough = false
-				currentFraktur = false
-				currentFg = purfecterm.Color{}
-				currentBg = purfecterm.Color{}
-			}
-			firstAttr = false
-
-			// Add attributes
-			if cell.Bold && !currentBold {
-				sgr = append(sgr, "1")
-				currentBold = true
-			}
-			if cell.Italic && !currentItalic {
-				sgr = append(sgr, "3")
-				currentItalic = true
-			}
-			if cell.Underline && !currentUnderline {
-				sgr = append(sgr, "4")
-				currentUnderline = true
-			}
-			if cell.Blink && !currentBlink {
-				sgr = append(sgr, "5")
-				currentBlink = true
-			}
-			if cell.Strikethrough && !currentStrikethrough {
-				sgr = append(sgr, "9")
-				currentStrikethrough = true
-			}
-			// SGR 20 fraktur: a font-slot-10 (VTFRAKTUR) cell emits real fraktur.
-			if cell.Font == purfecterm.VTFrakturSlot && !currentFraktur {
-				sgr = append(sgr, "20")
-				currentFraktur = true
-			}
-
-			// Add colors
-			if fg != currentFg {
-				sgr = append(sgr, fg.ToSGRCode(true))
-				currentFg = fg
-			}
-			if bg != currentBg {
-				sgr = append(sgr, bg.ToSGRCode(false))
-				currentBg = bg
-			}
-
-			// Write SGR sequence if needed
-			if len(sgr) > 0 {
-				output.WriteString("\033[")
-				output.WriteString(strings.Join(sgr, ";"))
-				output.WriteString("m")
-			}
-
-			// Write character
-			if cell.Char == 0 || cell.Char == ' ' {
-				output.WriteRune(' ')
-			} else {
-				output.WriteRune(cell.Char)
-				if cell.Combining != "" {
-					output.WriteString(cell.Combining)
-				}
-			}
+			fw.writeCell(screenY-1, screenX-1, &cell, fg, bg)
 		}
+		fw.invalidate() // Row end: the host may be holding a pending wrap
 	}
 
 	// Render status bar if configured (check clipping)
@@ -753,27 +676,7 @@ func (r *Renderer) renderStatusBarTo(output *strings.Builder, x, y, width int, s
 	// Status bar style: reversed colors
 	output.WriteString("\033[7m")
 
-	cols, rows := r.term.buffer.GetSize()
-	cursorX, cursorY := r.term.buffer.GetCursor()
-
-	// Build status text
-	var status string
-	if scrollOffset > 0 {
-		maxScroll := r.term.buffer.GetMaxScrollOffset()
-		percent := 100 - (scrollOffset * 100 / maxScroll)
-		status = fmt.Sprintf(" [%d%%] Lines: %d | Cursor: %d,%d | Size: %dx%d ",
-			percent, r.term.buffer.GetScrollbackSize(), cursorX+1, cursorY+1, cols, rows)
-	} else {
-		status = fmt.Sprintf(" Lines: %d | Cursor: %d,%d | Size: %dx%d ",
-			r.term.buffer.GetScrollbackSize(), cursorX+1, cursorY+1, cols, rows)
-	}
-
-	// Pad to full width
-	if len(status) < width {
-		status = status + strings.Repeat(" ", width-len(status))
-	} else if len(status) > width {
-		status = status[:width]
-	}
+	status := r.statusText(width, scrollOffset)
 
 	output.WriteString(status)
 	output.WriteString("\033[27m") // End reverse video
@@ -850,30 +753,10 @@ func (r *Renderer) renderBorderToClipped(output *strings.Builder, x, y, innerCol
 
 // renderStatusBarToClipped draws the status bar with clipping
 func (r *Renderer) renderStatusBarToClipped(output *strings.Builder, x, y, width int, scrollOffset int, clip Rect) {
-	cols, rows := r.term.buffer.GetSize()
-	cursorX, cursorY := r.term.buffer.GetCursor()
-
-	// Build status text
-	var status string
-	if scrollOffset > 0 {
-		maxScroll := r.term.buffer.GetMaxScrollOffset()
-		percent := 100 - (scrollOffset * 100 / maxScroll)
-		status = fmt.Sprintf(" [%d%%] Lines: %d | Cursor: %d,%d | Size: %dx%d ",
-			percent, r.term.buffer.GetScrollbackSize(), cursorX+1, cursorY+1, cols, rows)
-	} else {
-		status = fmt.Sprintf(" Lines: %d | Cursor: %d,%d | Size: %dx%d ",
-			r.term.buffer.GetScrollbackSize(), cursorX+1, cursorY+1, cols, rows)
-	}
-
-	// Pad to full width
-	if len(status) < width {
-		status = status + strings.Repeat(" ", width-len(status))
-	} else if len(status) > width {
-		status = status[:width]
-	}
+	status := r.statusText(width, scrollOffset)
 
 	// Render only visible characters
-	for i, ch := range status {
+	for i, ch := range []rune(status) {
 		screenX := x + i
 		if clip.Contains(screenX, y) {
 			output.WriteString(fmt.Sprintf("\033[%d;%dH", y+1, screenX+1))
@@ -0,0 +1,56 @@
+package cli
+
+import "time"
+
+// Windows consoles have no SIGWINCH, so there the host size is polled
+// instead: every resizePollInterval the size is read (term.GetSize, which
+// asks GetConsoleScreenBufferInfo for the window size) and the usual
+// resize path runs when it differs from the last reading. A quarter second
+// keeps a drag-resize responsive while costing one console call per tick.
+// The poller is platform independent so the change detection can be
+// tested anywhere; only signal_windows.go starts it.
+
+// resizePollInterval is how often the host size is checked when polling
+const resizePollInterval = 250 * time.Millisecond
+
+// sizePoller reports host terminal size changes between readings
+type sizePoller struct {
+	size       func() (cols, rows int)
+	cols, rows int
+}
+
+// newSizePoller starts from the current reading of size, so the first
+// poll reports only a change made after this call
+func newSizePoller(size func() (cols, rows int)) *sizePoller {
+	cols, rows := size()
+	return &sizePoller{size: size, cols: cols, rows: rows}
+}
+
+// poll reads the size and reports whether it changed since the last poll
+func (p *sizePoller) poll() bool {
+	cols, rows := p.size()
+	if cols == p.cols && rows == p.rows {
+		return false
+	}
+	p.cols, p.rows = cols, rows
+	return true
+}
+
+// pollResize calls onChange each time the size read by size changes,
+// until stop is closed
+func pollResize(size func() (cols, rows int), onChange func(), stop <-chan struct{}) {
+	p := newSizePoller(size)
+	ticker := time.NewTicker(resizePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if p.poll() {
+				onChange()
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -23,3 +23,19 @@ func (t *Terminal) handleSIGWINCH() {
 		}
 	}
 }
+
+// handleSIGWINCH re-lays out the panes when the host terminal is resized
+func (s *SplitTerminal) handleSIGWINCH() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGWINCH)
+	defer signal.Stop(sigChan)
+
+	for {
+		select {
+		case <-sigChan:
+			s.handleResize()
+		case <-s.stop:
+			return
+		}
+	}
+}
@@ -2,10 +2,14 @@
 
 package cli
 
-// handleSIGWINCH is a no-op on Windows.
-// Windows doesn't have SIGWINCH; terminal resize must be handled differently.
+// handleSIGWINCH follows host terminal resizes. Windows has no SIGWINCH,
+// so the console size is polled instead (see pollResize).
 func (t *Terminal) handleSIGWINCH() {
-	// On Windows, terminal resize detection would need to use Windows Console API
-	// or polling. For now, this is a no-op stub to allow compilation.
-	<-t.done
+	pollResize(getHostTerminalSize, t.handleResize, t.done)
+}
+
+// handleSIGWINCH re-lays out the panes when the host console is resized,
+// polling as Terminal.handleSIGWINCH does
+func (s *SplitTerminal) handleSIGWINCH() {
+	pollResize(getHostTerminalSize, s.handleResize, s.stop)
 }
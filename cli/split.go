@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phroun/direct-key-handler/keyboard"
+	"golang.org/x/term"
+)
+
+// SplitOrientation selects how a SplitTerminal divides the host screen
+type SplitOrientation int
+
+const (
+	SplitVertical   SplitOrientation = iota // Panes side by side (left | right)
+	SplitHorizontal                         // Panes stacked (top / bottom)
+)
+
+// DefaultSplitFocusKey is the key (Ctrl+]) that moves input focus to the
+// other pane when SplitOptions.FocusKey is empty
+const DefaultSplitFocusKey = "^]"
+
+// SplitOptions configures a two-pane terminal
+type SplitOptions struct {
+	Orientation SplitOrientation // How the host screen is divided
+
+	// Pane holds the settings both panes share (Scheme, ScrollbackSize,
+	// Shell, WorkingDir, ...). Size, offset, border and status bar are
+	// managed by the split and ignored here.
+	Pane Options
+
+	// FocusKey is the direct-key-handler key name that switches input to
+	// the other pane (default DefaultSplitFocusKey). It is never sent to
+	// either child.
+	FocusKey string
+
+	// Host area to fill (default: the host terminal size)
+	Cols, Rows int
+}
+
+// SplitTerminal runs two terminals side by side (or stacked) in one host
+// terminal. Each pane is an embedded Terminal with its own Buffer, Parser
+// and PTY, drawn by its own Renderer at the pane's offset; keyboard input
+// goes to the focused pane.
+type SplitTerminal struct {
+	mu sync.Mutex
+
+	panes [2]*Terminal
+	focus int // Index of the pane receiving input
+	opts  SplitOptions
+
+	// Host area and whether the divider must be redrawn
+	hostCols, hostRows int
+	dividerDirty       bool
+
+	// Pane renderers write into frame; each render is flushed to hostOut
+	// in one piece so the panes' output never interleaves
+	frame   strings.Builder
+	hostOut io.Writer
+
+	oldState *term.State // Host terminal state restored by Stop
+	stop     chan struct{}
+	stopped  bool
+}
+
+// splitLayout divides a cols x rows host area into two pane rectangles and
+// the one-cell-thick divider between them.
+//
+// The divider takes a single column (vertical split) or row (horizontal
+// split). The first pane gets half of what remains, rounded down; the
+// second pane gets the rest, so an odd remainder goes to the second pane.
+// For a vertical split:
+//
+//	a       = {X: 0,         Y: 0, Width: (cols-1)/2,       Height: rows}
+//	divider = {X: a.Width,   Y: 0, Width: 1,                Height: rows}
+//	b       = {X: a.Width+1, Y: 0, Width: cols-a.Width-1,   Height: rows}
+//
+// A horizontal split is the same with the roles of x/cols and y/rows
+// swapped. Panes never shrink below one cell; on a host too small for
+// that, they overlap the divider rather than get a negative size.
+func splitLayout(cols, rows int, orientation SplitOrientation) (a, b, divider Rect) {
+	if orientation == SplitHorizontal {
+		a, b, divider = splitLayout(rows, cols, SplitVertical)
+		swap := func(r Rect) Rect {
+			return Rect{X: r.Y, Y: r.X, Width: r.Height, Height: r.Width}
+		}
+		return swap(a), swap(b), swap(divider)
+	}
+
+	first := max((cols-1)/2, 1)
+	second := max(cols-first-1, 1)
+	rows = max(rows, 1)
+	a = Rect{X: 0, Y: 0, Width: first, Height: rows}
+	divider = Rect{X: first, Y: 0, Width: 1, Height: rows}
+	b = Rect{X: first + 1, Y: 0, Width: second, Height: rows}
+	return a, b, divider
+}
+
+// NewSplit creates a two-pane terminal. Start it, then run a command in
+// each pane, e.g. s.Pane(0).RunShell() and s.Pane(1).RunCommand("top").
+// The first pane starts with the focus.
+func NewSplit(opts SplitOptions) (*SplitTerminal, error) {
+	if opts.FocusKey == "" {
+		opts.FocusKey = DefaultSplitFocusKey
+	}
+	if opts.Cols <= 0 || opts.Rows <= 0 {
+		opts.Cols, opts.Rows = getHostTerminalSize()
+	}
+
+	s := &SplitTerminal{
+		opts:         opts,
+		hostCols:     opts.Cols,
+		hostRows:     opts.Rows,
+		dividerDirty: true,
+		hostOut:      os.Stdout,
+		stop:         make(chan struct{}),
+	}
+
+	a, b, _ := splitLayout(opts.Cols, opts.Rows, opts.Orientation)
+	for i, r := range []Rect{a, b} {
+		po := opts.Pane
+		po.Embedded = true
+		po.AutoSize = false
+		po.BorderStyle = BorderNone
+		po.ShowStatusBar = false
+		po.ForwardTitle = false // Two children would fight over one host title
+		po.OffsetX, po.OffsetY = r.X, r.Y
+		po.Cols, po.Rows = r.Width, r.Height
+
+		pane, err := New(po)
+		if err != nil {
+			return nil, err
+		}
+		pane.hostOut = &s.frame
+		s.panes[i] = pane
+	}
+	s.panes[0].SetFocused(true)
+
+	return s, nil
+}
+
+// Pane returns pane 0 (left or top) or pane 1 (right or bottom)
+func (s *SplitTerminal) Pane(i int) *Terminal {
+	return s.panes[i]
+}
+
+// Focused returns the index of the pane that receives keyboard input
+func (s *SplitTerminal) Focused() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.focus
+}
+
+// SetFocus sends keyboard input to pane i (0 or 1)
+func (s *SplitTerminal) SetFocus(i int) {
+	if i != 0 && i != 1 {
+		return
+	}
+	s.mu.Lock()
+	s.focus = i
+	s.mu.Unlock()
+
+	// Unfocus first so at most one pane ever shows its cursor
+	s.panes[1-i].SetFocused(false)
+	s.panes[i].SetFocused(true)
+}
+
+// ToggleFocus moves keyboard input to the other pane
+func (s *SplitTerminal) ToggleFocus() {
+	s.SetFocus(1 - s.Focused())
+}
+
+// Start enters raw mode on the host terminal and starts input, rendering
+// and resize handling for both panes
+func (s *SplitTerminal) Start() error {
+	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	s.mu.Lock()
+	s.oldState = oldState
+	s.mu.Unlock()
+
+	// Hide cursor, enable alternate screen buffer, clear screen
+	fmt.Fprint(s.hostOut, "\033[?25l\033[?1049h\033[2J\033[H")
+
+	go s.handleSIGWINCH()
+	go s.inputLoop()
+	go s.renderLoop()
+
+	return nil
+}
+
+// Wait blocks until the commands in both panes have exited
+func (s *SplitTerminal) Wait() {
+	s.panes[0].Wait()
+	s.panes[1].Wait()
+}
+
+// Stop stops both panes and restores the host terminal
+func (s *SplitTerminal) Stop() error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	close(s.stop)
+	oldState := s.oldState
+	s.mu.Unlock()
+
+	for _, pane := range s.panes {
+		pane.Stop()
+	}
+
+	if oldState != nil {
+		// Leave alternate screen, show cursor, reset attributes
+		fmt.Fprint(s.hostOut, "\033[?1049l\033[?25h\033[0m")
+		term.Restore(int(os.Stdin.Fd()), oldState)
+	}
+	return nil
+}
+
+// Resize lays the panes out again for a cols x rows host area
+func (s *SplitTerminal) Resize(cols, rows int) {
+	s.mu.Lock()
+	s.hostCols, s.hostRows = cols, rows
+	s.dividerDirty = true
+	orientation := s.opts.Orientation
+	// The divider moves, so the old one is cleared along with everything else
+	s.frame.WriteString("\033[0m\033[2J")
+	s.mu.Unlock()
+
+	a, b, _ := splitLayout(cols, rows, orientation)
+	for i, r := range []Rect{a, b} {
+		s.panes[i].Resize(r.Width, r.Height)
+		s.panes[i].SetOffset(r.X, r.Y)
+		s.panes[i].renderer.ForceFullRedraw()
+	}
+}
+
+// handleResize follows the host terminal size
+func (s *SplitTerminal) handleResize() {
+	cols, rows := getHostTerminalSize()
+	s.mu.Lock()
+	same := cols == s.hostCols && rows == s.hostRows
+	s.mu.Unlock()
+	if !same {
+		s.Resize(cols, rows)
+	}
+}
+
+// inputLoop reads host keys, switching panes on the focus key and passing
+// everything else to the focused pane
+func (s *SplitTerminal) inputLoop() {
+	manageTerminal := false
+	kb := keyboard.New(keyboard.Options{
+		InputReader:    os.Stdin,
+		ManageTerminal: &manageTerminal,
+	})
+	kb.OnKey = s.handleKey
+
+	if err := kb.Start(); err != nil {
+		return
+	}
+	<-s.stop
+	kb.Stop()
+}
+
+// handleKey routes one key from the host
+func (s *SplitTerminal) handleKey(key string) {
+	if key == s.opts.FocusKey {
+		s.ToggleFocus()
+		return
+	}
+	s.panes[s.Focused()].HandleKeyString(key)
+}
+
+// renderLoop draws at ~60fps max, but only when a pane has changed
+func (s *SplitTerminal) renderLoop() {
+	ticker := time.NewTicker(16 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.render()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// render draws the divider and any pane needing it, then writes the frame
+// to the host in one piece. The focused pane is always drawn last, since
+// each Renderer ends its output by placing (or hiding) the host cursor.
+func (s *SplitTerminal) render() {
+	focus := s.Focused()
+	other := 1 - focus
+	otherNeeds := s.panes[other].renderer.takeRenderRequest()
+	focusNeeds := s.panes[focus].renderer.takeRenderRequest()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !otherNeeds && !focusNeeds && !s.dividerDirty && s.frame.Len() == 0 {
+		return
+	}
+
+	if s.dividerDirty {
+		s.renderDivider()
+		s.dividerDirty = false
+	}
+	if otherNeeds {
+		s.panes[other].renderer.Render()
+	}
+	s.panes[focus].renderer.Render()
+
+	io.WriteString(s.hostOut, s.frame.String())
+	s.frame.Reset()
+}
+
+// renderDivider draws the line between the panes (s.mu held)
+func (s *SplitTerminal) renderDivider() {
+	_, _, d := splitLayout(s.hostCols, s.hostRows, s.opts.Orientation)
+	s.frame.WriteString("\033[0m")
+	if s.opts.Orientation == SplitHorizontal {
+		s.frame.WriteString(cupSeq(d.Y, d.X))
+		s.frame.WriteString(strings.Repeat("─", d.Width))
+		return
+	}
+	for y := 0; y < d.Height; y++ {
+		s.frame.WriteString(cupSeq(d.Y+y, d.X))
+		s.frame.WriteRune('│')
+	}
+}
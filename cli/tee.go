@@ -0,0 +1,72 @@
+package cli
+
+import "io"
+
+// teeQueueSize is the number of chunks a tee may lag behind before new
+// chunks are dropped
+const teeQueueSize = 256
+
+// teeWriter copies chunks to a writer on its own goroutine so a slow writer
+// never stalls the PTY read loop or input handling. It is best-effort:
+// chunks are dropped while the queue is full.
+type teeWriter struct {
+	w     io.Writer
+	queue chan []byte
+}
+
+// newTeeWriter starts a tee goroutine writing to w
+func newTeeWriter(w io.Writer) *teeWriter {
+	tw := &teeWriter{w: w, queue: make(chan []byte, teeQueueSize)}
+	go tw.run()
+	return tw
+}
+
+func (tw *teeWriter) run() {
+	for chunk := range tw.queue {
+		tw.w.Write(chunk)
+	}
+}
+
+// send queues a copy of data, dropping it if the writer has fallen behind
+func (tw *teeWriter) send(data []byte) {
+	if tw == nil || len(data) == 0 {
+		return
+	}
+	chunk := append([]byte(nil), data...)
+	select {
+	case tw.queue <- chunk:
+	default:
+	}
+}
+
+// close stops the goroutine once queued chunks have been written
+func (tw *teeWriter) close() {
+	if tw != nil {
+		close(tw.queue)
+	}
+}
+
+// SetOutputTee copies every raw chunk read from the PTY to w, before it is
+// parsed. Useful for capturing exactly what the child emits. Writes happen
+// asynchronously and chunks may be dropped if w is too slow. Pass nil to stop.
+func (t *Terminal) SetOutputTee(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.outputTee.close()
+	t.outputTee = nil
+	if w != nil {
+		t.outputTee = newTeeWriter(w)
+	}
+}
+
+// SetInputTee copies every chunk sent to the child process to w, with the
+// same best-effort semantics as SetOutputTee. Pass nil to stop.
+func (t *Terminal) SetInputTee(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inputTee.close()
+	t.inputTee = nil
+	if w != nil {
+		t.inputTee = newTeeWriter(w)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
 
 	"github.com/phroun/purfecterm"
@@ -83,6 +84,15 @@ type TerminalCapabilities struct {
 	LineMode      bool
 }
 
+// StatusInfo describes the terminal state passed to Options.StatusBarFunc
+type StatusInfo struct {
+	CursorX, CursorY int    // Cursor position (0-indexed)
+	ScrollOffset     int    // Lines scrolled back (0 = live view)
+	ScrollbackSize   int    // Lines held in scrollback
+	Title            string // Current window title
+	Cols, Rows       int    // Terminal dimensions
+}
+
 // Options configures terminal creation
 type Options struct {
 	Cols           int                    // Terminal width in columns (default: auto-detect or 80)
@@ -104,6 +114,16 @@ type Options struct {
 	// If true, render a status bar at the bottom
 	ShowStatusBar bool
 
+	// ForwardTitle copies window titles set by the child (OSC 0/2) to the
+	// host terminal and to the border title. The host's previous title is
+	// saved on the first change and restored on Stop.
+	ForwardTitle bool
+
+	// StatusBarFunc, if set, supplies the status bar text each frame instead
+	// of the built-in cursor/scroll summary. The result is padded or
+	// truncated to the window width.
+	StatusBarFunc func(info StatusInfo) string
+
 	// Embedded mode: when true, the terminal acts as a widget within a larger TUI.
 	// It will NOT enter raw mode, switch to alternate screen, or start its own input loop.
 	// The parent application is responsible for:
@@ -117,6 +137,17 @@ type Options struct {
 	// when it requests mouse tracking via escape sequences (e.g., CSI ?1000h).
 	// Set to true to prevent mouse events from ever being reported to the PTY.
 	DisableMouseReporting bool
+
+	// ColorDepth is how many colors the host terminal shows: 24 (true
+	// color), 256, 16 or 8. Colors beyond it are downsampled to the nearest
+	// one it has; -1 turns color off. 0 detects it from COLORTERM and TERM
+	// (DetectColorDepth).
+	ColorDepth int
+
+	// ScrollKeys maps direct-key-handler key names (e.g. "C-M-Up") to
+	// scrollback actions handled locally instead of being sent to the PTY.
+	// Nil uses DefaultScrollKeys(); a non-nil map replaces it entirely.
+	ScrollKeys map[string]ScrollAction
 }
 
 // Terminal is a complete terminal emulator running within a CLI terminal
@@ -138,6 +169,7 @@ type Terminal struct {
 	running    bool
 	done       chan struct{}
 	stopRender chan struct{}
+	stopped    bool // Stop has run; later calls do nothing
 
 	// Original terminal state for restoration
 	oldState *term.State
@@ -161,6 +193,26 @@ type Terminal struct {
 
 	// Input callback for intercepting input before sending to PTY
 	inputCallback func([]byte) bool // Return true to consume input
+
+	// Child process environment: env replaces os.Environ() when non-nil,
+	// extraEnv is appended last (after TERM/COLORTERM) so it can override them
+	env      []string
+	extraEnv []string
+
+	// Raw byte taps for debugging (see SetOutputTee/SetInputTee)
+	outputTee *teeWriter
+	inputTee  *teeWriter
+
+	// Host title forwarding (see Options.ForwardTitle)
+	hostOut     io.Writer // Where rendered frames and host title sequences are written (os.Stdout)
+	titlePushed bool      // Host title saved with XTWINOPS 22 and must be restored
+
+	// Colors the host terminal shows (Options.ColorDepth or detected)
+	hostColorDepth int
+
+	// Terminal capabilities (for PawScript channel integration)
+	// Automatically updated on resize
+	termCaps *purfecterm.TerminalCapabilities
 }
 
 // New creates a new CLI terminal emulator
@@ -223,6 +275,31 @@ func New(opts Options) (*Terminal, error) {
 		hostCols:   hostCols,
 		hostRows:   hostRows,
 		focused:    !opts.Embedded, // Non-embedded terminals are always focused
+		hostOut:    os.Stdout,
+	}
+
+	switch {
+	case opts.ColorDepth < 0:
+		t.hostColorDepth = 0
+	case opts.ColorDepth == 0:
+		t.hostColorDepth = DetectColorDepth(os.Getenv)
+	default:
+		t.hostColorDepth = opts.ColorDepth
+	}
+
+	// Initialize terminal capabilities (auto-updated on resize)
+	t.termCaps = &purfecterm.TerminalCapabilities{
+		TermType:      "xterm-256color",
+		IsTerminal:    true,
+		SupportsANSI:  true,
+		SupportsColor: true,
+		ColorDepth:    24,
+		Width:         opts.Cols,
+		Height:        opts.Rows,
+		SupportsInput: true,
+		EchoEnabled:   false,
+		LineMode:      false,
+		Metadata:      make(map[string]interface{}),
 	}
 
 	// Create renderer
@@ -231,6 +308,13 @@ func New(opts Options) (*Terminal, error) {
 	// Create input handler
 	t.input = NewInputHandler(t)
 
+	// Query replies (DECRQSS) go to the hosted program like typed input
+	parser.SetResponseWriter(t)
+
+	if opts.ForwardTitle {
+		buffer.SetTitleChangeCallback(t.forwardTitle)
+	}
+
 	// Set dirty callback for efficient rendering
 	buffer.SetDirtyCallback(func() {
 		t.renderer.RequestRender()
@@ -329,6 +413,7 @@ func (t *Terminal) handleResize() {
 		}
 		t.options.Cols = cols
 		t.options.Rows = rows
+		t.termCaps.SetSize(cols, rows)
 	}
 
 	// Force full redraw
@@ -367,11 +452,10 @@ func (t *Terminal) RunCommand(name string, args ...string) error {
 
 	// Create command
 	cmd := exec.Command(name, args...)
+	t.mu.Lock()
 	cmd.Dir = t.options.WorkingDir
-	cmd.Env = append(os.Environ(),
-		"TERM=xterm-256color",
-		"COLORTERM=truecolor",
-	)
+	cmd.Env = t.childEnv()
+	t.mu.Unlock()
 
 	// Start PTY
 	if err := pty.Start(cmd); err != nil {
@@ -411,6 +495,60 @@ func (t *Terminal) RunCommand(name string, args ...string) error {
 	return nil
 }
 
+// childEnv builds the environment for a child process. Must be called with
+// t.mu held.
+func (t *Terminal) childEnv() []string {
+	base := t.env
+	if base == nil {
+		base = os.Environ()
+	}
+	env := make([]string, 0, len(base)+2+len(t.extraEnv))
+	env = append(env, base...)
+	env = append(env, "TERM=xterm-256color", "COLORTERM=truecolor")
+	if t.options.WorkingDir != "" {
+		env = append(env, "PWD="+t.options.WorkingDir)
+	}
+	return append(env, t.extraEnv...)
+}
+
+// SetEnv replaces the base environment for commands started afterwards
+// (default: the current process environment). TERM, COLORTERM and PWD are
+// still added, and AppendEnv entries still apply. Pass nil to restore the default.
+func (t *Terminal) SetEnv(env []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if env == nil {
+		t.env = nil
+		return
+	}
+	t.env = append([]string{}, env...)
+}
+
+// AppendEnv adds "KEY=value" entries to the environment of commands started
+// afterwards. They are applied last, so they override the base environment
+// and the default TERM/COLORTERM.
+func (t *Terminal) AppendEnv(vars ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.extraEnv = append(t.extraEnv, vars...)
+}
+
+// SetWorkingDir sets the working directory for commands started afterwards.
+// Returns an error if dir does not exist or is not a directory.
+func (t *Terminal) SetWorkingDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid working directory: %s is not a directory", dir)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.options.WorkingDir = dir
+	return nil
+}
+
 // readLoop reads output from the PTY and feeds it to the parser
 func (t *Terminal) readLoop() {
 	buf := make([]byte, 4096)
@@ -426,6 +564,9 @@ func (t *Terminal) readLoop() {
 
 		n, err := pty.Read(buf)
 		if n > 0 {
+			t.mu.Lock()
+			t.outputTee.send(buf[:n])
+			t.mu.Unlock()
 			t.parser.Parse(buf[:n])
 		}
 		if err != nil {
@@ -451,6 +592,9 @@ func (t *Terminal) FeedString(data string) {
 func (t *Terminal) Write(data []byte) (int, error) {
 	t.mu.Lock()
 	pty := t.pty
+	if pty != nil {
+		t.inputTee.send(data)
+	}
 	t.mu.Unlock()
 	if pty == nil {
 		return 0, nil
@@ -483,6 +627,7 @@ func (t *Terminal) Resize(cols, rows int) {
 	t.buffer.Resize(cols, rows)
 	t.options.Cols = cols
 	t.options.Rows = rows
+	t.termCaps.SetSize(cols, rows)
 
 	if t.pty != nil {
 		t.pty.Resize(cols, rows)
@@ -597,7 +742,41 @@ func (t *Terminal) SetTitle(title string) {
 	t.renderer.RequestRender()
 }
 
-// GetTerminalCapabilities returns the terminal capabilities
+// forwardTitle shows a title set by the child in the border and on the host
+// terminal. Control characters are stripped so the title cannot end the
+// host OSC sequence early.
+func (t *Terminal) forwardTitle(title string) {
+	title = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, title)
+
+	t.mu.Lock()
+	t.options.Title = title
+	push := !t.titlePushed
+	t.titlePushed = true
+	t.mu.Unlock()
+
+	// This runs on the parser goroutine, so write through the renderer to
+	// keep the sequence out of the middle of a frame
+	seq := fmt.Sprintf("\033]2;%s\007", title)
+	if push {
+		seq = "\033[22;2t" + seq // Save the host title first
+	}
+	t.renderer.writeHost(seq)
+	t.renderer.RequestRender()
+}
+
+// Capabilities returns the live terminal capabilities for this terminal.
+// The returned pointer is kept in sync with the terminal size on resize,
+// so it can be handed to a PawScript channel for feature negotiation.
+func (t *Terminal) Capabilities() *purfecterm.TerminalCapabilities {
+	return t.termCaps
+}
+
+// GetTerminalCapabilities returns a snapshot of the terminal capabilities
 func (t *Terminal) GetTerminalCapabilities() *TerminalCapabilities {
 	cols, rows := t.GetSize()
 	return &TerminalCapabilities{
@@ -650,6 +829,9 @@ func (t *Terminal) SetFocused(focused bool) {
 
 	if changed {
 		t.renderer.RequestRender()
+		if data := t.buffer.FocusEvent(focused); data != nil {
+			t.Write(data)
+		}
 		if callback != nil {
 			callback(focused)
 		}
@@ -740,6 +922,7 @@ func (t *Terminal) HandleResize(hostCols, hostRows int) {
 		}
 		t.options.Cols = cols
 		t.options.Rows = rows
+		t.termCaps.SetSize(cols, rows)
 	}
 	t.mu.Unlock()
 
@@ -889,6 +1072,7 @@ func (t *Terminal) GetCells() [][]RenderedCell {
 	opts := t.options
 	buffer := t.buffer
 	t.mu.Unlock()
+	opts.Scheme = buffer.EffectiveScheme(opts.Scheme)
 
 	cols, rows := buffer.GetSize()
 	isDark := buffer.IsDarkTheme()
@@ -900,8 +1084,7 @@ func (t *Terminal) GetCells() [][]RenderedCell {
 			cell := buffer.GetVisibleCell(x, y)
 
 			// Resolve colors
-			fg := opts.Scheme.ResolveColor(cell.Foreground, true, isDark)
-			bg := opts.Scheme.ResolveColor(cell.Background, false, isDark)
+			fg, bg := opts.Scheme.ResolveColors(cell.Foreground, cell.Background, isDark)
 
 			// Handle reverse video
 			if cell.Reverse {
@@ -938,6 +1121,7 @@ func (t *Terminal) GetCellAt(col, row int) RenderedCell {
 	opts := t.options
 	buffer := t.buffer
 	t.mu.Unlock()
+	opts.Scheme = buffer.EffectiveScheme(opts.Scheme)
 
 	cols, rows := buffer.GetSize()
 	if col < 0 || col >= cols || row < 0 || row >= rows {
@@ -947,8 +1131,7 @@ func (t *Terminal) GetCellAt(col, row int) RenderedCell {
 	isDark := buffer.IsDarkTheme()
 	cell := buffer.GetVisibleCell(col, row)
 
-	fg := opts.Scheme.ResolveColor(cell.Foreground, true, isDark)
-	bg := opts.Scheme.ResolveColor(cell.Background, false, isDark)
+	fg, bg := opts.Scheme.ResolveColors(cell.Foreground, cell.Background, isDark)
 
 	if cell.Reverse {
 		fg, bg = bg, fg
@@ -988,19 +1171,33 @@ func (t *Terminal) HandleKeyString(key string) bool {
 	return t.input.handleKey(key)
 }
 
-// Stop stops the terminal and restores the original terminal state
+// Stop stops the terminal and restores the original terminal state.
+// Calls after the first do nothing.
 func (t *Terminal) Stop() error {
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+		return nil
+	}
+	t.stopped = true
+
 	// Signal stop
 	close(t.stopRender)
 
 	// Kill child process if running
-	t.mu.Lock()
 	if t.cmd != nil && t.cmd.Process != nil {
 		t.cmd.Process.Kill()
 	}
 	if t.pty != nil {
 		t.pty.Close()
 	}
+	t.outputTee.close()
+	t.inputTee.close()
+	t.outputTee, t.inputTee = nil, nil
+	if t.titlePushed {
+		fmt.Fprint(t.hostOut, "\033[23;2t") // Restore the host title
+		t.titlePushed = false
+	}
 	oldState := t.oldState
 	embedded := t.options.Embedded
 	t.mu.Unlock()
@@ -1034,3 +1231,19 @@ func (t *Terminal) Stop() error {
 func (t *Terminal) Close() error {
 	return t.Stop()
 }
+
+// RunGuarded starts t (a Terminal or SplitTerminal), runs fn, and stops t
+// when fn returns or panics. On a panic the host terminal is back in cooked
+// mode, off the alternate screen and with its cursor shown before the panic
+// continues, so the trace is readable and the shell usable. Panics in other
+// goroutines can't be caught here; recover there and call Stop.
+func RunGuarded(t interface {
+	Start() error
+	Stop() error
+}, fn func() error) error {
+	if err := t.Start(); err != nil {
+		return err
+	}
+	defer t.Stop()
+	return fn()
+}
@@ -0,0 +1,67 @@
+package purfecterm
+
+// ClipboardWriter is the minimal clipboard a widget adapter copies into.
+// GTK's gtk.Clipboard and Qt's QClipboard both satisfy it.
+type ClipboardWriter interface {
+	SetText(text string)
+}
+
+// CopySelectionTo writes the selected text to the clipboard.
+// Returns false (and leaves the clipboard alone) when nothing is selected.
+func (b *Buffer) CopySelectionTo(cw ClipboardWriter) bool {
+	if cw == nil || !b.HasSelection() {
+		return false
+	}
+	cw.SetText(b.GetSelectedText())
+	return true
+}
+
+// WrapPaste prepares clipboard text for sending to the PTY. The text is
+// wrapped in bracketed paste markers (ESC [200~ ... ESC [201~) when the
+// application enabled bracketed paste, or when the text contains newlines,
+// escapes or other control characters that could otherwise run as commands.
+func WrapPaste(text string, bracketedPasteMode bool) []byte {
+	useBracketedPaste := bracketedPasteMode
+	if !useBracketedPaste {
+		for _, c := range text {
+			if c < 32 {
+				useBracketedPaste = true
+				break
+			}
+		}
+	}
+	if !useBracketedPaste {
+		return []byte(text)
+	}
+	out := make([]byte, 0, len(text)+12)
+	out = append(out, "\x1b[200~"...)
+	out = append(out, text...)
+	out = append(out, "\x1b[201~"...)
+	return out
+}
+
+// SetPasteFilter sets a hook that sees every paste after WrapPaste and
+// returns what is actually sent, so an embedder can strip newlines or
+// control characters, or ask the user before a multi-line paste runs.
+// Returning nil or empty cancels the paste. A nil filter sends data as is.
+func (b *Buffer) SetPasteFilter(fn func(data []byte) []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pasteFilter = fn
+}
+
+// PreparePaste returns the bytes an adapter sends to the PTY for pasted
+// text: wrapped for the current bracketed paste mode, then passed through
+// the paste filter. Empty means there is nothing to send.
+func (b *Buffer) PreparePaste(text string) []byte {
+	b.mu.RLock()
+	bracketed := b.bracketedPasteMode
+	filter := b.pasteFilter
+	b.mu.RUnlock()
+
+	data := WrapPaste(text, bracketed)
+	if filter != nil {
+		data = filter(data)
+	}
+	return data
+}
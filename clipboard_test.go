@@ -0,0 +1,117 @@
+package purfecterm
+
+import (
+	"bytes"
+	"testing"
+)
+
+type mockClipboard struct{ text string }
+
+func (m *mockClipboard) SetText(text string) { m.text = text }
+
+// Copy only happens with a selection made through StartSelection/UpdateSelection.
+func TestCopySelectionTo(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	NewParser(b).Parse([]byte("hello world"))
+	cb := &mockClipboard{text: "untouched"}
+
+	if b.CopySelectionTo(cb) || cb.text != "untouched" {
+		t.Fatalf("no selection must not copy, clipboard = %q", cb.text)
+	}
+	b.StartSelection(6, 0)
+	b.UpdateSelection(10, 0)
+	if !b.CopySelectionTo(cb) || cb.text != "world" {
+		t.Fatalf("copy-on-selection: clipboard = %q, want %q", cb.text, "world")
+	}
+}
+
+// WrapPaste brackets text when the mode is on or the text has control chars.
+func TestWrapPaste(t *testing.T) {
+	if got := string(WrapPaste("ls", false)); got != "ls" {
+		t.Errorf("plain paste = %q", got)
+	}
+	if got := string(WrapPaste("ls", true)); got != "\x1b[200~ls\x1b[201~" {
+		t.Errorf("bracketed mode paste = %q", got)
+	}
+	if got := string(WrapPaste("a\nb", false)); got != "\x1b[200~a\nb\x1b[201~" {
+		t.Errorf("multi-line paste should be bracketed, got %q", got)
+	}
+}
+
+// Finishing or clearing a selection reports only when auto-copy is enabled.
+func TestAutoCopyOnSelect(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	NewParser(b).Parse([]byte("hello world"))
+	var got []string
+	b.SetSelectionChangedCallback(func(text string) { got = append(got, text) })
+
+	b.StartSelection(0, 0)
+	b.UpdateSelection(4, 0)
+	b.EndSelection()
+	b.ClearSelection()
+	if len(got) != 0 {
+		t.Fatalf("auto-copy off must not report, got %q", got)
+	}
+
+	b.SetAutoCopyOnSelect(true)
+	b.StartSelection(0, 0)
+	b.UpdateSelection(4, 0)
+	b.EndSelection()
+	b.ClearSelection()
+	b.ClearSelection() // no active selection: no report
+	if len(got) != 2 || got[0] != "hello" || got[1] != "" {
+		t.Fatalf("reports = %q, want [\"hello\" \"\"]", got)
+	}
+}
+
+// The paste filter sees the wrapped paste and decides what is sent.
+func TestPasteFilter(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	if got := string(b.PreparePaste("a\nb")); got != "\x1b[200~a\nb\x1b[201~" {
+		t.Fatalf("no filter: paste = %q", got)
+	}
+
+	var sent []byte
+	onInput := func(data []byte) { sent = append(sent, data...) }
+	b.SetPasteFilter(func(data []byte) []byte {
+		return bytes.ReplaceAll(data, []byte("\n"), nil)
+	})
+	onInput(b.PreparePaste("echo one\necho two\n"))
+	if got := string(sent); got != "\x1b[200~echo oneecho two\x1b[201~" {
+		t.Errorf("filtered paste = %q, want a single line", got)
+	}
+}
+
+// The child turning bracketed paste on and off (CSI ? 2004 h/l) sets the
+// mode, tells the adapter through the mode callback, and decides whether
+// PreparePaste wraps a one-line paste
+func TestBracketedPasteFromChild(t *testing.T) {
+	b := NewBuffer(20, 3, 0)
+	p := NewParser(b)
+	var changes []bool
+	b.SetModeChangeCallback(func(mode string, enabled bool) {
+		if mode == "bracketedpaste" {
+			changes = append(changes, enabled)
+		}
+	})
+
+	p.Parse([]byte("\x1b[?2004h"))
+	if !b.IsBracketedPasteModeEnabled() {
+		t.Fatal("CSI ? 2004 h did not enable bracketed paste")
+	}
+	if got := string(b.PreparePaste("ls")); got != "\x1b[200~ls\x1b[201~" {
+		t.Errorf("paste with the mode on = %q, want it bracketed", got)
+	}
+
+	p.Parse([]byte("\x1b[?2004l"))
+	if b.IsBracketedPasteModeEnabled() {
+		t.Fatal("CSI ? 2004 l did not disable bracketed paste")
+	}
+	if got := string(b.PreparePaste("ls")); got != "ls" {
+		t.Errorf("paste with the mode off = %q, want it plain", got)
+	}
+
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("mode callback got %v, want [true false]", changes)
+	}
+}
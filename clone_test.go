@@ -0,0 +1,59 @@
+package purfecterm
+
+import "testing"
+
+// A clone starts out identical and then changes independently of the
+// original, in both directions.
+func TestBufferClone(t *testing.T) {
+	b := NewBuffer(10, 3, 100)
+	p := NewParser(b)
+	p.Parse([]byte("one\r\ntwo\r\nthree\r\nfour\x1b]2;title\x07"))
+	b.InitPalette(1, 2)
+	b.SetPaletteEntryColor(1, 0, StandardColor(1), false)
+	b.SetGlyph('g', 2, []int{0, 1, 1, 0})
+	b.SetSprite(7, 1, 2, 0, -1, 0, 1, 1, -1, []rune{'g'})
+
+	c := b.Clone()
+	if !c.EqualVisible(b) {
+		t.Fatalf("clone differs from original: %v", c.DiffVisible(b))
+	}
+	if c.GetScrollbackSize() != b.GetScrollbackSize() {
+		t.Fatalf("clone scrollback = %d lines, want %d", c.GetScrollbackSize(), b.GetScrollbackSize())
+	}
+	if c.GetTitle() != "title" {
+		t.Errorf("clone title = %q, want %q", c.GetTitle(), "title")
+	}
+	cx, cy := c.GetCursor()
+	bx, by := b.GetCursor()
+	if cx != bx || cy != by {
+		t.Errorf("clone cursor = (%d,%d), want (%d,%d)", cx, cy, bx, by)
+	}
+
+	// Mutating the clone leaves the original alone
+	NewParser(c).Parse([]byte("\x1b[HX"))
+	c.SetPaletteEntryColor(1, 0, StandardColor(2), false)
+	c.GetGlyph('g').Pixels[0] = 9
+	c.MoveSprite(7, 5, 5)
+	c.GetScrollbackLine(0)[0].Char = 'Z'
+	if got := b.GetCell(0, 0).Char; got != 't' {
+		t.Errorf("original cell (0,0) = %q after writing the clone, want 't'", got)
+	}
+	if got := b.GetPalette(1).Entries[0].Color; got != StandardColor(1) {
+		t.Errorf("original palette entry = %+v, want red", got)
+	}
+	if got := b.GetGlyph('g').Pixels[0]; got != 0 {
+		t.Errorf("original glyph pixel = %d, want 0", got)
+	}
+	if s := b.GetSprite(7); s.X != 1 || s.Y != 2 {
+		t.Errorf("original sprite at (%v,%v), want (1,2)", s.X, s.Y)
+	}
+
+	// ... and the other way round
+	p.Parse([]byte("\x1b[3;1HY"))
+	if got := c.GetCell(0, 2).Char; got != 'f' {
+		t.Errorf("clone cell (0,2) = %q after writing the original, want 'f'", got)
+	}
+	if got := c.GetCell(0, 0).Char; got != 'X' {
+		t.Errorf("clone cell (0,0) = %q, want 'X'", got)
+	}
+}
@@ -12,6 +12,11 @@
 // implementations that use this core package.
 package purfecterm
 
+import (
+	"strconv"
+	"strings"
+)
+
 // ColorType indicates how a color was specified
 type ColorType uint8
 
@@ -135,6 +140,7 @@ const (
 	BlinkModeBounce BlinkMode = iota // Bobbing wave animation (default)
 	BlinkModeBlink                   // Traditional on/off blinking
 	BlinkModeBright                  // Interpret as bright background (VGA style)
+	BlinkModeNone                    // Ignore blink: text is drawn static
 )
 
 // RGB holds just the red, green, blue components (used internally)
@@ -204,6 +210,54 @@ func Get256Color(idx int) Color {
 	return PaletteColor(idx)
 }
 
+// Downsample returns the closest color a terminal with the given color
+// depth (as in TerminalCapabilities.ColorDepth: 24, 256, 16, 8 or 0) can
+// show. Colors already within the depth are kept; others become the
+// nearest palette entry by RGB distance. Depth 0 (no color) gives the
+// default color. Default colors are never changed.
+func (c Color) Downsample(depth int) Color {
+	// 24 (bits) sorts below 256 (colors), so true color is matched exactly
+	if c.Type == ColorTypeDefault || depth == 24 || depth > 256 {
+		return c
+	}
+	switch {
+	case depth <= 0:
+		return Color{Type: ColorTypeDefault}
+	case depth == 256:
+		if c.Type == ColorTypeTrueColor {
+			return PaletteColor(nearestColorIndex(c, 16, 256))
+		}
+		return c
+	}
+
+	// 16 or 8 colors: palette entries 0-15 are the standard colors
+	if c.Type != ColorTypeTrueColor && c.Index < 16 {
+		idx := int(c.Index)
+		if depth < 16 && idx >= 8 {
+			idx -= 8 // Bright colors fall back to their normal variant
+		}
+		return StandardColor(idx)
+	}
+	n := 16
+	if depth < 16 {
+		n = 8
+	}
+	return StandardColor(nearestColorIndex(c, 0, n))
+}
+
+// nearestColorIndex returns the 256-color index in [from, to) closest to c
+func nearestColorIndex(c Color, from, to int) int {
+	best, bestDist := from, -1
+	for i := from; i < to; i++ {
+		rgb := Get256ColorRGB(i)
+		dr, dg, db := int(c.R)-int(rgb.R), int(c.G)-int(rgb.G), int(c.B)-int(rgb.B)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
 // ToHex returns the color as a hex string like "#RRGGBB"
 func (c Color) ToHex() string {
 	return "#" + hexByte(c.R) + hexByte(c.G) + hexByte(c.B)
@@ -237,6 +291,34 @@ func ParseHexColor(s string) (Color, bool) {
 	return TrueColor(r, g, b), true
 }
 
+// ParseXColor parses a color as programs send it in OSC 4/10/11/12:
+// "rgb:R/G/B" with 1 to 4 hex digits per component (scaled to 8 bits),
+// or "#RGB" / "#RRGGBB" as ParseHexColor does
+func ParseXColor(s string) (Color, bool) {
+	rest, ok := strings.CutPrefix(s, "rgb:")
+	if !ok {
+		return ParseHexColor(s)
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 {
+		return Color{}, false
+	}
+	var rgb [3]uint8
+	for i, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return Color{}, false
+		}
+		v, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return Color{}, false
+		}
+		// Scale from len(part)*4 bits to 8 (e.g. "f" -> 0xff, "ffff" -> 0xff)
+		maxV := uint64(1)<<(4*len(part)) - 1
+		rgb[i] = uint8((v*255 + maxV/2) / maxV)
+	}
+	return TrueColor(rgb[0], rgb[1], rgb[2]), true
+}
+
 func parseHexNibble(c byte) uint8 {
 	switch {
 	case c >= '0' && c <= '9':
@@ -259,22 +341,39 @@ var ColorNames = map[string]int{
 }
 
 // ColorScheme defines the colors used by the terminal for both dark and light modes.
-// The terminal switches between modes via DECSCNM (\e[?5h / \e[?5l).
+// The host selects the mode with Buffer.SetDarkTheme; DECSCNM (\e[?5h) inverts
+// every cell in whichever mode is active (see ReverseVideo).
 type ColorScheme struct {
-	// Dark mode colors (DECSCNM off / \e[?5l)
+	// Dark mode colors
 	DarkForeground Color
 	DarkBackground Color
 	DarkPalette    []Color // 16 ANSI colors for dark mode
 
-	// Light mode colors (DECSCNM on / \e[?5h)
+	// Light mode colors
 	LightForeground Color
 	LightBackground Color
 	LightPalette    []Color // 16 ANSI colors for light mode
 
 	// Shared settings
-	Cursor    Color
-	Selection Color
-	BlinkMode BlinkMode
+	Cursor          Color
+	Selection       Color
+	SearchHighlight Color // Background for cells in a search match (see Buffer.SetSearchMatches)
+	BlinkMode       BlinkMode
+
+	// Opacity of the selection and focused block cursor over the cell
+	// background, from 0 to 1. 0 (unset) and 1 draw them opaque; the
+	// selection then replaces the background and the block cursor swaps
+	// foreground and background.
+	SelectionAlpha float64
+	CursorAlpha    float64
+
+	// ScrollbackBoundary colors the dashed line between scrollback and the
+	// logical screen. Left unset it follows ScrollbackBoundaryColor's defaults.
+	ScrollbackBoundary Color
+
+	// ReverseScreen is set by ReverseVideo: ResolveColors then inverts
+	// every cell, not just those in the default colors
+	ReverseScreen bool
 }
 
 // Foreground returns the foreground color for the specified mode
@@ -326,14 +425,120 @@ func (s ColorScheme) ResolveColor(c Color, isFg bool, isDark bool) Color {
 	case ColorTypePalette:
 		// For 256-color palette, indices 0-15 use scheme palette
 		idx := int(c.Index)
-		if idx < 16 && idx < len(palette) {
+		if idx < len(palette) {
 			return palette[idx]
 		}
 		// Indices 16-255 use the fixed 256-color values (already baked in)
+		// unless program-set colors extended the palette (see
+		// Buffer.SetPaletteColor)
 	}
 	return c
 }
 
+// ScrollbackBoundaryColor returns the color of the scrollback boundary line.
+// An unset ScrollbackBoundary gives the classic yellow on dark backgrounds
+// and a darker amber on light ones, where yellow has too little contrast.
+func (s ColorScheme) ScrollbackBoundaryColor(isDark bool) Color {
+	if s.ScrollbackBoundary == (Color{}) {
+		if isDark {
+			return TrueColor(255, 200, 0)
+		}
+		return TrueColor(170, 120, 0)
+	}
+	return s.ResolveColor(s.ScrollbackBoundary, true, isDark)
+}
+
+// BlendColor returns over composited onto base with the given opacity
+// (0 = base, 1 = over). The result is always a true color.
+func BlendColor(base, over Color, alpha float64) Color {
+	if alpha <= 0 {
+		alpha = 0
+	} else if alpha > 1 {
+		alpha = 1
+	}
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a)*(1-alpha) + float64(b)*alpha + 0.5)
+	}
+	return TrueColor(mix(base.R, over.R), mix(base.G, over.G), mix(base.B, over.B))
+}
+
+// translucent reports whether alpha asks for blending rather than an
+// opaque overlay
+func translucent(alpha float64) bool {
+	return alpha > 0 && alpha < 1
+}
+
+// SelectionBackground returns the background of a selected cell whose own
+// background is bg: the selection color, blended over bg when
+// SelectionAlpha is translucent so colored text stays readable
+func (s ColorScheme) SelectionBackground(bg Color) Color {
+	if translucent(s.SelectionAlpha) {
+		return BlendColor(bg, s.Selection, s.SelectionAlpha)
+	}
+	return s.Selection
+}
+
+// BlockCursorColors returns the foreground and background of the cell
+// under a focused block cursor. An opaque cursor swaps fg and bg; a
+// translucent CursorAlpha tints bg with the cursor color and keeps fg.
+func (s ColorScheme) BlockCursorColors(fg, bg Color) (Color, Color) {
+	if translucent(s.CursorAlpha) {
+		return fg, BlendColor(bg, s.Cursor, s.CursorAlpha)
+	}
+	return bg, fg
+}
+
+// ReverseVideo returns a copy of the scheme as shown while DECSCNM is set:
+// the default foreground and background swapped in both modes, and
+// ReverseScreen toggled so ResolveColors inverts explicit colors too
+func (s ColorScheme) ReverseVideo() ColorScheme {
+	s.DarkForeground, s.DarkBackground = s.DarkBackground, s.DarkForeground
+	s.LightForeground, s.LightBackground = s.LightBackground, s.LightForeground
+	s.ReverseScreen = !s.ReverseScreen
+	return s
+}
+
+// FaintColor returns c with its brightness reduced by 40%, as used for
+// faint (SGR 2) text
+func FaintColor(c Color) Color {
+	c.R = uint8(float64(c.R) * 0.6)
+	c.G = uint8(float64(c.G) * 0.6)
+	c.B = uint8(float64(c.B) * 0.6)
+	return c
+}
+
+// ResolveForeground resolves a cell's foreground color, applying the faint
+// attribute
+func (s ColorScheme) ResolveForeground(cell *Cell, isDark bool) Color {
+	fg := s.ResolveColor(cell.Foreground, true, isDark)
+	if cell.Faint {
+		fg = FaintColor(fg)
+	}
+	return fg
+}
+
+// ResolveColors resolves a foreground and background pair. With
+// ReverseScreen set the pair is inverted, whether the colors are the
+// defaults or explicit (SGR 30;47 draws white on black).
+func (s ColorScheme) ResolveColors(fg, bg Color, isDark bool) (Color, Color) {
+	if s.ReverseScreen {
+		// The defaults are already swapped, so resolving each color in the
+		// other's role inverts default and explicit colors alike
+		return s.ResolveColor(bg, true, isDark), s.ResolveColor(fg, false, isDark)
+	}
+	return s.ResolveColor(fg, true, isDark), s.ResolveColor(bg, false, isDark)
+}
+
+// ResolveCellColors resolves a cell's foreground and background with
+// ResolveColors, applying the faint attribute to the foreground
+func (s ColorScheme) ResolveCellColors(cell *Cell, isDark bool) (fg, bg Color) {
+	fg, bg = s.ResolveColors(cell.Foreground, cell.Background, isDark)
+	if cell.Faint {
+		fg = FaintColor(fg)
+	}
+	return fg, bg
+}
+
 // ParseBlinkMode parses a blink mode string
 func ParseBlinkMode(s string) BlinkMode {
 	switch s {
@@ -341,6 +546,8 @@ func ParseBlinkMode(s string) BlinkMode {
 		return BlinkModeBlink
 	case "bright":
 		return BlinkModeBright
+	case "none":
+		return BlinkModeNone
 	default:
 		return BlinkModeBounce
 	}
@@ -379,7 +586,8 @@ func DefaultColorScheme() ColorScheme {
 		LightPalette:    ANSIColors,
 
 		// Shared
-		Cursor:    TrueColor(255, 255, 255),
-		Selection: TrueColor(68, 68, 68),
+		Cursor:          TrueColor(255, 255, 255),
+		Selection:       TrueColor(68, 68, 68),
+		SearchHighlight: TrueColor(110, 90, 0),
 	}
 }
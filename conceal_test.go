@@ -0,0 +1,19 @@
+package purfecterm
+
+import "testing"
+
+// Concealed text renders as blanks but selection still copies the real
+// characters.
+func TestSGRConceal(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	NewParser(b).Parse([]byte("pw:\x1b[8msecret\x1b[28m!"))
+
+	if got := b.RenderPlain(); got != "pw:      !\n" {
+		t.Fatalf("RenderPlain = %q, want concealed blanks", got)
+	}
+	b.StartSelection(3, 0)
+	b.UpdateSelection(8, 0)
+	if got := b.GetSelectedText(); got != "secret" {
+		t.Fatalf("GetSelectedText = %q, want %q", got, "secret")
+	}
+}
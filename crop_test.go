@@ -0,0 +1,30 @@
+package purfecterm
+
+import "testing"
+
+// An inverted crop hides sprite pixels inside the rectangle and keeps the
+// ones outside; a normal crop does the opposite.
+func TestInvertedCropRect(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	b.SetInvertedCropRect(1, 8, 8, 24, 24)
+	crop := b.GetCropRect(1)
+	if crop == nil || !crop.Inverted {
+		t.Fatalf("SetInvertedCropRect stored %+v", crop)
+	}
+
+	if !crop.HidesArea(10, 10, 11, 11) {
+		t.Error("pixel inside an inverted crop should not be drawn")
+	}
+	if crop.HidesArea(30, 10, 31, 11) {
+		t.Error("pixel outside an inverted crop should be drawn")
+	}
+	if crop.HidesArea(7, 10, 9, 11) {
+		t.Error("pixel straddling the edge should be drawn")
+	}
+
+	b.SetCropRect(1, 8, 8, 24, 24)
+	crop = b.GetCropRect(1)
+	if crop.HidesArea(10, 10, 11, 11) || !crop.HidesArea(30, 10, 31, 11) {
+		t.Error("normal crop should draw inside and hide outside")
+	}
+}
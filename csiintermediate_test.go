@@ -0,0 +1,96 @@
+package purfecterm
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Intermediates and private markers pick the control: CSI 2 SP q is
+// DECSCUSR and CSI 5 $ p is DECRQM, neither of which may run as (or
+// disturb) the plain CSI q / CSI p, or the sequences that follow.
+func TestCSIIntermediateDispatch(t *testing.T) {
+	b := NewBuffer(20, 5, 0)
+	p := NewParser(b)
+	var reply bytes.Buffer
+	p.SetResponseWriter(&reply)
+
+	p.Parse([]byte("\x1b[2 q\x1b[3;4H"))
+	if shape, blink := b.GetCursorStyle(); shape != 0 || blink != CursorBlinkNone {
+		t.Errorf("CSI 2 SP q: shape=%d blink=%d, want steady block", shape, blink)
+	}
+	if x, y := b.GetCursor(); x != 3 || y != 2 {
+		t.Errorf("CUP after DECSCUSR: cursor (%d,%d), want (3,2)", x, y)
+	}
+
+	// DECRQM for an ANSI mode gets DECRPM back; it is not DECSTR
+	b.SetCursorVisible(false)
+	p.Parse([]byte("\x1b[20h\x1b[5$p\x1b[20$p\x1b[1;1H"))
+	if got, want := reply.String(), "\x1b[5;0$y\x1b[20;1$y"; got != want {
+		t.Errorf("DECRQM replies %q, want %q", got, want)
+	}
+	if b.IsCursorVisible() {
+		t.Error("CSI 5 $ p ran a soft reset")
+	}
+	if x, y := b.GetCursor(); x != 0 || y != 0 {
+		t.Errorf("CUP after DECRQM: cursor (%d,%d), want (0,0)", x, y)
+	}
+
+	reply.Reset()
+	p.Parse([]byte("\x1b[?25$p\x1b[?2027$p\x1b[?2004$p"))
+	if got, want := reply.String(), "\x1b[?25;2$y\x1b[?2027;3$y\x1b[?2004;2$y"; got != want {
+		t.Errorf("private DECRQM replies %q, want %q", got, want)
+	}
+
+	// DECSTR still needs its '!'
+	p.Parse([]byte("\x1b[!p"))
+	if !b.IsCursorVisible() {
+		t.Error("CSI ! p did not soft reset")
+	}
+}
+
+// Markers other than '?' and unexpected intermediates don't run the plain
+// control of the same final byte, and are reported instead
+func TestCSIUnknownMarkers(t *testing.T) {
+	b := NewBuffer(20, 5, 0)
+	p := NewParser(b)
+	var unknown []string
+	p.SetUnknownSequenceCallback(func(seq string) { unknown = append(unknown, seq) })
+
+	// xterm modifyOtherKeys and a keypad-mode query: not underline/bold
+	p.Parse([]byte("\x1b[>4;1m\x1b[=5mX"))
+	if c := b.GetCell(0, 0); c.Char != 'X' || c.Underline || c.Bold || c.Blink {
+		t.Errorf("cell after CSI > 4;1 m and CSI = 5 m: %+v, want plain 'X'", c)
+	}
+	// DECCARA-style intermediate: not DECSTBM; two intermediates: dropped
+	p.Parse([]byte("\x1b[2;3$r\x1b[1 !q"))
+	if top, bottom := b.GetScrollRegion(); top != 0 || bottom != 4 {
+		t.Errorf("scroll region %d-%d, want untouched 0-4", top, bottom)
+	}
+	// Out of order: a parameter after the intermediate, a marker mid-way
+	p.Parse([]byte("\x1b[ 2q\x1b[1?25l"))
+	if !b.IsCursorVisible() {
+		t.Error("malformed CSI 1 ? 25 l hid the cursor")
+	}
+	if shape, _ := b.GetCursorStyle(); shape != 0 {
+		t.Errorf("malformed CSI SP 2 q set cursor shape %d", shape)
+	}
+
+	want := []string{"CSI > m", "CSI = m", "CSI $ r", "CSI SP q", "CSI SP q", "CSI l"}
+	if len(unknown) != len(want) {
+		t.Fatalf("reported %q, want %q", unknown, want)
+	}
+	for i := range want {
+		if unknown[i] != want[i] {
+			t.Errorf("report %d = %q, want %q", i, unknown[i], want[i])
+		}
+	}
+
+	// The parser is back in step afterwards
+	p.Parse([]byte("\x1b[4 q\x1b[1mY"))
+	if shape, _ := b.GetCursorStyle(); shape != 1 {
+		t.Errorf("DECSCUSR after malformed input: shape %d, want underline", shape)
+	}
+	if c := b.GetCell(1, 0); c.Char != 'Y' || !c.Bold {
+		t.Errorf("SGR after malformed input: %+v, want bold 'Y'", c)
+	}
+}
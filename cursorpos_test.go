@@ -0,0 +1,53 @@
+package purfecterm
+
+import "testing"
+
+// CHA/HPA and VPA are absolute on one axis and keep the other; HPR and
+// VPR move relative to the cursor.
+func TestCursorAxisMoves(t *testing.T) {
+	b := NewBuffer(80, 24, 100)
+	p := NewParser(b)
+
+	steps := []struct {
+		seq  string
+		x, y int
+	}{
+		{"\x1b[5;7H", 6, 4},
+		{"\x1b[40G", 39, 4},
+		{"\x1b[10d", 39, 9},
+		{"\x1b[3`", 2, 9},
+		{"\x1b[5a", 7, 9},
+		{"\x1b[a", 8, 9},
+		{"\x1b[2e", 8, 11},
+		{"\x1b[e", 8, 12},
+		{"\x1b[200G", 79, 12},
+		{"\x1b[99d", 79, 23},
+		{"\x1b[G", 0, 23},
+		{"\x1b[d", 0, 0},
+	}
+	for _, s := range steps {
+		p.Parse([]byte(s.seq))
+		if x, y := b.GetCursor(); x != s.x || y != s.y {
+			t.Errorf("after %q: cursor %d,%d, want %d,%d", s.seq, x, y, s.x, s.y)
+		}
+	}
+}
+
+// CHA counts visual columns, so a wide character before the target
+// takes two of them.
+func TestCHAVisualColumn(t *testing.T) {
+	b := NewBuffer(20, 5, 100)
+	p := NewParser(b)
+
+	p.Parse([]byte("中ab\x1b[4G"))
+	if x, _ := b.GetCursor(); x != 2 {
+		t.Errorf("CSI 4 G after a wide char: logical x = %d, want 2", x)
+	}
+
+	var moves int
+	b.SetCursorMoveCallback(func(x, y int) { moves++ })
+	p.Parse([]byte("\x1b[1G\x1b[3d"))
+	if moves != 2 {
+		t.Errorf("cursor callback fired %d times, want 2", moves)
+	}
+}
@@ -0,0 +1,62 @@
+package purfecterm
+
+import "testing"
+
+// DECSCUSR (CSI Ps SP q) selects cursor shape and blink; Ps 4 is a steady
+// underline, Ps 0 restores the blinking block.
+func TestDECSCUSR(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	p := NewParser(b)
+
+	p.Parse([]byte("\x1b[4 q"))
+	if shape, blink := b.GetCursorStyle(); shape != 1 || blink != 0 {
+		t.Fatalf("CSI 4 SP q: shape=%d blink=%d, want underline (1) steady (0)", shape, blink)
+	}
+	p.Parse([]byte("\x1b[5 q"))
+	if shape, blink := b.GetCursorStyle(); shape != 2 || blink != 1 {
+		t.Fatalf("CSI 5 SP q: shape=%d blink=%d, want bar (2) blinking (1)", shape, blink)
+	}
+	p.Parse([]byte("\x1b[0 q"))
+	if shape, blink := b.GetCursorStyle(); shape != 0 || blink != 1 {
+		t.Fatalf("CSI 0 SP q: shape=%d blink=%d, want blinking block", shape, blink)
+	}
+}
+
+// Blink values follow one contract: 0 steady, 1 slow, 2 fast. DECSCUSR
+// picks steady or blinking; DECSET/DECRST 12 picks the rate, which a
+// later blinking DECSCUSR keeps.
+func TestCursorBlinkContract(t *testing.T) {
+	b := NewBuffer(20, 2, 100)
+	p := NewParser(b)
+
+	for _, tt := range []struct {
+		seq   string
+		blink int
+	}{
+		{"\x1b[2 q", CursorBlinkNone},
+		{"\x1b[1 q", CursorBlinkSlow},
+		{"\x1b[?12h", CursorBlinkFast},
+		{"\x1b[5 q", CursorBlinkFast},
+		{"\x1b[6 q", CursorBlinkNone},
+		{"\x1b[3 q", CursorBlinkSlow},
+		{"\x1b[?12l", CursorBlinkSlow},
+	} {
+		p.Parse([]byte(tt.seq))
+		if _, blink := b.GetCursorStyle(); blink != tt.blink {
+			t.Errorf("after %q: blink = %d, want %d", tt.seq, blink, tt.blink)
+		}
+	}
+
+	// A steady cursor has no blink interval, so widgets draw it solid
+	if d := CursorBlinkInterval(CursorBlinkNone); d != 0 {
+		t.Errorf("steady interval = %v, want 0", d)
+	}
+	if slow, fast := CursorBlinkInterval(CursorBlinkSlow), CursorBlinkInterval(CursorBlinkFast); fast <= 0 || fast >= slow {
+		t.Errorf("intervals slow=%v fast=%v, want fast shorter than slow", slow, fast)
+	}
+
+	b.SetCursorStyle(0, 7)
+	if _, blink := b.GetCursorStyle(); blink != CursorBlinkFast {
+		t.Errorf("SetCursorStyle(0, 7): blink = %d, want clamped to fast", blink)
+	}
+}
@@ -0,0 +1,32 @@
+package purfecterm
+
+import "testing"
+
+// After the initial full-screen report, writing one character damages
+// exactly one cell; clearing the screen yields the full-screen marker.
+func TestTakeDamage(t *testing.T) {
+	b := NewBuffer(10, 3, 100)
+	if spans := b.TakeDamage(); len(spans) == 0 || !spans[0].FullScreen {
+		t.Fatalf("first TakeDamage should start with the full-screen marker, got %+v", spans)
+	}
+	if spans := b.TakeDamage(); len(spans) != 0 {
+		t.Fatalf("no change should yield no damage, got %+v", spans)
+	}
+
+	b.SetCursor(4, 1)
+	b.WriteChar('x')
+	spans := b.TakeDamage()
+	if len(spans) != 1 {
+		t.Fatalf("want one span, got %+v", spans)
+	}
+	s := spans[0]
+	if s.FullScreen || s.Row != 1 || s.StartCol != 4 || len(s.Cells) != 1 || s.Cells[0].Char != 'x' {
+		t.Fatalf("want single-cell span at (4,1) holding 'x', got %+v", s)
+	}
+
+	b.ClearScreen()
+	spans = b.TakeDamage()
+	if len(spans) != 4 || !spans[0].FullScreen {
+		t.Fatalf("clear should report the marker plus every row, got %d spans", len(spans))
+	}
+}
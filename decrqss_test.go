@@ -0,0 +1,39 @@
+package purfecterm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDECRQSS(t *testing.T) {
+	b := NewBuffer(20, 10, 0)
+	p := NewParser(b)
+	var resp bytes.Buffer
+	p.SetResponseWriter(&resp)
+
+	p.Parse([]byte("\x1b[1;4m\x1bP$qm\x1b\\"))
+	got := resp.String()
+	if !strings.HasPrefix(got, "\x1bP1$r") || !strings.HasSuffix(got, "m\x1b\\") {
+		t.Fatalf("SGR reply %q is not DCS 1 $ r ... m ST", got)
+	}
+	if !strings.Contains(got, "1;4") {
+		t.Errorf("SGR reply %q should encode bold and underline as 1;4", got)
+	}
+
+	resp.Reset()
+	p.Parse([]byte("\x1b[3;8r\x1bP$qr\x1b\\"))
+	if got, want := resp.String(), "\x1bP1$r3;8r\x1b\\"; got != want {
+		t.Errorf("DECSTBM reply %q, want %q", got, want)
+	}
+
+	resp.Reset()
+	p.Parse([]byte("\x1bP$q\"p\x1b\\x"))
+	if got, want := resp.String(), "\x1bP0$r\x1b\\"; got != want {
+		t.Errorf("unsupported request reply %q, want %q", got, want)
+	}
+	// The ST is consumed; only the text after it reaches the screen
+	if c := b.GetCell(0, 0).Char; c != 'x' {
+		t.Errorf("cell 0,0 = %q, want 'x'", c)
+	}
+}
@@ -0,0 +1,26 @@
+package purfecterm
+
+import "testing"
+
+func TestDiffVisible(t *testing.T) {
+	a := NewBuffer(10, 3, 10)
+	b := NewBuffer(10, 3, 10)
+	NewParser(a).Parse([]byte("hello\r\nworld"))
+	NewParser(b).Parse([]byte("hello\r\nwOrld"))
+
+	diffs := a.DiffVisible(b)
+	if len(diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1: %+v", len(diffs), diffs)
+	}
+	if d := diffs[0]; d.X != 1 || d.Y != 1 || d.Got.Char != 'o' || d.Want.Char != 'O' {
+		t.Fatalf("diff = %+v, want 'o' vs 'O' at 1,1", d)
+	}
+	if a.EqualVisible(b) {
+		t.Fatal("EqualVisible should be false")
+	}
+
+	NewParser(b).Parse([]byte("\x1b[2;2Ho"))
+	if !a.EqualVisible(b) {
+		t.Fatalf("buffers should match, diffs: %+v", a.DiffVisible(b))
+	}
+}
@@ -0,0 +1,44 @@
+package purfecterm
+
+import "testing"
+
+// Selection columns on a double-width line are logical cells: the copied
+// text has each character once, and stops where the doubled line's
+// visible half ends.
+func TestSelectDoubleWidthLine(t *testing.T) {
+	b := NewBuffer(20, 3, 100)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b#6Title\r\nnormal line"))
+
+	b.StartSelection(0, 0)
+	b.UpdateSelection(19, 0)
+	if got := b.GetSelectedText(); got != "Title" {
+		t.Errorf("double-width title = %q, want %q", got, "Title")
+	}
+
+	// Text past the visible half (written before DECDWL) is not copied
+	p.Parse([]byte("\x1b[3;1H0123456789abcdefghij\x1b#3"))
+	b.StartSelection(0, 2)
+	b.UpdateSelection(19, 2)
+	if got := b.GetSelectedText(); got != "0123456789" {
+		t.Errorf("double-height line = %q, want %q", got, "0123456789")
+	}
+
+	// A multi-line selection clips only the doubled line
+	b.StartSelection(2, 0)
+	b.UpdateSelection(5, 1)
+	if got := b.GetSelectedText(); got != "tle\nnormal" {
+		t.Errorf("selection = %q, want %q", got, "tle\nnormal")
+	}
+
+	// The attribute follows the line into scrollback
+	p.Parse([]byte("\r\n\r\n\r\n"))
+	b.StartSelection(0, 0)
+	b.UpdateSelection(19, 0)
+	b.mu.Lock()
+	b.selStartY, b.selEndY = 0, 0 // Oldest scrollback line: the title
+	b.mu.Unlock()
+	if got := b.GetSelectedText(); got != "Title" {
+		t.Errorf("title from scrollback = %q, want %q", got, "Title")
+	}
+}
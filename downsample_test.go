@@ -0,0 +1,28 @@
+package purfecterm
+
+import "testing"
+
+func TestColorDownsample(t *testing.T) {
+	orange := TrueColor(255, 140, 0)
+	tests := []struct {
+		name  string
+		c     Color
+		depth int
+		want  Color
+	}{
+		{"true color kept at 24", orange, 24, orange},
+		{"true color to 256", TrueColor(0, 0, 255), 256, PaletteColor(21)},
+		{"palette kept at 256", PaletteColor(200), 256, PaletteColor(200)},
+		{"palette to 16", PaletteColor(196), 16, StandardColor(1)},
+		{"low palette index is the standard color", PaletteColor(12), 16, StandardColor(12)},
+		{"bright falls back at 8", StandardColor(9), 8, StandardColor(1)},
+		{"true color to 8", TrueColor(250, 250, 250), 8, StandardColor(7)},
+		{"default untouched", DefaultForeground, 8, DefaultForeground},
+		{"no color", orange, 0, Color{Type: ColorTypeDefault}},
+	}
+	for _, tc := range tests {
+		if got := tc.c.Downsample(tc.depth); got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
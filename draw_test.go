@@ -0,0 +1,90 @@
+package purfecterm
+
+import "testing"
+
+func TestDrawBox(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	b.DrawBox(1, 1, 4, 3, BoxSingle)
+
+	want := map[[2]int]rune{
+		{1, 1}: '┌', {2, 1}: '─', {3, 1}: '─', {4, 1}: '┐',
+		{1, 2}: '│', {2, 2}: ' ', {3, 2}: ' ', {4, 2}: '│',
+		{1, 3}: '└', {2, 3}: '─', {3, 3}: '─', {4, 3}: '┘',
+	}
+	for pos, ch := range want {
+		if got := b.GetCell(pos[0], pos[1]).Char; got != ch {
+			t.Errorf("cell %v: want %q, got %q", pos, ch, got)
+		}
+	}
+	if x, y := b.GetCursor(); x != 0 || y != 0 {
+		t.Errorf("DrawBox moved the cursor to %d,%d", x, y)
+	}
+
+	b.DrawBox(5, 0, 4, 3, BoxDouble)
+	if got := b.GetCell(5, 0).Char; got != '╔' {
+		t.Errorf("double top-left: got %q", got)
+	}
+	b.DrawBox(5, 0, 4, 3, BoxRounded)
+	if got := b.GetCell(8, 2).Char; got != '╯' {
+		t.Errorf("rounded bottom-right: got %q", got)
+	}
+}
+
+func TestFillRect(t *testing.T) {
+	b := NewBuffer(5, 3, 0)
+	attrs := EmptyCell()
+	attrs.Bold = true
+	b.FillRect(3, 1, 4, 4, '#', attrs) // clipped to the screen
+
+	for y := 1; y < 3; y++ {
+		for x := 3; x < 5; x++ {
+			if c := b.GetCell(x, y); c.Char != '#' || !c.Bold {
+				t.Errorf("cell %d,%d: got %q bold=%v", x, y, c.Char, c.Bold)
+			}
+		}
+	}
+	if c := b.GetCell(2, 1); c.Char == '#' {
+		t.Errorf("fill leaked left of the rectangle")
+	}
+}
+
+func TestSetCell(t *testing.T) {
+	b := NewBuffer(20, 8, 0)
+	cell := EmptyCell()
+	cell.Char = 'Z'
+	cell.Foreground = TrueColor(10, 20, 30)
+	cell.Background = StandardColor(4)
+	cell.Bold = true
+	cell.UnderlineStyle = UnderlineCurly
+	cell.Underline = true
+	b.SetCell(10, 5, cell)
+
+	if got := b.GetCell(10, 5); got != cell {
+		t.Errorf("read back %+v, want %+v", got, cell)
+	}
+	if x, y := b.GetCursor(); x != 0 || y != 0 {
+		t.Errorf("SetCell moved the cursor to %d,%d", x, y)
+	}
+	if !b.IsDirty() {
+		t.Error("SetCell should mark the buffer dirty")
+	}
+
+	b.SetCell(20, 5, cell) // off screen: ignored
+	b.SetCell(-1, 0, cell)
+
+	run := make([]Cell, 4)
+	for i, ch := range "wxyz" {
+		run[i] = cell
+		run[i].Char = ch
+	}
+	b.SetCellRange(18, 2, run) // clipped at the right edge, no wrap
+	if got := b.GetCell(18, 2).Char; got != 'w' {
+		t.Errorf("range start = %q, want 'w'", got)
+	}
+	if got := b.GetCell(19, 2).Char; got != 'x' {
+		t.Errorf("range end = %q, want 'x'", got)
+	}
+	if got := b.GetCell(0, 3).Char; got == 'y' {
+		t.Error("SetCellRange wrapped onto the next line")
+	}
+}
@@ -0,0 +1,28 @@
+package purfecterm
+
+import "testing"
+
+// EL with a colored background fills the rest of the line out to
+// EffectiveCols, and a line wrapped onto from it keeps that fill.
+func TestClearToEndOfLineBackground(t *testing.T) {
+	b := NewBuffer(8, 4, 0)
+	p := NewParser(b)
+	p.Parse([]byte("ab\x1b[44m\x1b[K"))
+
+	blue := StandardColor(4)
+	for x := 2; x < b.EffectiveCols(); x++ {
+		if got := b.GetVisibleCell(x, 0).Background; got != blue {
+			t.Errorf("cell %d,0: background %+v, want %+v", x, got, blue)
+		}
+	}
+	if got := b.GetVisibleCell(0, 0).Background; got == blue {
+		t.Error("EL recolored cells before the cursor")
+	}
+
+	p.Parse([]byte("cdefgxyz"))
+	for x := 2; x < b.EffectiveCols(); x++ {
+		if got := b.GetVisibleCell(x, 1).Background; got != blue {
+			t.Errorf("wrapped cell %d,1: background %+v, want %+v", x, got, blue)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package purfecterm
+
+import (
+	"fmt"
+	"testing"
+)
+
+// CSI 3J drops the scrollback and leaves the screen; CSI 2J blanks the
+// screen and leaves the scrollback
+func TestEraseInDisplaySavedLines(t *testing.T) {
+	b := NewBuffer(20, 4, 100)
+	p := NewParser(b)
+	for i := 0; i < 10; i++ {
+		p.Parse([]byte(fmt.Sprintf("\r\nline %d", i)))
+	}
+	if b.GetScrollbackSize() == 0 {
+		t.Fatal("no scrollback to clear")
+	}
+	before := b.VisibleText()
+	x, y := b.GetCursor()
+
+	p.Parse([]byte("\x1b[3J"))
+	if n := b.GetScrollbackSize(); n != 0 {
+		t.Errorf("scrollback after CSI 3J = %d lines, want 0", n)
+	}
+	if got := b.VisibleText(); got != before {
+		t.Errorf("screen after CSI 3J = %q, want unchanged %q", got, before)
+	}
+	if cx, cy := b.GetCursor(); cx != x || cy != y {
+		t.Errorf("cursor after CSI 3J = (%d,%d), want (%d,%d)", cx, cy, x, y)
+	}
+
+	p.Parse([]byte("\r\nmore\r\nmore\r\nmore\r\nmore"))
+	saved := b.GetScrollbackSize()
+	p.Parse([]byte("\x1b[2J"))
+	if n := b.GetScrollbackSize(); n != saved {
+		t.Errorf("scrollback after CSI 2J = %d lines, want %d kept", n, saved)
+	}
+	if got := b.GetCell(0, 3).Char; got != ' ' && got != 0 {
+		t.Errorf("screen after CSI 2J still shows %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+package purfecterm
+
+import "testing"
+
+// SGR 2 sets faint, SGR 22 clears both faint and bold, and a faint cell's
+// foreground resolves darker than the plain color.
+func TestSGRFaint(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	NewParser(b).Parse([]byte("\x1b[1;2mA\x1b[22mB"))
+
+	a := b.GetCell(0, 0)
+	if !a.Faint || !a.Bold {
+		t.Fatalf("SGR 1;2 should set bold and faint, got %+v", a)
+	}
+	if c := b.GetCell(1, 0); c.Faint || c.Bold {
+		t.Fatal("SGR 22 should clear bold and faint")
+	}
+
+	scheme := DefaultColorScheme()
+	plain := scheme.ResolveColor(a.Foreground, true, true)
+	dim := scheme.ResolveForeground(&a, true)
+	if int(dim.R)+int(dim.G)+int(dim.B) >= int(plain.R)+int(plain.G)+int(plain.B) {
+		t.Fatalf("faint color %v should be darker than %v", dim, plain)
+	}
+
+	NewParser(b).Parse([]byte("\x1b[2m\x1b[0mC"))
+	if b.GetCell(2, 0).Faint {
+		t.Fatal("SGR 0 should clear faint")
+	}
+}
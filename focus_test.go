@@ -0,0 +1,24 @@
+package purfecterm
+
+import "testing"
+
+// DEC 1004 enables focus reports; they are empty while it is off.
+func TestFocusReporting(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	p := NewParser(b)
+
+	if got := b.FocusEvent(true); got != nil {
+		t.Fatalf("focus report while disabled = %q", got)
+	}
+	p.Parse([]byte("\x1b[?1004h"))
+	if got := string(b.FocusEvent(true)); got != "\x1b[I" {
+		t.Fatalf("focus in = %q", got)
+	}
+	if got := string(b.FocusEvent(false)); got != "\x1b[O" {
+		t.Fatalf("focus out = %q", got)
+	}
+	p.Parse([]byte("\x1b[?1004l"))
+	if b.FocusEvent(false) != nil {
+		t.Fatal("focus reporting should be off after ?1004l")
+	}
+}
@@ -0,0 +1,64 @@
+package purfecterm
+
+import "testing"
+
+// GetLine pads a partly written row with the line's default cell (the
+// background EL erased with) and returns a copy, not the stored row.
+func TestGetLine(t *testing.T) {
+	b := NewBuffer(10, 3, 100)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b[2;1H\x1b[44mab\x1b[K\x1b[0m"))
+
+	line := b.GetLine(1)
+	if len(line) != 10 {
+		t.Fatalf("len = %d, want 10", len(line))
+	}
+	if line[0].Char != 'a' || line[1].Char != 'b' {
+		t.Fatalf("content = %q%q, want ab", line[0].Char, line[1].Char)
+	}
+	for x := 2; x < 10; x++ {
+		if line[x] != b.GetCell(x, 1) {
+			t.Fatalf("pad cell %d = %+v, want GetCell's %+v", x, line[x], b.GetCell(x, 1))
+		}
+		if line[x].Char != ' ' || line[x].Background != StandardColor(4) {
+			t.Fatalf("pad cell %d = %q bg %+v, want blue blank", x, line[x].Char, line[x].Background)
+		}
+	}
+
+	line[0].Char = 'z'
+	if b.GetCell(0, 1).Char != 'a' {
+		t.Fatal("GetLine returned the stored row instead of a copy")
+	}
+
+	if got := b.GetLine(2); len(got) != 10 || got[0].Char != b.GetCell(0, 2).Char {
+		t.Fatalf("unwritten row = %+v", got)
+	}
+	if b.GetLine(-1) != nil || b.GetLine(3) != nil {
+		t.Fatal("rows outside the screen should return nil")
+	}
+}
+
+func TestGetScrollbackLine(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	NewParser(b).Parse([]byte("one\r\ntwo\r\nthree\r\nfour"))
+
+	if n := b.GetScrollbackSize(); n != 2 {
+		t.Fatalf("scrollback size = %d, want 2", n)
+	}
+	for i, want := range []string{"one", "two"} {
+		line := b.GetScrollbackLine(i)
+		if len(line) != 10 {
+			t.Fatalf("line %d len = %d, want 10", i, len(line))
+		}
+		got := ""
+		for _, c := range line[:len(want)] {
+			got += string(c.Char)
+		}
+		if got != want || line[len(want)].Char != ' ' {
+			t.Errorf("scrollback line %d = %q, want %q then blanks", i, got, want)
+		}
+	}
+	if b.GetScrollbackLine(2) != nil || b.GetScrollbackLine(-1) != nil {
+		t.Fatal("out-of-range scrollback index should return nil")
+	}
+}
@@ -265,6 +265,17 @@ func (t *Terminal) Resize(cols, rows int) {
 	}
 }
 
+// SetGridSize forces the terminal to exactly cols x rows whatever the
+// widget's pixel size, until ClearGridSize (see Widget.SetGridSize)
+func (t *Terminal) SetGridSize(cols, rows int) {
+	t.widget.SetGridSize(cols, rows)
+}
+
+// ClearGridSize returns to fitting the grid to the widget
+func (t *Terminal) ClearGridSize() {
+	t.widget.ClearGridSize()
+}
+
 // GetSize returns the terminal size
 func (t *Terminal) GetSize() (cols, rows int) {
 	return t.widget.GetSize()
@@ -314,6 +325,18 @@ func (t *Terminal) CopySelection() {
 	t.widget.CopySelection()
 }
 
+// SetCopyTrailingNewline chooses whether copied multi-line selections end
+// with a newline (see Widget.SetCopyTrailingNewline)
+func (t *Terminal) SetCopyTrailingNewline(enabled bool) {
+	t.widget.SetCopyTrailingNewline(enabled)
+}
+
+// SetCtrlCCopiesSelection chooses whether Ctrl+C copies a selection or
+// sends ^C (see Widget.SetCtrlCCopiesSelection)
+func (t *Terminal) SetCtrlCCopiesSelection(enabled bool) {
+	t.widget.SetCtrlCCopiesSelection(enabled)
+}
+
 // PasteClipboard pastes text from clipboard into terminal
 func (t *Terminal) PasteClipboard() {
 	t.widget.PasteClipboard()
@@ -347,6 +370,12 @@ func (t *Terminal) SetFontFallbacks(unicodeFont, cjkFont string) {
 	t.widget.SetFontFallbacks(unicodeFont, cjkFont)
 }
 
+// SetFontFallbackChain sets an ordered chain of fallback fonts, tried in
+// order for characters missing from the main font
+func (t *Terminal) SetFontFallbackChain(fonts []string) {
+	t.widget.SetFontFallbackChain(fonts)
+}
+
 // SetFont sets the terminal font family and size
 func (t *Terminal) SetFont(family string, size int) {
 	t.widget.SetFont(family, size)
@@ -194,6 +194,25 @@ static int pango_text_width_standalone(const char *text, const char *font_family
 
     return width;
 }
+
+// Set antialiasing and hinting on a cairo context. Pango layouts created
+// from the context afterwards (pango_cairo_create_layout) inherit them.
+static void set_font_rendering(cairo_t *cr, int antialias, int hint_style) {
+    cairo_font_options_t *opts = cairo_font_options_create();
+    cairo_font_options_set_antialias(opts, (cairo_antialias_t)antialias);
+    cairo_font_options_set_hint_style(opts, (cairo_hint_style_t)hint_style);
+    cairo_set_font_options(cr, opts);
+    cairo_font_options_destroy(opts);
+}
+
+// Read back the antialias and hint style set on a cairo context
+static void get_font_rendering(cairo_t *cr, int *antialias, int *hint_style) {
+    cairo_font_options_t *opts = cairo_font_options_create();
+    cairo_get_font_options(cr, opts);
+    *antialias = cairo_font_options_get_antialias(opts);
+    *hint_style = cairo_font_options_get_hint_style(opts);
+    cairo_font_options_destroy(opts);
+}
 */
 import "C"
 
@@ -203,6 +222,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/gotk3/gotk3/cairo"
@@ -215,6 +235,12 @@ import (
 // Left padding for terminal content (pixels)
 const terminalLeftPadding = 8
 
+// Minimum drawing area size, small so the terminal can be resized freely
+const (
+	minDrawingAreaWidth  = 100
+	minDrawingAreaHeight = 50
+)
+
 // Widget is a GTK terminal emulator widget
 // glyphCacheEntry stores a cached rendered glyph surface
 type glyphCacheEntry struct {
@@ -374,6 +400,13 @@ type Widget struct {
 	charHeight        int
 	charAscent        int
 
+	// Fallback chain, tried in order by glyph coverage
+	fontFallbacks []string
+
+	// Font resolution per (main family, rune), so glyph coverage is asked of
+	// Pango once rather than every frame; cleared when the fonts change
+	fontCache map[fontCacheKey]fontChoice
+
 	// Color scheme
 	scheme purfecterm.ColorScheme
 
@@ -402,7 +435,8 @@ type Widget struct {
 	blinkTickCount int // Counter for variable blink rates
 
 	// Text blink animation (bobbing wave)
-	blinkPhase float64 // Animation phase in radians (0 to 2*PI)
+	blinkPhase float64                   // Animation phase in radians (0 to 2*PI)
+	blinkAnim  purfecterm.BlinkAnimation // Cycle, bounce shape and on/off duty cycle
 
 	// Focus state
 	hasFocus bool
@@ -410,14 +444,26 @@ type Widget struct {
 	// Mouse reporting
 	mouseReportingEnabled bool // When true, forward mouse events to PTY when app requests tracking
 
+	// Draw U+2500-U+259F as cell-sized rectangles instead of font glyphs
+	drawBoxGlyphs bool
+
+	// Text antialiasing and hinting (cairo defaults follow the desktop)
+	fontAntialias cairo.Antialias
+	fontHintStyle cairo.HintStyle
+
 	// Callback when data should be written to PTY
 	onInput func([]byte)
 
 	// Callback when terminal size changes (for PTY notification)
 	onResize func(cols, rows int)
 
+	// Grid forced by SetGridSize (0 = fit the grid to the widget)
+	gridCols, gridRows int
+
 	// Clipboard
-	clipboard *gtk.Clipboard
+	clipboard            *gtk.Clipboard
+	copyTrailingNewline  bool // End copied multi-line selections with "\n"
+	ctrlCCopiesSelection bool // Ctrl+C copies a selection instead of sending ^C
 
 	// Context menu for right-click
 	contextMenu            *gtk.Menu
@@ -426,6 +472,65 @@ type Widget struct {
 	// Terminal capabilities (for PawScript channel integration)
 	// Automatically updated on resize
 	termCaps *purfecterm.TerminalCapabilities
+
+	// Device pixels per logical pixel forced by SetScaleFactorOverride;
+	// 0 follows the monitor's scale factor
+	scaleOverride float64
+
+	// Vertical scrollbar presentation, and for ScrollbarOverlayHint the
+	// offset last seen and when it last changed (the hint fades from there)
+	scrollbarMode  ScrollbarMode
+	hintOffset     int
+	hintScrolledAt time.Time
+}
+
+// ScrollbarMode selects how the widget shows the vertical scroll position
+type ScrollbarMode int
+
+const (
+	ScrollbarAlways      ScrollbarMode = iota // A GtkScrollbar is always shown (default)
+	ScrollbarAuto                             // The scrollbar is shown only while there is scrollback to scroll
+	ScrollbarOverlayHint                      // No scrollbar; a thin indicator fades in over the right edge when scrolling
+	ScrollbarHidden                           // No scrollbar and no indicator
+)
+
+// Overlay hint geometry and timing: the indicator is fully visible for
+// scrollHintHold after the last scroll, then fades out over scrollHintFade
+const (
+	scrollHintWidth     = 4.0  // Indicator thickness in pixels
+	scrollHintInset     = 2.0  // Gap between the indicator and the right edge
+	scrollHintMinLength = 16.0 // Shortest indicator, however long the scrollback
+	scrollHintHold      = 800 * time.Millisecond
+	scrollHintFade      = 400 * time.Millisecond
+)
+
+// scrollHintExtent returns where the overlay hint sits along a track height
+// pixels tall: its top and length. The length is the visible share of the
+// rows+maxOffset lines (at least scrollHintMinLength), and it sits at the
+// bottom when offset is 0 (live output) and at the top when offset is
+// maxOffset, like the scrollbar thumb. ok is false when there is nothing
+// to scroll.
+func scrollHintExtent(offset, maxOffset, rows int, height float64) (top, length float64, ok bool) {
+	if maxOffset <= 0 || rows <= 0 || height <= 0 {
+		return 0, 0, false
+	}
+	offset = max(0, min(offset, maxOffset))
+	length = height * float64(rows) / float64(rows+maxOffset)
+	length = min(max(length, scrollHintMinLength), height)
+	top = (height - length) * float64(maxOffset-offset) / float64(maxOffset)
+	return top, length, true
+}
+
+// scrollHintAlpha returns the overlay hint's opacity since after the last
+// scroll: 1 while held, then fading linearly to 0
+func scrollHintAlpha(since time.Duration) float64 {
+	switch {
+	case since < scrollHintHold:
+		return 1
+	case since >= scrollHintHold+scrollHintFade:
+		return 0
+	}
+	return 1 - float64(since-scrollHintHold)/float64(scrollHintFade)
 }
 
 // NewWidget creates a new terminal widget with the specified dimensions
@@ -439,11 +544,15 @@ func NewWidget(cols, rows, scrollbackSize int) (*Widget, error) {
 		scheme:        purfecterm.DefaultColorScheme(),
 		cursorBlinkOn: true,
 		glyphCache:    newGlyphCache(4096), // Cache up to 4096 rendered glyphs
+		blinkAnim:     purfecterm.DefaultBlinkAnimation,
+
+		ctrlCCopiesSelection: true,
 	}
 
 	// Create buffer and parser
 	w.buffer = purfecterm.NewBuffer(cols, rows, scrollbackSize)
 	w.parser = purfecterm.NewParser(w.buffer)
+	w.parser.SetResponseWriter(responseWriter{w})
 
 	// Initialize terminal capabilities (auto-updated on resize)
 	w.termCaps = &purfecterm.TerminalCapabilities{
@@ -504,6 +613,7 @@ func NewWidget(cols, rows, scrollbackSize int) (*Widget, error) {
 	w.drawingArea.Connect("scroll-event", w.onScroll)
 	w.drawingArea.Connect("key-press-event", w.onKeyPress)
 	w.drawingArea.Connect("configure-event", w.onConfigure)
+	w.drawingArea.Connect("notify::scale-factor", w.onScaleFactorChanged)
 	w.drawingArea.Connect("focus-in-event", w.onFocusIn)
 	w.drawingArea.Connect("focus-out-event", w.onFocusOut)
 
@@ -589,26 +699,24 @@ func NewWidget(cols, rows, scrollbackSize int) (*Widget, error) {
 
 	// Set minimum size (small fixed value to allow flexible resizing)
 	w.updateFontMetrics()
-	w.drawingArea.SetSizeRequest(100, 50)
+	w.drawingArea.SetSizeRequest(minDrawingAreaWidth, minDrawingAreaHeight)
 
 	// Start animation timer (50ms interval for smooth bobbing wave animation)
 	// Also handles cursor blink timing
-	w.blinkTimerID = glib.TimeoutAdd(50, func() bool {
-		// Update text blink animation phase (complete wave cycle in ~1.5 seconds)
-		w.blinkPhase += 0.21         // ~1.5 second cycle
+	w.blinkTimerID = glib.TimeoutAdd(blinkTickMs, func() bool {
+		// Update text blink animation phase (one wave per blinkAnim.CycleMs)
+		w.mu.Lock()
+		w.blinkPhase += w.blinkAnim.PhaseStep(blinkTickMs)
 		if w.blinkPhase > 6.283185 { // 2*PI
 			w.blinkPhase -= 6.283185
 		}
+		w.mu.Unlock()
 
 		// Handle cursor blink timing (roughly every 250ms = 5 ticks)
 		w.blinkTickCount++
 		_, cursorBlink := w.buffer.GetCursorStyle()
-		if cursorBlink > 0 && w.hasFocus {
-			// Fast blink (2) toggles every 5 ticks (~250ms), slow blink (1) every 10 ticks (~500ms)
-			ticksNeeded := 10
-			if cursorBlink >= 2 {
-				ticksNeeded = 5
-			}
+		if ticksNeeded := cursorBlinkTicks(cursorBlink); ticksNeeded > 0 && w.hasFocus {
+			// Fast blink toggles every 5 ticks (~250ms), slow blink every 10 ticks (~500ms)
 			if w.blinkTickCount >= ticksNeeded {
 				w.blinkTickCount = 0
 				w.cursorBlinkOn = !w.cursorBlinkOn
@@ -627,6 +735,71 @@ func NewWidget(cols, rows, scrollbackSize int) (*Widget, error) {
 	return w, nil
 }
 
+// blinkTickMs is the animation timer interval
+const blinkTickMs = 50
+
+// cursorBlinkTicks returns how many timer ticks the cursor stays in each
+// blink phase, or 0 for a steady cursor that is always drawn
+func cursorBlinkTicks(blink int) int {
+	return int(purfecterm.CursorBlinkInterval(blink) / (blinkTickMs * time.Millisecond))
+}
+
+// SetBlinkAnimation sets the blink cycle duration, the bobbing wave's
+// height in pixels, and the phase shift in radians between neighbouring
+// columns (0 makes a row bob together). The defaults are 1500ms, 3.0 and
+// 0.5; a cycleMs of 0 or less keeps the default cycle.
+func (w *Widget) SetBlinkAnimation(cycleMs int, amplitudePx float64, phaseShift float64) {
+	w.mu.Lock()
+	w.blinkAnim.CycleMs = cycleMs
+	w.blinkAnim.Amplitude = amplitudePx
+	w.blinkAnim.PhaseShift = phaseShift
+	w.mu.Unlock()
+	w.drawingArea.QueueDraw()
+}
+
+// SetBlinkDutyCycle sets the fraction of each cycle that BlinkModeBlink
+// text is shown, clamped to 0..1 (default 0.5)
+func (w *Widget) SetBlinkDutyCycle(duty float64) {
+	w.mu.Lock()
+	w.blinkAnim.DutyCycle = math.Max(0, math.Min(1, duty))
+	w.mu.Unlock()
+	w.drawingArea.QueueDraw()
+}
+
+// BlinkAnimation returns the current blink animation parameters
+func (w *Widget) BlinkAnimation() purfecterm.BlinkAnimation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.blinkAnim
+}
+
+// SetBlinkMode changes how blinking text is drawn (bounce, blink, bright
+// background, or none for static text) without replacing the color scheme;
+// it takes effect on the next frame
+func (w *Widget) SetBlinkMode(mode purfecterm.BlinkMode) {
+	w.mu.Lock()
+	w.scheme.BlinkMode = mode
+	w.mu.Unlock()
+	w.drawingArea.QueueDraw()
+}
+
+// GetBlinkMode returns how blinking text is drawn
+func (w *Widget) GetBlinkMode() purfecterm.BlinkMode {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.scheme.BlinkMode
+}
+
+// blinkTextVisible reports whether onDraw draws a cell's text at the given
+// blink phase: only a blink cell under BlinkModeBlink is ever hidden
+// (bounce moves it, bright recolors the background, none leaves it static)
+func blinkTextVisible(cell *purfecterm.Cell, mode purfecterm.BlinkMode, anim purfecterm.BlinkAnimation, phase float64) bool {
+	if !cell.Blink || mode != purfecterm.BlinkModeBlink {
+		return true
+	}
+	return anim.Visible(cell.BlinkPhase(phase))
+}
+
 // Box returns the container widget
 func (w *Widget) Box() *gtk.Box {
 	return w.box
@@ -637,6 +810,57 @@ func (w *Widget) DrawingArea() *gtk.DrawingArea {
 	return w.drawingArea
 }
 
+// scaleFactor returns device pixels per logical pixel: the override when
+// set, else the scale factor of the monitor the widget is on
+func (w *Widget) scaleFactor() float64 {
+	w.mu.Lock()
+	override := w.scaleOverride
+	w.mu.Unlock()
+	if override > 0 {
+		return override
+	}
+	if w.drawingArea != nil {
+		if f := w.drawingArea.GetScaleFactor(); f > 1 {
+			return float64(f)
+		}
+	}
+	return 1
+}
+
+// SetScaleFactorOverride forces the device scale factor (e.g. 2 for a
+// HiDPI monitor) instead of following the monitor; 0 restores following
+// it. Meant for testing HiDPI rendering on any display.
+func (w *Widget) SetScaleFactorOverride(f float64) {
+	if f < 0 {
+		f = 0
+	}
+	w.mu.Lock()
+	w.scaleOverride = f
+	w.mu.Unlock()
+	w.onScaleFactorChanged()
+}
+
+// DeviceCellSize returns the size of one cell in device pixels, the
+// resolution cached glyph surfaces are rendered at. Layout (cols, rows,
+// mouse coordinates) stays in logical pixels, as GTK reports them.
+func (w *Widget) DeviceCellSize() (width, height int) {
+	scale := w.scaleFactor()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return int(math.Round(float64(w.charWidth) * scale)), int(math.Round(float64(w.charHeight) * scale))
+}
+
+// onScaleFactorChanged re-renders at the new resolution when the widget
+// moves to a monitor with a different scale factor (or the override changes)
+func (w *Widget) onScaleFactorChanged() {
+	w.glyphCache.clear()
+	if w.drawingArea == nil {
+		return
+	}
+	w.onConfigure(w.drawingArea, nil)
+	w.drawingArea.QueueDraw()
+}
+
 // SetFont sets the terminal font
 // family can be a comma-separated list of fonts; the first available one is used
 func (w *Widget) SetFont(family string, size int) {
@@ -646,6 +870,7 @@ func (w *Widget) SetFont(family string, size int) {
 	w.mu.Lock()
 	w.fontFamily = resolvedFont
 	w.fontSize = size
+	w.fontCache = nil
 	w.mu.Unlock()
 	// Trigger full configure handling to recalculate terminal dimensions,
 	// scrollbars, and update the buffer with new character metrics
@@ -665,9 +890,55 @@ func (w *Widget) SetFontFallbacks(unicodeFont, cjkFont string) {
 	w.mu.Lock()
 	w.fontFamilyUnicode = resolvedUnicode
 	w.fontFamilyCJK = resolvedCJK
+	w.fontCache = nil
+	w.mu.Unlock()
+}
+
+// SetFontFallbackChain sets an ordered chain of fallback fonts. A character
+// missing from the main font (and with no script font set) is drawn in the
+// first font of the chain that has a glyph for it, so any number of fonts
+// can share the coverage (e.g. symbols, emoji, then a CJK face). Fonts not
+// installed are dropped. The Unicode and CJK fallbacks from SetFontFallbacks
+// are still used for characters no chain font covers. Pass nil to clear.
+func (w *Widget) SetFontFallbackChain(fonts []string) {
+	var chain []string
+	for _, f := range fonts {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		cName := C.CString(f)
+		exists := C.font_family_exists(cName)
+		C.free(unsafe.Pointer(cName))
+		if exists != 0 {
+			chain = append(chain, f)
+		}
+	}
+
+	w.mu.Lock()
+	w.fontFallbacks = chain
+	w.fontCache = nil
 	w.mu.Unlock()
 }
 
+// GetFontFallbackChain returns the installed fonts of the fallback chain
+func (w *Widget) GetFontFallbackChain() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.fontFallbacks...)
+}
+
+// firstCoveringFont returns the first font in chain that hasGlyph reports
+// as covering r, or "" if none does
+func firstCoveringFont(chain []string, r rune, hasGlyph func(family string, r rune) bool) string {
+	for _, fam := range chain {
+		if hasGlyph(fam, r) {
+			return fam
+		}
+	}
+	return ""
+}
+
 // isCJKCharacter returns true if the rune is a CJK character
 // This includes CJK Unified Ideographs, Hiragana, Katakana, Hangul, and related ranges
 func isCJKCharacter(r rune) bool {
@@ -710,49 +981,102 @@ func isCJKCharacter(r rune) bool {
 	return false
 }
 
+// fontHasGlyph reports whether a font family has a glyph for r. It is a
+// variable so tests can count and fake coverage checks without Pango.
+var fontHasGlyph = func(family string, fontSize int, r rune) bool {
+	cFont := C.CString(family)
+	defer C.free(unsafe.Pointer(cFont))
+	return C.font_has_glyph(cFont, C.int(fontSize), C.gunichar(r)) != 0
+}
+
+// fontCacheKey identifies a cached font resolution
+type fontCacheKey struct {
+	family string
+	r      rune
+}
+
+// fontChoice is the cached outcome of resolving a rune against a main font:
+// whether the main font covers it, and otherwise the fallback to use ("" =
+// the main font anyway)
+type fontChoice struct {
+	covered  bool
+	fallback string
+}
+
 // getFontForCharacter returns the appropriate font family for a character
-// It checks if the main font has the glyph, and falls back to Unicode or CJK fonts if needed
+// It checks if the main font has the glyph, then the script font, then the
+// fallback chain, and finally falls back to Unicode or CJK fonts. Coverage
+// is resolved once per (main font, rune) and cached.
 func (w *Widget) getFontForCharacter(r rune, mainFont string, fontSize int) string {
 	// ASCII characters always use the main font
 	if r < 128 {
 		return mainFont
 	}
 
-	// Check if main font has this glyph
-	cFont := C.CString(mainFont)
-	hasGlyph := C.font_has_glyph(cFont, C.int(fontSize), C.gunichar(r))
-	C.free(unsafe.Pointer(cFont))
+	key := fontCacheKey{mainFont, r}
+	w.mu.Lock()
+	if w.fontCache == nil {
+		w.fontCache = make(map[fontCacheKey]fontChoice)
+	}
+	cache := w.fontCache
+	choice, ok := cache[key]
+	w.mu.Unlock()
+	if !ok {
+		choice = w.resolveFontChoice(r, mainFont, fontSize)
+		// Fonts changed meanwhile got a fresh cache, so this one is dropped
+		w.mu.Lock()
+		cache[key] = choice
+		w.mu.Unlock()
+	}
 
-	if hasGlyph != 0 {
+	if choice.covered {
 		return mainFont
 	}
 
 	// Script-class font (OSC 7005): an app-chosen face for this rune's script
 	// (Hebrew/Arabic/CJK) takes precedence over the generic fallbacks below.
+	// Not cached: apps can change it at any time.
 	if cls := purfecterm.ScriptClass(r); cls != "" {
 		if fam := w.buffer.GetScriptFont(cls); fam != "" {
 			return fam
 		}
 	}
 
-	// Main font doesn't have the glyph - use fallback
+	if choice.fallback != "" {
+		return choice.fallback
+	}
+
+	// Final fallback to main font
+	return mainFont
+}
+
+// resolveFontChoice checks glyph coverage for r: the main font first, then
+// the fallback chain, then the CJK or Unicode fallback
+func (w *Widget) resolveFontChoice(r rune, mainFont string, fontSize int) fontChoice {
+	if fontHasGlyph(mainFont, fontSize, r) {
+		return fontChoice{covered: true}
+	}
+
 	w.mu.Lock()
+	chain := w.fontFallbacks
 	unicodeFont := w.fontFamilyUnicode
 	cjkFont := w.fontFamilyCJK
 	w.mu.Unlock()
 
+	// Fallback chain: first font that actually covers the rune
+	if fam := firstCoveringFont(chain, r, func(family string, r rune) bool {
+		return fontHasGlyph(family, fontSize, r)
+	}); fam != "" {
+		return fontChoice{fallback: fam}
+	}
+
 	// Use CJK font for CJK characters
 	if isCJKCharacter(r) && cjkFont != "" {
-		return cjkFont
+		return fontChoice{fallback: cjkFont}
 	}
 
 	// Use Unicode font for other characters
-	if unicodeFont != "" {
-		return unicodeFont
-	}
-
-	// Final fallback to main font
-	return mainFont
+	return fontChoice{fallback: unicodeFont}
 }
 
 // cellFontFamily resolves the family a cell paints in from its font slot
@@ -870,6 +1194,38 @@ func pangoTextWidthStandalone(text, fontFamily string, fontSize int, bold, itali
 	return int(C.pango_text_width_standalone(cText, cFont, C.int(fontSize), C.int(boldInt), C.int(italicInt)))
 }
 
+// setFontRendering applies antialiasing and hinting to cr for the text
+// drawn on it afterwards
+func setFontRendering(cr *cairo.Context, antialias cairo.Antialias, hintStyle cairo.HintStyle) {
+	crNative := (*C.cairo_t)(unsafe.Pointer(cr.Native()))
+	C.set_font_rendering(crNative, C.int(antialias), C.int(hintStyle))
+}
+
+// fontRendering returns the antialiasing and hinting set on cr
+func fontRendering(cr *cairo.Context) (cairo.Antialias, cairo.HintStyle) {
+	var antialias, hintStyle C.int
+	crNative := (*C.cairo_t)(unsafe.Pointer(cr.Native()))
+	C.get_font_rendering(crNative, &antialias, &hintStyle)
+	return cairo.Antialias(antialias), cairo.HintStyle(hintStyle)
+}
+
+// parseHintStyle maps a hinting name ("none", "slight", "medium", "full")
+// to a cairo hint style; anything else is the desktop default
+func parseHintStyle(s string) cairo.HintStyle {
+	switch strings.ToLower(s) {
+	case "none":
+		return cairo.HINT_STYLE_NONE
+	case "slight":
+		return cairo.HINT_STYLE_SLIGHT
+	case "medium":
+		return cairo.HINT_STYLE_MEDIUM
+	case "full":
+		return cairo.HINT_STYLE_FULL
+	default:
+		return cairo.HINT_STYLE_DEFAULT
+	}
+}
+
 // createCustomGlyphSurface renders a custom glyph to a cached Cairo surface.
 // The surface is rendered at the specified cell size with all palette colors resolved.
 // scaleY is used for double-height mode (1.0 for normal, 2.0 for double-height).
@@ -879,9 +1235,16 @@ func (w *Widget) createCustomGlyphSurface(cell *purfecterm.Cell, glyph *purfecte
 	glyphW := glyph.Width
 	glyphH := glyph.Height
 
-	// Calculate surface dimensions (account for scaleY for double-height)
+	// Calculate surface dimensions (account for scaleY for double-height).
+	// The pixels are allocated at device resolution so glyphs stay sharp on
+	// HiDPI monitors; the device scale lets drawing stay in logical units.
 	surfaceH := int(float64(cellH) * scaleY)
-	surface := cairo.CreateImageSurface(cairo.FORMAT_ARGB32, cellW, surfaceH)
+	scale := w.scaleFactor()
+	surface := cairo.CreateImageSurface(cairo.FORMAT_ARGB32,
+		int(math.Ceil(float64(cellW)*scale)), int(math.Ceil(float64(surfaceH)*scale)))
+	// gotk3 has no binding for cairo_surface_set_device_scale
+	C.cairo_surface_set_device_scale((*C.cairo_surface_t)(unsafe.Pointer(surface.Native())),
+		C.double(scale), C.double(scale))
 	cr := cairo.Create(surface)
 
 	// Calculate pixel size (scale glyph to fill cell)
@@ -941,7 +1304,7 @@ func (w *Widget) createCustomGlyphSurface(cell *purfecterm.Cell, glyph *purfecte
 // renderCustomGlyph renders a custom glyph for a cell at the specified position.
 // Uses the glyph cache for performance - cache hits just blit the pre-rendered surface.
 // Returns true if a custom glyph was rendered, false if normal text rendering should be used.
-func (w *Widget) renderCustomGlyph(cr *cairo.Context, cell *purfecterm.Cell, cellX, cellY, cellW, cellH float64, cellCol int, blinkPhase float64, blinkMode purfecterm.BlinkMode, lineAttr purfecterm.LineAttribute) bool {
+func (w *Widget) renderCustomGlyph(cr *cairo.Context, cell *purfecterm.Cell, cellX, cellY, cellW, cellH float64, cellCol int, blinkPhase float64, blinkAnim purfecterm.BlinkAnimation, blinkMode purfecterm.BlinkMode, lineAttr purfecterm.LineAttribute) bool {
 	glyph := w.buffer.GetGlyph(cell.Char)
 	if glyph == nil {
 		return false
@@ -957,8 +1320,7 @@ func (w *Widget) renderCustomGlyph(cr *cairo.Context, cell *purfecterm.Cell, cel
 	// Calculate wave offset for blink bounce mode
 	yOffset := 0.0
 	if cell.Blink && blinkMode == purfecterm.BlinkModeBounce {
-		wavePhase := blinkPhase + float64(cellCol)*0.5
-		yOffset = math.Sin(wavePhase) * 3.0
+		yOffset = blinkAnim.BounceOffset(cell.BlinkPhase(blinkPhase), cellCol)
 	}
 
 	// Handle double-height lines by clipping and scaling
@@ -1135,14 +1497,16 @@ func (w *Widget) renderSprite(cr *cairo.Context, sprite *purfecterm.Sprite, unit
 
 			// Apply crop rectangle if specified (also relative to logical screen)
 			if cropRect != nil {
-				cropMinX := spriteCoordToPixels(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-				cropMinY := spriteCoordToPixels(cropRect.MinY, unitY, charHeight) + scrollPixelY
-				cropMaxX := spriteCoordToPixels(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-				cropMaxY := spriteCoordToPixels(cropRect.MaxY, unitY, charHeight) + scrollPixelY
-
-				// Skip if tile is completely outside crop rect
-				if pixelX+tileW <= cropMinX || pixelX >= cropMaxX ||
-					pixelY+tileH <= cropMinY || pixelY >= cropMaxY {
+				pixelCrop := purfecterm.CropRectangle{
+					MinX:     spriteCoordToPixels(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+					MinY:     spriteCoordToPixels(cropRect.MinY, unitY, charHeight) + scrollPixelY,
+					MaxX:     spriteCoordToPixels(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+					MaxY:     spriteCoordToPixels(cropRect.MaxY, unitY, charHeight) + scrollPixelY,
+					Inverted: cropRect.Inverted,
+				}
+
+				// Skip if the crop hides the whole tile
+				if pixelCrop.HidesArea(pixelX, pixelY, pixelX+tileW, pixelY+tileH) {
 					continue
 				}
 			}
@@ -1175,19 +1539,30 @@ func (w *Widget) renderSpriteGlyph(cr *cairo.Context, glyph *purfecterm.CustomGl
 	pixelH := tileH / float64(glyphH)
 
 	// Calculate crop bounds in pixels if needed (relative to logical screen)
-	var cropMinX, cropMinY, cropMaxX, cropMaxY float64
+	var pixelCrop purfecterm.CropRectangle
 	hasCrop := cropRect != nil
 	if hasCrop {
-		cropMinX = spriteCoordToPixels(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-		cropMinY = spriteCoordToPixels(cropRect.MinY, unitY, charHeight) + scrollPixelY
-		cropMaxX = spriteCoordToPixels(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-		cropMaxY = spriteCoordToPixels(cropRect.MaxY, unitY, charHeight) + scrollPixelY
+		pixelCrop = purfecterm.CropRectangle{
+			MinX:     spriteCoordToPixels(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+			MinY:     spriteCoordToPixels(cropRect.MinY, unitY, charHeight) + scrollPixelY,
+			MaxX:     spriteCoordToPixels(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+			MaxY:     spriteCoordToPixels(cropRect.MaxY, unitY, charHeight) + scrollPixelY,
+			Inverted: cropRect.Inverted,
+		}
 	}
 
 	// Determine default foreground color for this sprite
 	defaultFg := scheme.Foreground(isDark)
 	defaultBg := scheme.Background(isDark)
 
+	// A translucent sprite is drawn with SetSourceRGBA and without the seam
+	// strips, which would double-blend where they overlap a neighbor
+	alpha := sprite.Opacity()
+	if alpha <= 0 {
+		return
+	}
+	translucent := alpha < 1
+
 	// Render each pixel of the glyph
 	for gy := 0; gy < glyphH; gy++ {
 		for gx := 0; gx < glyphW; gx++ {
@@ -1202,11 +1577,8 @@ func (w *Widget) renderSpriteGlyph(cr *cairo.Context, glyph *purfecterm.CustomGl
 			belowNeighborIdx := glyph.GetPixel(gx, gy+1)
 
 			// Apply crop if specified
-			if hasCrop {
-				if px+pixelW <= cropMinX || px >= cropMaxX ||
-					py+pixelH <= cropMinY || py >= cropMaxY {
-					continue
-				}
+			if hasCrop && pixelCrop.HidesArea(px, py, px+pixelW, py+pixelH) {
+				continue
 			}
 
 			// Resolve color using sprite's FGP
@@ -1216,14 +1588,25 @@ func (w *Widget) renderSpriteGlyph(cr *cairo.Context, glyph *purfecterm.CustomGl
 			}
 
 			// Set color for drawing
-			cr.SetSourceRGB(
-				float64(color.R)/255.0,
-				float64(color.G)/255.0,
-				float64(color.B)/255.0)
+			if translucent {
+				cr.SetSourceRGBA(
+					float64(color.R)/255.0,
+					float64(color.G)/255.0,
+					float64(color.B)/255.0,
+					alpha)
+			} else {
+				cr.SetSourceRGB(
+					float64(color.R)/255.0,
+					float64(color.G)/255.0,
+					float64(color.B)/255.0)
+			}
 
 			// Draw main pixel
 			cr.Rectangle(px, py, pixelW, pixelH)
 			cr.Fill()
+			if translucent {
+				continue
+			}
 
 			// Draw seam extensions as separate strips (1 screen pixel each)
 			// to prevent hairline gaps without creating corner artifacts
@@ -1303,21 +1686,87 @@ func (w *Widget) onCornerDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 	w.mu.Lock()
 	scheme := w.scheme
 	w.mu.Unlock()
+	scheme = w.buffer.EffectiveScheme(scheme)
 	isDark := w.buffer.IsDarkTheme()
 	bg := scheme.Background(isDark)
 
 	// Fill with terminal background color
 	alloc := da.GetAllocation()
-	cr.SetSourceRGB(
-		float64(bg.R)/255.0,
-		float64(bg.G)/255.0,
-		float64(bg.B)/255.0)
+	setBackgroundSource(cr, bg, w.buffer.IsTransparentBackground())
 	cr.Rectangle(0, 0, float64(alloc.GetWidth()), float64(alloc.GetHeight()))
 	cr.Fill()
 
 	return true
 }
 
+// SetScrollbarMode selects how the vertical scroll position is shown (see
+// ScrollbarMode). The horizontal scrollbar is unaffected.
+func (w *Widget) SetScrollbarMode(mode ScrollbarMode) {
+	w.mu.Lock()
+	w.scrollbarMode = mode
+	w.mu.Unlock()
+	w.updateScrollbar()
+	w.drawingArea.QueueDraw()
+}
+
+// GetScrollbarMode returns how the vertical scroll position is shown
+func (w *Widget) GetScrollbarMode() ScrollbarMode {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.scrollbarMode
+}
+
+// drawScrollHint draws the ScrollbarOverlayHint indicator along the right
+// edge of a width x height drawing area. It needs no timer of its own: the
+// blink animation timer redraws every blinkTickMs, and each draw takes the
+// opacity from the time since the last scroll, so the hint fades out over
+// those redraws and simply stops being drawn once transparent.
+func (w *Widget) drawScrollHint(cr *cairo.Context, width, height float64) {
+	w.mu.Lock()
+	mode := w.scrollbarMode
+	scrolledAt := w.hintScrolledAt
+	w.mu.Unlock()
+	if mode != ScrollbarOverlayHint || scrolledAt.IsZero() {
+		return
+	}
+	alpha := scrollHintAlpha(time.Since(scrolledAt))
+	if alpha <= 0 {
+		return
+	}
+	_, rows := w.buffer.GetSize()
+	top, length, ok := scrollHintExtent(w.buffer.GetScrollOffset(), w.buffer.GetMaxScrollOffset(), rows, height)
+	if !ok {
+		return
+	}
+
+	// Same gray as the scrollbar slider, as a rounded bar
+	x := width - scrollHintInset - scrollHintWidth
+	r := scrollHintWidth / 2
+	cr.SetSourceRGBA(0.5, 0.5, 0.5, 0.6*alpha)
+	cr.NewPath()
+	cr.Arc(x+r, top+r, r, math.Pi, 0)
+	cr.Arc(x+r, top+length-r, r, 0, math.Pi)
+	cr.ClosePath()
+	cr.Fill()
+}
+
+// setBackgroundSource prepares cr to paint the terminal background: the
+// scheme color bg, or with a transparent background (see
+// Buffer.SetTransparentBackground) fully clear pixels, so whatever is
+// behind the widget shows through. It may change the operator; callers
+// that draw more afterwards wrap it in Save/Restore.
+func setBackgroundSource(cr *cairo.Context, bg purfecterm.Color, transparent bool) {
+	if transparent {
+		cr.SetOperator(cairo.OPERATOR_SOURCE)
+		cr.SetSourceRGBA(0, 0, 0, 0)
+		return
+	}
+	cr.SetSourceRGB(
+		float64(bg.R)/255.0,
+		float64(bg.G)/255.0,
+		float64(bg.B)/255.0)
+}
+
 // onCornerButtonPress handles button press on the corner widget to initiate window resize
 func (w *Widget) onCornerButtonPress(da *gtk.DrawingArea, event *gdk.Event) bool {
 	buttonEvent := gdk.EventButtonNewFromEvent(event)
@@ -1362,6 +1811,69 @@ func (w *Widget) onCornerButtonPress(da *gtk.DrawingArea, event *gdk.Event) bool
 	return false
 }
 
+// renderBoxGlyph draws a box-drawing or block element character with Cairo
+// rectangles sized exactly to the cell, so borders and shaded blocks meet
+// seamlessly. Double-height halves draw the matching half of a 2x-tall glyph.
+// Returns false if the character should be rendered from the font instead.
+func renderBoxGlyph(cr *cairo.Context, ch rune, cellX, cellY, cellW, cellH float64, lineAttr purfecterm.LineAttribute, fg purfecterm.Color) bool {
+	originX, originY := math.Round(cellX), math.Round(cellY)
+	glyphH := cellH
+	switch lineAttr {
+	case purfecterm.LineAttrDoubleTop:
+		glyphH = cellH * 2
+	case purfecterm.LineAttrDoubleBottom:
+		glyphH = cellH * 2
+		originY -= cellH
+	}
+
+	rects, ok := purfecterm.BoxGlyphRects(ch, cellW, glyphH)
+	if !ok {
+		return false
+	}
+
+	cr.Save()
+	cr.Rectangle(cellX, cellY, cellW, cellH)
+	cr.Clip()
+	for _, r := range rects {
+		cr.SetSourceRGBA(
+			float64(fg.R)/255.0,
+			float64(fg.G)/255.0,
+			float64(fg.B)/255.0,
+			r.Alpha)
+		cr.Rectangle(originX+r.X, originY+r.Y, r.W, r.H)
+		cr.Fill()
+	}
+	cr.Restore()
+	return true
+}
+
+// SetDrawBoxGlyphs enables drawing box-drawing and block element characters
+// (U+2500-U+259F) directly as rectangles instead of through the font, which
+// avoids antialiasing gaps at cell boundaries. Dashed lines, arcs and
+// diagonals still come from the font. Disabled by default.
+func (w *Widget) SetDrawBoxGlyphs(enabled bool) {
+	w.mu.Lock()
+	w.drawBoxGlyphs = enabled
+	w.mu.Unlock()
+	w.drawingArea.QueueDraw()
+}
+
+// SetFontRendering chooses how text is rasterized. antialias false gives
+// hard-edged, pixel-exact glyphs (for bitmap fonts and retro looks); true
+// keeps the desktop's antialiasing. hinting is "none", "slight", "medium"
+// or "full"; "" (or anything else) keeps the desktop default. The options
+// are set on the cairo context at the start of each onDraw.
+func (w *Widget) SetFontRendering(antialias bool, hinting string) {
+	w.mu.Lock()
+	w.fontAntialias = cairo.ANTIALIAS_DEFAULT
+	if !antialias {
+		w.fontAntialias = cairo.ANTIALIAS_NONE
+	}
+	w.fontHintStyle = parseHintStyle(hinting)
+	w.mu.Unlock()
+	w.drawingArea.QueueDraw()
+}
+
 // SetMouseReportingEnabled enables or disables xterm mouse event reporting.
 // When enabled, a toggle menu item is added to the context menu.
 func (w *Widget) SetMouseReportingEnabled(enabled bool) {
@@ -1411,6 +1923,20 @@ func (w *Widget) FeedString(data string) {
 	w.parser.ParseString(data)
 }
 
+// responseWriter passes the parser's query replies to the input callback,
+// so they reach the hosted program like typed input
+type responseWriter struct{ w *Widget }
+
+func (r responseWriter) Write(data []byte) (int, error) {
+	r.w.mu.Lock()
+	onInput := r.w.onInput
+	r.w.mu.Unlock()
+	if onInput != nil {
+		onInput(data)
+	}
+	return len(data), nil
+}
+
 // Clear clears the terminal screen
 func (w *Widget) Clear() {
 	w.buffer.ClearScreen()
@@ -1566,11 +2092,7 @@ func (w *Widget) renderScreenSplits(cr *cairo.Context, splits []*purfecterm.Scre
 			cr.Save()
 			cr.Rectangle(0, startPixelY, float64(cols*charWidth+terminalLeftPadding), endPixelY-startPixelY)
 			cr.Clip()
-			splitBg := scheme.Background(isDark)
-			cr.SetSourceRGB(
-				float64(splitBg.R)/255.0,
-				float64(splitBg.G)/255.0,
-				float64(splitBg.B)/255.0)
+			setBackgroundSource(cr, scheme.Background(isDark), w.buffer.IsTransparentBackground())
 			cr.Rectangle(0, startPixelY, float64(cols*charWidth+terminalLeftPadding), endPixelY-startPixelY)
 			cr.Fill()
 			cr.Restore()
@@ -1604,7 +2126,7 @@ func (w *Widget) renderScreenSplits(cr *cairo.Context, splits []*purfecterm.Scre
 		cr.Clip()
 
 		// Get line attribute for this buffer row
-		lineAttr := w.buffer.GetLineAttributeForSplit(rowInSplit, currentSplit.BufferRow)
+		lineAttr := w.buffer.GetLineAttributeForScrolledSplit(rowInSplit, currentSplit.BufferRow, currentSplit.ScrollOffset)
 
 		effectiveCols := cols
 		if lineAttr != purfecterm.LineAttrNormal {
@@ -1612,7 +2134,7 @@ func (w *Widget) renderScreenSplits(cr *cairo.Context, splits []*purfecterm.Scre
 		}
 
 		// Get the content length for this row (excluding content before BufferCol)
-		contentLen := w.buffer.GetLineLengthForSplit(rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol)
+		contentLen := w.buffer.GetLineLengthForScrolledSplit(rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset)
 
 		// Determine where to stop rendering:
 		// - At screen edge (effectiveCols)
@@ -1641,7 +2163,7 @@ func (w *Widget) renderScreenSplits(cr *cairo.Context, splits []*purfecterm.Scre
 		// will clip the left portion of the first cell when LeftFineScroll > 0
 		// horizOffset accounts for the global horizontal scroll position
 		for screenCol := 0; screenCol < maxRenderCol; screenCol++ {
-			cell := w.buffer.GetCellForSplit(screenCol+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol)
+			cell := w.buffer.GetCellForScrolledSplit(screenCol+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset)
 
 			// Calculate cell position (shifted left by fine scroll)
 			var cellX, cellW float64
@@ -1665,11 +2187,10 @@ func (w *Widget) renderScreenSplits(cr *cairo.Context, splits []*purfecterm.Scre
 				continue
 			}
 
-			fg := scheme.ResolveColor(cell.Foreground, true, isDark)
-			bg := scheme.ResolveColor(cell.Background, false, isDark)
+			fg, bg := scheme.ResolveCellColors(&cell, isDark)
 
-			// Draw cell background if different from terminal background
-			if bg != scheme.Background(isDark) {
+			// Draw cell background unless the cleared split already shows it
+			if !w.buffer.SkipBackgroundFill(&cell, bg, scheme.Background(isDark)) {
 				cr.SetSourceRGB(
 					float64(bg.R)/255.0,
 					float64(bg.G)/255.0,
@@ -1679,14 +2200,14 @@ func (w *Widget) renderScreenSplits(cr *cairo.Context, splits []*purfecterm.Scre
 			}
 
 			// Draw character
-			if cell.Char != ' ' && cell.Char != 0 {
+			if cell.Char != ' ' && cell.Char != 0 && !cell.Conceal {
 				// Arabic contextual joining from the neighbor cells (visual order).
 				var leftCh, rightCh rune
 				if screenCol > 0 {
-					leftCh = w.buffer.GetCellForSplit(screenCol-1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol).Char
+					leftCh = w.buffer.GetCellForScrolledSplit(screenCol-1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset).Char
 				}
 				if screenCol+1 < maxRenderCol {
-					rightCh = w.buffer.GetCellForSplit(screenCol+1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol).Char
+					rightCh = w.buffer.GetCellForScrolledSplit(screenCol+1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset).Char
 				}
 				shapedChar, suppress := purfecterm.ShapeArabicCellVisual(leftCh, cell.Char, rightCh)
 				if suppress {
@@ -1730,7 +2251,15 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 	baseCharWidth := w.charWidth
 	baseCharHeight := w.charHeight
 	blinkPhase := w.blinkPhase
+	blinkAnim := w.blinkAnim
+	drawBoxGlyphs := w.drawBoxGlyphs
+	fontAntialias, fontHintStyle := w.fontAntialias, w.fontHintStyle
 	w.mu.Unlock()
+	scheme = w.buffer.EffectiveScheme(scheme)
+
+	// Font options go on the context before any text is laid out; every
+	// Pango layout below is created from cr and inherits them
+	setFontRendering(cr, fontAntialias, fontHintStyle)
 
 	// Get current theme mode (dark/light) from buffer's DECSCNM state
 	isDark := w.buffer.IsDarkTheme()
@@ -1769,12 +2298,11 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 	// This ensures any extra space at edges is filled with terminal background
 	alloc := da.GetAllocation()
 	schemeBg := scheme.Background(isDark)
-	cr.SetSourceRGB(
-		float64(schemeBg.R)/255.0,
-		float64(schemeBg.G)/255.0,
-		float64(schemeBg.B)/255.0)
+	cr.Save()
+	setBackgroundSource(cr, schemeBg, w.buffer.IsTransparentBackground())
 	cr.Rectangle(0, 0, float64(alloc.GetWidth()), float64(alloc.GetHeight()))
 	cr.Fill()
+	cr.Restore()
 
 	// Apply screen crop clipping if set (crop values are in sprite coordinate units)
 	widthCrop, heightCrop := w.buffer.GetScreenCrop()
@@ -1844,6 +2372,11 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 			// GetVisibleCell takes screen position and applies horizOffset internally
 			cell := w.buffer.GetVisibleCell(x, y)
 
+			// A wide-char continuation cell is covered by its glyph
+			if cell.Continuation {
+				continue
+			}
+
 			// Calculate this cell's visual width
 			// Standard-mode cells carry real widths too, so key on CellWidth
 			// regardless of the FlexWidth flag.
@@ -1853,43 +2386,43 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 			}
 
 			// Determine colors
-			fg := scheme.ResolveColor(cell.Foreground, true, isDark)
-			bg := scheme.ResolveColor(cell.Background, false, isDark)
-
-			// Handle blink attribute based on mode
-			blinkVisible := true // For traditional blink mode
-			if cell.Blink {
+			fg, bg := scheme.ResolveCellColors(&cell, isDark)
+
+			// Handle blink attribute based on mode. Traditional on/off blink
+			// hides the text for part of the cycle; BlinkModeBounce is handled
+			// later in character drawing; BlinkModeNone draws the text as if it
+			// did not blink
+			blinkVisible := blinkTextVisible(&cell, scheme.BlinkMode, blinkAnim, blinkPhase)
+			if cell.Blink && scheme.BlinkMode == purfecterm.BlinkModeBright {
+				// Interpret blink as bright background (VGA style)
+				// Find if bg matches a dark color (0-7) and use bright version (8-15)
 				palette := scheme.Palette(isDark)
-				switch scheme.BlinkMode {
-				case purfecterm.BlinkModeBright:
-					// Interpret blink as bright background (VGA style)
-					// Find if bg matches a dark color (0-7) and use bright version (8-15)
-					for i := 0; i < 8; i++ {
-						if len(palette) > i+8 &&
-							bg.R == palette[i].R &&
-							bg.G == palette[i].G &&
-							bg.B == palette[i].B {
-							bg = palette[i+8]
-							break
-						}
+				for i := 0; i < 8; i++ {
+					if len(palette) > i+8 &&
+						bg.R == palette[i].R &&
+						bg.G == palette[i].G &&
+						bg.B == palette[i].B {
+						bg = palette[i+8]
+						break
 					}
-				case purfecterm.BlinkModeBlink:
-					// Traditional on/off blink - visible when phase is in first half
-					blinkVisible = blinkPhase < 3.14159
-					// BlinkModeBounce is handled later in character drawing
 				}
 			}
 
+			// Search matches are shaded first so a selection stays visible on top
+			if w.buffer.IsCellInSearchHighlight(logicalX, y) {
+				bg = scheme.SearchHighlight
+			}
+
 			// Handle selection highlighting (use logicalX for buffer position)
 			if w.buffer.IsInSelection(logicalX, y) {
-				bg = scheme.Selection
+				bg = scheme.SelectionBackground(bg)
 			}
 
 			// Handle cursor - only swap colors for solid block cursor when focused
 			isCursor := cursorVisible && x == cursorVisibleX && y == cursorVisibleY && w.cursorBlinkOn
 			if isCursor && w.hasFocus && cursorShape == 0 {
-				// Swap colors for solid block cursor when focused
-				fg, bg = bg, fg
+				// Swap colors (or tint the background) for solid block cursor when focused
+				fg, bg = scheme.BlockCursorColors(fg, bg)
 			}
 
 			// Calculate cell position and size based on line attributes and flex width
@@ -1919,8 +2452,8 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 			_ = x // x is still useful for wave animation phase calculation
 			visibleAccumulatedWidth += cellVisualWidth
 
-			// Draw cell background if different from terminal background
-			if bg != scheme.Background(isDark) {
+			// Draw cell background unless the cleared screen already shows it
+			if !w.buffer.SkipBackgroundFill(&cell, bg, schemeBg) {
 				cr.SetSourceRGB(
 					float64(bg.R)/255.0,
 					float64(bg.G)/255.0,
@@ -1930,13 +2463,18 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 			}
 
 			// Draw character (skip if traditional blink mode and currently invisible)
-			if cell.Char != ' ' && cell.Char != 0 && blinkVisible {
+			if cell.Char != ' ' && cell.Char != 0 && blinkVisible && !cell.Conceal {
 				// Check for custom glyph first
-				if w.renderCustomGlyph(cr, &cell, cellX, cellY, cellW, cellH, x, blinkPhase, scheme.BlinkMode, lineAttr) {
+				if w.renderCustomGlyph(cr, &cell, cellX, cellY, cellW, cellH, x, blinkPhase, blinkAnim, scheme.BlinkMode, lineAttr) {
 					// Custom glyph was rendered, skip normal text rendering
 					goto afterCharRender
 				}
 
+				// Box-drawing and block elements as crisp rectangles (opt-in)
+				if drawBoxGlyphs && renderBoxGlyph(cr, cell.Char, cellX, cellY, cellW, cellH, lineAttr, fg) {
+					goto afterCharRender
+				}
+
 				// Arabic contextual joining from the neighbor cells (visual order:
 				// left = logically next, right = logically previous).
 				{
@@ -1975,10 +2513,9 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 				// creating a "wave" effect where characters bob up and down in sequence
 				yOffset := 0.0
 				if cell.Blink && scheme.BlinkMode == purfecterm.BlinkModeBounce {
-					// Wave parameters: each character is phase-shifted by 0.5 radians from its neighbor
-					// Amplitude is about 3 pixels up and down
-					wavePhase := blinkPhase + float64(x)*0.5
-					yOffset = math.Sin(wavePhase) * 3.0
+					// Wave parameters (blinkAnim): each character is phase-shifted
+					// from its neighbor by PhaseShift and bobs Amplitude pixels
+					yOffset = blinkAnim.BounceOffset(cell.BlinkPhase(blinkPhase), x)
 				}
 
 				switch lineAttr {
@@ -2093,8 +2630,10 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 			}
 		afterCharRender:
 
-			// Draw underline if needed (with style support)
-			if cell.UnderlineStyle != purfecterm.UnderlineNone {
+			// Draw underline if needed (with style support). The top half of
+			// a double-height line has no bottom edge of its own. Every style
+			// uses the cell's underline color (SGR 58) when one is set.
+			if cell.UnderlineStyle != purfecterm.UnderlineNone && lineAttr != purfecterm.LineAttrDoubleTop {
 				// Use underline color if set, otherwise use foreground color
 				ulColor := fg
 				if cell.HasUnderlineColor {
@@ -2187,6 +2726,21 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 				cr.Fill()
 			}
 
+			// Draw overline if needed (the bottom half of a double-height
+			// line has no top edge of its own)
+			if cell.Overline && lineAttr != purfecterm.LineAttrDoubleBottom {
+				cr.SetSourceRGB(
+					float64(fg.R)/255.0,
+					float64(fg.G)/255.0,
+					float64(fg.B)/255.0)
+				overH := 1.0
+				if lineAttr == purfecterm.LineAttrDoubleTop {
+					overH = 2.0
+				}
+				cr.Rectangle(cellX, cellY, cellW, overH)
+				cr.Fill()
+			}
+
 			// Draw cursor based on shape (0=block, 1=underline, 2=bar)
 			if isCursor {
 				cr.SetSourceRGB(
@@ -2284,11 +2838,12 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 		w.buffer.SetSplitContentWidth(0)
 	}
 
-	// Draw yellow dashed line between scrollback and logical screen
+	// Draw dashed line between scrollback and logical screen
 	boundaryRow := w.buffer.GetScrollbackBoundaryVisibleRow()
-	if boundaryRow > 0 {
+	if boundaryRow > 0 && w.buffer.GetShowScrollbackBoundary() {
 		lineY := float64(boundaryRow * charHeight)
-		cr.SetSourceRGB(1.0, 0.78, 0.0) // Yellow (255, 200, 0)
+		lineColor := scheme.ScrollbackBoundaryColor(isDark)
+		cr.SetSourceRGB(float64(lineColor.R)/255.0, float64(lineColor.G)/255.0, float64(lineColor.B)/255.0)
 		cr.SetLineWidth(1.0)
 		cr.SetDash([]float64{4, 4}, 0)
 		cr.MoveTo(0, lineY)
@@ -2302,6 +2857,8 @@ func (w *Widget) onDraw(da *gtk.DrawingArea, cr *cairo.Context) bool {
 		cr.Restore()
 	}
 
+	w.drawScrollHint(cr, float64(alloc.GetWidth()), float64(alloc.GetHeight()))
+
 	// Report whether cursor's LINE was rendered for auto-scroll logic
 	// We track the line, not the cursor itself - the cursor may be horizontally
 	// off-screen or invisible, but if its line is visible, auto-scroll should stop.
@@ -2369,7 +2926,13 @@ func (w *Widget) screenToCell(screenX, screenY float64) (cellX, cellY int) {
 	// accumulatedPixels tracks the right edge of each cell
 	accumulatedPixels := 0.0
 	for col := horizOffset; col < cols+horizOffset; col++ {
-		cell := w.buffer.GetVisibleCell(col, cellY)
+		// GetVisibleCell takes a screen column and adds horizOffset itself
+		cell := w.buffer.GetVisibleCell(col-horizOffset, cellY)
+
+		// A wide-char continuation cell is covered by its glyph
+		if cell.Continuation {
+			continue
+		}
 
 		// Calculate this cell's visual width
 		// Standard-mode cells carry real widths too, so key on CellWidth
@@ -2416,7 +2979,6 @@ func (w *Widget) sendMouseEvent(button, cellX, cellY int, press bool) bool {
 		return false
 	}
 
-	encodingMode := w.buffer.GetMouseEncodingMode()
 	// screenToCell yields a LOGICAL cell index. Under the standard contract
 	// the hosted application addresses in VISUAL columns, so translate; under
 	// flex mode (?7027h) it addresses logical cells, so report as-is.
@@ -2425,7 +2987,7 @@ func (w *Widget) sendMouseEvent(button, cellX, cellY int, press bool) bool {
 		reportX = w.buffer.LogicalToVisualCol(cellY, cellX)
 	}
 	// Convert to 1-based coordinates
-	data := purfecterm.EncodeMouseEvent(button, reportX+1, cellY+1, press, encodingMode)
+	data := w.buffer.EncodeMouseEvent(button, reportX+1, cellY+1, press)
 	if data != nil {
 		onInput(data)
 		return true
@@ -2804,54 +3366,40 @@ func (w *Widget) onScroll(da *gtk.DrawingArea, ev *gdk.Event) bool {
 	}
 
 	// Shift+scroll = horizontal scrolling
-	maxOffset := w.buffer.GetMaxScrollOffset()
+	step := w.buffer.GetScrollLineStep()
 
 	switch dir {
 	case gdk.SCROLL_UP:
 		if hasShift {
 			// Horizontal scroll left
 			horizOffset := w.buffer.GetHorizOffset()
-			horizOffset -= 3
+			horizOffset -= step
 			if horizOffset < 0 {
 				horizOffset = 0
 			}
 			w.buffer.SetHorizOffset(horizOffset)
 		} else {
 			// Vertical scroll up
-			offset := w.buffer.GetScrollOffset()
-			offset += 3
-			if offset > maxOffset {
-				offset = maxOffset
-			}
-			w.buffer.SetScrollOffset(offset)
-			w.buffer.NotifyManualVertScroll() // User initiated scroll
+			w.buffer.ScrollViewWheel(1)
 		}
 	case gdk.SCROLL_DOWN:
 		if hasShift {
 			// Horizontal scroll right
 			horizOffset := w.buffer.GetHorizOffset()
 			maxHoriz := w.buffer.GetMaxHorizOffset()
-			horizOffset += 3
+			horizOffset += step
 			if horizOffset > maxHoriz {
 				horizOffset = maxHoriz
 			}
 			w.buffer.SetHorizOffset(horizOffset)
 		} else {
-			// Vertical scroll down
-			offset := w.buffer.GetScrollOffset()
-			offset -= 3
-			if offset < 0 {
-				offset = 0
-			}
-			w.buffer.SetScrollOffset(offset)
-			// Snap to 0 if in the magnetic zone (creates sticky boundary effect)
-			w.buffer.NormalizeScrollOffset()
-			w.buffer.NotifyManualVertScroll() // User initiated scroll
+			// Vertical scroll down (snaps to 0 in the magnetic zone)
+			w.buffer.ScrollViewWheel(-1)
 		}
 	case gdk.SCROLL_LEFT:
 		// Horizontal scroll left
 		horizOffset := w.buffer.GetHorizOffset()
-		horizOffset -= 3
+		horizOffset -= step
 		if horizOffset < 0 {
 			horizOffset = 0
 		}
@@ -2860,7 +3408,7 @@ func (w *Widget) onScroll(da *gtk.DrawingArea, ev *gdk.Event) bool {
 		// Horizontal scroll right
 		horizOffset := w.buffer.GetHorizOffset()
 		maxHoriz := w.buffer.GetMaxHorizOffset()
-		horizOffset += 3
+		horizOffset += step
 		if horizOffset > maxHoriz {
 			horizOffset = maxHoriz
 		}
@@ -2878,6 +3426,7 @@ func (w *Widget) onKeyPress(da *gtk.DrawingArea, ev *gdk.Event) bool {
 
 	w.mu.Lock()
 	onInput := w.onInput
+	ctrlCCopies := w.ctrlCCopiesSelection
 	w.mu.Unlock()
 
 	// Extract modifier states (cast ModifierType to uint for bitwise ops)
@@ -2915,21 +3464,19 @@ func (w *Widget) onKeyPress(da *gtk.DrawingArea, ev *gdk.Event) bool {
 		// Plain Tab or Tab with Alt/Meta/Super: continue to send to terminal
 	}
 
-	// Handle clipboard copy (Ctrl+C with selection only)
+	// Handle clipboard copy (Ctrl+C with selection only, see SetCtrlCCopiesSelection)
 	// Note: Ctrl+V paste is NOT handled here - use PasteClipboard() via context menu
 	// Note: Ctrl+A is NOT handled here - it passes through to the terminal
 	// for programs that use it (e.g., readline beginning-of-line)
 	if hasCtrl && !hasAlt && !hasMeta {
 		switch keyval {
 		case gdk.KEY_c, gdk.KEY_C:
-			if w.buffer.HasSelection() {
-				text := w.buffer.GetSelectedText()
-				if w.clipboard != nil {
-					w.clipboard.SetText(text)
-				}
+			if ctrlCCopies && w.buffer.HasSelection() {
+				w.CopySelection()
 				return true
 			}
-			// Ctrl+C without selection falls through to send interrupt
+			// Ctrl+C without selection (or with copying turned off) falls
+			// through to send interrupt
 		}
 	}
 
@@ -2962,7 +3509,7 @@ func (w *Widget) onKeyPress(da *gtk.DrawingArea, ev *gdk.Event) bool {
 		if hasModifiers {
 			data = modifiedSpecialKey(mod, 13, 0) // CSI 13 ; mod u (kitty protocol)
 		} else {
-			data = []byte{'\r'}
+			data = w.buffer.EnterKeySequence() // CR, or CR LF under LNM
 		}
 	case gdk.KEY_BackSpace:
 		if hasCtrl {
@@ -3403,15 +3950,19 @@ func (w *Widget) onConfigure(da *gtk.DrawingArea, ev *gdk.Event) bool {
 	w.updateFontMetrics()
 
 	// Apply screen scaling to character dimensions
-	horizScale := w.buffer.GetHorizontalScale()
-	vertScale := w.buffer.GetVerticalScale()
-	scaledCharWidth := int(float64(w.charWidth) * horizScale)
-	scaledCharHeight := int(float64(w.charHeight) * vertScale)
-	if scaledCharWidth < 1 {
-		scaledCharWidth = 1
-	}
-	if scaledCharHeight < 1 {
-		scaledCharHeight = 1
+	scaledCharWidth, scaledCharHeight := w.scaledCellSize()
+
+	// A forced grid keeps its size; only the pixel size it asks for follows
+	// font and scale changes
+	w.mu.Lock()
+	gridCols, gridRows := w.gridCols, w.gridRows
+	w.mu.Unlock()
+	if gridCols > 0 {
+		width, height := gridPixelSize(gridCols, gridRows, scaledCharWidth, scaledCharHeight)
+		if rw, rh := da.GetSizeRequest(); rw != width || rh != height {
+			da.SetSizeRequest(width, height)
+		}
+		return false
 	}
 
 	// Recalculate terminal size based on widget size (minus left padding)
@@ -3450,15 +4001,28 @@ func (w *Widget) onFocusIn(da *gtk.DrawingArea, ev *gdk.Event) bool {
 	w.hasFocus = true
 	w.cursorBlinkOn = true // Reset blink so cursor is immediately visible
 	w.drawingArea.QueueDraw()
+	w.reportFocus(true)
 	return false
 }
 
 func (w *Widget) onFocusOut(da *gtk.DrawingArea, ev *gdk.Event) bool {
 	w.hasFocus = false
 	w.drawingArea.QueueDraw()
+	w.reportFocus(false)
 	return false
 }
 
+// reportFocus sends a focus in/out report to the PTY if the application
+// enabled focus reporting (DEC 1004)
+func (w *Widget) reportFocus(focused bool) {
+	w.mu.Lock()
+	onInput := w.onInput
+	w.mu.Unlock()
+	if data := w.buffer.FocusEvent(focused); data != nil && onInput != nil {
+		onInput(data)
+	}
+}
+
 func (w *Widget) onScrollbarChanged(sb *gtk.Scrollbar) {
 	adj := sb.GetAdjustment()
 	val := int(adj.GetValue())
@@ -3489,6 +4053,19 @@ func (w *Widget) updateScrollbar() {
 	offset := w.buffer.GetScrollOffset()
 	_, rows := w.buffer.GetSize()
 
+	w.mu.Lock()
+	mode := w.scrollbarMode
+	if offset != w.hintOffset {
+		w.hintOffset = offset
+		w.hintScrolledAt = time.Now()
+	}
+	w.mu.Unlock()
+	if mode == ScrollbarAlways || (mode == ScrollbarAuto && maxOffset > 0) {
+		w.scrollbar.Show()
+	} else {
+		w.scrollbar.Hide()
+	}
+
 	adj := w.scrollbar.GetAdjustment()
 	adj.SetLower(0)
 	adj.SetUpper(float64(maxOffset + rows))
@@ -3541,12 +4118,84 @@ func (w *Widget) updateHorizScrollbar() {
 	}
 }
 
-// Resize resizes the terminal to the specified dimensions
+// Resize resizes the terminal to the specified dimensions. It ends any
+// grid forced by SetGridSize, so the widget no longer asks for that
+// grid's size and later widget resizes fit the grid again.
 func (w *Widget) Resize(cols, rows int) {
+	w.mu.Lock()
+	forced := w.gridCols > 0
+	w.gridCols, w.gridRows = 0, 0
+	w.mu.Unlock()
+	if forced {
+		w.drawingArea.SetSizeRequest(minDrawingAreaWidth, minDrawingAreaHeight)
+	}
 	w.buffer.Resize(cols, rows)
 	w.updateScrollbar()
 }
 
+// SetGridSize forces the terminal to exactly cols x rows (e.g. to match a
+// recording) whatever the widget's pixel size, and asks for a widget size
+// that fits that grid. The grid then stays put as the widget is resized,
+// until ClearGridSize (or a manual Resize) returns to fitting the grid to
+// the widget.
+func (w *Widget) SetGridSize(cols, rows int) {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	w.mu.Lock()
+	w.gridCols, w.gridRows = cols, rows
+	w.mu.Unlock()
+
+	oldCols, oldRows := w.buffer.GetSize()
+	w.buffer.Resize(cols, rows)
+	if w.termCaps != nil {
+		w.termCaps.SetSize(cols, rows)
+	}
+	if (cols != oldCols || rows != oldRows) && w.onResize != nil {
+		w.onResize(cols, rows)
+	}
+
+	w.updateFontMetrics()
+	cw, ch := w.scaledCellSize()
+	w.drawingArea.SetSizeRequest(gridPixelSize(cols, rows, cw, ch))
+	w.updateScrollbar()
+	w.updateHorizScrollbar()
+	w.drawingArea.QueueDraw()
+}
+
+// ClearGridSize undoes SetGridSize: the grid fits the widget again
+func (w *Widget) ClearGridSize() {
+	w.mu.Lock()
+	forced := w.gridCols > 0
+	w.gridCols, w.gridRows = 0, 0
+	w.mu.Unlock()
+	if !forced {
+		return
+	}
+	w.drawingArea.SetSizeRequest(minDrawingAreaWidth, minDrawingAreaHeight)
+	w.onConfigure(w.drawingArea, nil)
+	w.updateScrollbar()
+	w.updateHorizScrollbar()
+	w.drawingArea.QueueDraw()
+}
+
+// scaledCellSize returns the cell size in pixels with the screen's
+// horizontal and vertical scaling (132/40-column modes, line density)
+func (w *Widget) scaledCellSize() (width, height int) {
+	width = int(float64(w.charWidth) * w.buffer.GetHorizontalScale())
+	height = int(float64(w.charHeight) * w.buffer.GetVerticalScale())
+	return max(width, 1), max(height, 1)
+}
+
+// gridPixelSize returns the drawing area size that holds exactly cols x
+// rows cells of charWidth x charHeight, the left padding included
+func gridPixelSize(cols, rows, charWidth, charHeight int) (width, height int) {
+	return cols*charWidth + terminalLeftPadding, rows * charHeight
+}
+
 // GetSize returns the current terminal size in characters
 func (w *Widget) GetSize() (cols, rows int) {
 	return w.buffer.GetSize()
@@ -3560,49 +4209,69 @@ func (w *Widget) GetTerminalCapabilities() *purfecterm.TerminalCapabilities {
 	return w.termCaps
 }
 
+// Capabilities is an alias for GetTerminalCapabilities.
+func (w *Widget) Capabilities() *purfecterm.TerminalCapabilities {
+	return w.termCaps
+}
+
 // GetSelectedText returns currently selected text
 func (w *Widget) GetSelectedText() string {
 	return w.buffer.GetSelectedText()
 }
 
-// CopySelection copies selected text to clipboard
+// CopySelection copies selected text to clipboard, with a trailing newline
+// on multi-line selections if SetCopyTrailingNewline is on
 func (w *Widget) CopySelection() {
 	if w.clipboard != nil && w.buffer.HasSelection() {
-		text := w.buffer.GetSelectedText()
-		w.clipboard.SetText(text)
+		w.clipboard.SetText(w.selectionCopyText())
+	}
+}
+
+// selectionCopyText returns the selected text as CopySelection copies it
+func (w *Widget) selectionCopyText() string {
+	w.mu.Lock()
+	trailingNewline := w.copyTrailingNewline
+	w.mu.Unlock()
+	return copyText(w.buffer.GetSelectedText(), trailingNewline)
+}
+
+// copyText adds the trailing newline to a multi-line selection's text when
+// asked to; a selection within one line is copied as is
+func copyText(text string, trailingNewline bool) string {
+	if trailingNewline && strings.Contains(text, "\n") && !strings.HasSuffix(text, "\n") {
+		return text + "\n"
 	}
+	return text
+}
+
+// SetCopyTrailingNewline chooses whether copying a selection that spans
+// more than one line ends it with a newline (off by default, matching
+// GetSelectedText)
+func (w *Widget) SetCopyTrailingNewline(enabled bool) {
+	w.mu.Lock()
+	w.copyTrailingNewline = enabled
+	w.mu.Unlock()
+}
+
+// SetCtrlCCopiesSelection chooses what Ctrl+C does while text is selected:
+// copy it (the default) or send ^C to the program as it does without a
+// selection, leaving copying to the context menu and CopySelection
+func (w *Widget) SetCtrlCCopiesSelection(enabled bool) {
+	w.mu.Lock()
+	w.ctrlCCopiesSelection = enabled
+	w.mu.Unlock()
 }
 
 // PasteClipboard pastes text from clipboard into terminal
 // Uses bracketed paste mode if enabled by the application or if the
-// pasted text contains special characters (newlines, control chars, etc.)
+// pasted text contains special characters (newlines, control chars, etc.),
+// then applies the buffer's paste filter (see purfecterm.Buffer.PreparePaste)
 func (w *Widget) PasteClipboard() {
 	if w.clipboard != nil && w.onInput != nil {
 		text, err := w.clipboard.WaitForText()
 		if err == nil && len(text) > 0 {
-			// Determine if we should use bracketed paste
-			useBracketedPaste := w.buffer.IsBracketedPasteModeEnabled()
-
-			// Also use bracketed paste if text contains special characters
-			// even if the application hasn't requested it
-			if !useBracketedPaste {
-				for _, c := range text {
-					// Check for newlines, control chars, or escape
-					if c == '\n' || c == '\r' || c == '\x1b' || c < 32 {
-						useBracketedPaste = true
-						break
-					}
-				}
-			}
-
-			if useBracketedPaste {
-				// Send bracketed paste start sequence
-				w.onInput([]byte("\x1b[200~"))
-				w.onInput([]byte(text))
-				// Send bracketed paste end sequence
-				w.onInput([]byte("\x1b[201~"))
-			} else {
-				w.onInput([]byte(text))
+			if data := w.buffer.PreparePaste(text); len(data) > 0 {
+				w.onInput(data)
 			}
 		}
 	}
@@ -0,0 +1,346 @@
+package purfectermgtk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gotk3/gotk3/cairo"
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/phroun/purfecterm"
+)
+
+// At scale 2 a cell covers twice the device pixels, while the grid is
+// still laid out in logical pixels and keeps its cols and rows.
+func TestScaleFactorMetrics(t *testing.T) {
+	if err := gtk.InitCheck(nil); err != nil {
+		t.Skip("no display:", err)
+	}
+	w, err := NewWidget(80, 24, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SetScaleFactorOverride(1)
+	cols, rows := w.Buffer().GetSize()
+	cw1, ch1 := w.DeviceCellSize()
+
+	w.SetScaleFactorOverride(2)
+	cw2, ch2 := w.DeviceCellSize()
+	if cw2 != 2*cw1 || ch2 != 2*ch1 {
+		t.Errorf("device cell %dx%d at scale 2, want %dx%d", cw2, ch2, 2*cw1, 2*ch1)
+	}
+	if c, r := w.Buffer().GetSize(); c != cols || r != rows {
+		t.Errorf("grid %dx%d at scale 2, want %dx%d", c, r, cols, rows)
+	}
+}
+
+// A wide glyph spans two cell widths: clicks on either half hit the glyph
+// and the next cell starts two widths in.
+func TestScreenToCellWideChar(t *testing.T) {
+	if err := gtk.InitCheck(nil); err != nil {
+		t.Skip("no display:", err)
+	}
+	w, err := NewWidget(20, 4, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := w.Buffer()
+	b.SetWideCharMode(true)
+	w.FeedString("日a")
+
+	w.mu.Lock()
+	cw := float64(w.charWidth)
+	w.mu.Unlock()
+	for _, tc := range []struct {
+		cells float64
+		want  int
+	}{
+		{0.5, 0}, {1.5, 0}, {2.5, 2}, {3.5, 3},
+	} {
+		x := float64(terminalLeftPadding) + tc.cells*cw
+		if got, _ := w.screenToCell(x, 1); got != tc.want {
+			t.Errorf("screenToCell at %.1f cells = %d, want %d", tc.cells, got, tc.want)
+		}
+	}
+}
+
+// The antialias and hinting chosen with SetFontRendering are what onDraw
+// sets on the cairo context before drawing text.
+func TestFontRenderingApplied(t *testing.T) {
+	surface := cairo.CreateImageSurface(cairo.FORMAT_ARGB32, 1, 1)
+	cr := cairo.Create(surface)
+
+	setFontRendering(cr, cairo.ANTIALIAS_NONE, parseHintStyle("full"))
+	if aa, hint := fontRendering(cr); aa != cairo.ANTIALIAS_NONE || hint != cairo.HINT_STYLE_FULL {
+		t.Errorf("context options = %v/%v, want none/full", aa, hint)
+	}
+	setFontRendering(cr, cairo.ANTIALIAS_DEFAULT, parseHintStyle(""))
+	if aa, hint := fontRendering(cr); aa != cairo.ANTIALIAS_DEFAULT || hint != cairo.HINT_STYLE_DEFAULT {
+		t.Errorf("context options = %v/%v, want defaults", aa, hint)
+	}
+
+	if err := gtk.InitCheck(nil); err != nil {
+		t.Skip("no display:", err)
+	}
+	w, err := NewWidget(20, 4, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SetFontRendering(false, "slight")
+	w.mu.Lock()
+	aa, hint := w.fontAntialias, w.fontHintStyle
+	w.mu.Unlock()
+	if aa != cairo.ANTIALIAS_NONE || hint != cairo.HINT_STYLE_SLIGHT {
+		t.Errorf("widget options = %v/%v, want none/slight", aa, hint)
+	}
+}
+
+// The overlay hint is sized and placed like a scrollbar thumb: the visible
+// share of the lines, at the bottom for live output and the top when
+// scrolled all the way back.
+func TestScrollHintExtent(t *testing.T) {
+	tests := []struct {
+		offset, maxOffset, rows int
+		top, length             float64
+	}{
+		{0, 300, 100, 300, 100},   // Live output: bottom quarter
+		{300, 300, 100, 0, 100},   // Oldest line at the top
+		{150, 300, 100, 150, 100}, // Halfway
+		{999, 300, 100, 0, 100},   // Offsets are clamped
+		{0, 100000, 24, 384, 16},  // Long scrollback: minimum length
+	}
+	for _, tt := range tests {
+		top, length, ok := scrollHintExtent(tt.offset, tt.maxOffset, tt.rows, 400)
+		if !ok || top != tt.top || length != tt.length {
+			t.Errorf("scrollHintExtent(%d, %d, %d) = %v, %v, %v; want %v, %v",
+				tt.offset, tt.maxOffset, tt.rows, top, length, ok, tt.top, tt.length)
+		}
+	}
+	if _, _, ok := scrollHintExtent(0, 0, 24, 400); ok {
+		t.Error("hint shown with nothing to scroll")
+	}
+
+	// Held, then fading out
+	for _, tt := range []struct {
+		since time.Duration
+		alpha float64
+	}{
+		{0, 1}, {scrollHintHold - 1, 1}, {scrollHintHold + scrollHintFade/2, 0.5}, {scrollHintHold + scrollHintFade, 0},
+	} {
+		if got := scrollHintAlpha(tt.since); got != tt.alpha {
+			t.Errorf("scrollHintAlpha(%v) = %v, want %v", tt.since, got, tt.alpha)
+		}
+	}
+}
+
+// A rune missing from the first two fonts of the chain resolves to the
+// third; the chain order decides between fonts that both cover a rune.
+func TestFirstCoveringFont(t *testing.T) {
+	coverage := map[string]string{
+		"Symbols": "★",
+		"Greek":   "αβ★",
+		"CJK":     "中文αβ",
+	}
+	hasGlyph := func(family string, r rune) bool {
+		for _, c := range coverage[family] {
+			if c == r {
+				return true
+			}
+		}
+		return false
+	}
+	chain := []string{"Symbols", "Greek", "CJK"}
+	for r, want := range map[rune]string{'中': "CJK", 'α': "Greek", '★': "Symbols", '☃': ""} {
+		if got := firstCoveringFont(chain, r, hasGlyph); got != want {
+			t.Errorf("firstCoveringFont(%q) = %q, want %q", r, got, want)
+		}
+	}
+}
+
+// countGlyphChecks replaces the Pango coverage check with one where only
+// the named font covers non-ASCII runes, counting the calls
+func countGlyphChecks(tb testing.TB, covering string) *int {
+	calls := 0
+	saved := fontHasGlyph
+	fontHasGlyph = func(family string, fontSize int, r rune) bool {
+		calls++
+		return family == covering
+	}
+	tb.Cleanup(func() { fontHasGlyph = saved })
+	return &calls
+}
+
+// Coverage is checked once per (font, rune); changing the fonts starts over
+func TestFontCacheAvoidsRepeatChecks(t *testing.T) {
+	calls := countGlyphChecks(t, "CJK")
+	w := &Widget{buffer: purfecterm.NewBuffer(10, 2, 0), fontFallbacks: []string{"Symbols", "CJK"}}
+
+	for i := 0; i < 3; i++ {
+		if got := w.getFontForCharacter('中', "Mono", 12); got != "CJK" {
+			t.Fatalf("font for '中' = %q, want the chain's CJK", got)
+		}
+	}
+	if *calls != 3 {
+		t.Errorf("%d coverage checks for one rune drawn three times, want 3 (main and two chain fonts)", *calls)
+	}
+
+	w.fontCache = nil // as SetFont and SetFontFallbacks do
+	w.getFontForCharacter('中', "Mono", 12)
+	if *calls != 6 {
+		t.Errorf("%d coverage checks after the fonts changed, want 6", *calls)
+	}
+}
+
+// A screen full of CJK: after the first frame, no coverage checks
+func BenchmarkFontForCJKScreen(b *testing.B) {
+	calls := countGlyphChecks(b, "CJK")
+	w := &Widget{buffer: purfecterm.NewBuffer(80, 24, 0), fontFamilyCJK: "CJK"}
+	screen := make([]rune, 80*24)
+	for i := range screen {
+		screen[i] = rune(0x4E00 + i%2000)
+	}
+	for _, r := range screen {
+		w.getFontForCharacter(r, "Mono", 12)
+	}
+	warm := *calls
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, r := range screen {
+			w.getFontForCharacter(r, "Mono", 12)
+		}
+	}
+	b.ReportMetric(float64(*calls-warm)/float64(b.N), "checks/frame")
+}
+
+// The blink timer keeps a steady cursor solid and toggles slow blink half
+// as often as fast
+func TestCursorBlinkTicks(t *testing.T) {
+	b := purfecterm.NewBuffer(10, 2, 0)
+	purfecterm.NewParser(b).Parse([]byte("\x1b[2 q"))
+	if _, blink := b.GetCursorStyle(); cursorBlinkTicks(blink) != 0 {
+		t.Errorf("steady DECSCUSR: %d ticks per phase, want 0 (never toggles)", cursorBlinkTicks(blink))
+	}
+	if slow, fast := cursorBlinkTicks(purfecterm.CursorBlinkSlow), cursorBlinkTicks(purfecterm.CursorBlinkFast); slow != 10 || fast != 5 {
+		t.Errorf("ticks slow=%d fast=%d, want 10 and 5", slow, fast)
+	}
+}
+
+// onDraw decides through blinkTextVisible whether to draw each cell's text:
+// with BlinkModeNone (or bounce or bright) blink text is drawn at every
+// phase, while BlinkModeBlink hides it for part of the cycle, and never
+// hides a cell that doesn't blink
+func TestBlinkModeNoneStatic(t *testing.T) {
+	anim := purfecterm.DefaultBlinkAnimation
+	blink := purfecterm.Cell{Char: 'A', Blink: true}
+	plain := purfecterm.Cell{Char: 'A'}
+	hidden := false
+	for i := 0; i < 64; i++ {
+		phase := float64(i) * 6.283185 / 64
+		for _, mode := range []purfecterm.BlinkMode{purfecterm.BlinkModeNone, purfecterm.BlinkModeBounce, purfecterm.BlinkModeBright} {
+			if !blinkTextVisible(&blink, mode, anim, phase) {
+				t.Fatalf("mode %v: text hidden at phase %v", mode, phase)
+			}
+		}
+		if !blinkTextVisible(&plain, purfecterm.BlinkModeBlink, anim, phase) {
+			t.Fatalf("BlinkModeBlink: non-blink text hidden at phase %v", phase)
+		}
+		if !blinkTextVisible(&blink, purfecterm.BlinkModeBlink, anim, phase) {
+			hidden = true
+		}
+	}
+	if !hidden {
+		t.Error("BlinkModeBlink never hid the text")
+	}
+	if purfecterm.BlinkModeNone == purfecterm.BlinkModeBounce || purfecterm.BlinkModeNone == purfecterm.BlinkModeBright {
+		t.Error("BlinkModeNone shares a value with an animated mode")
+	}
+	if m := purfecterm.ParseBlinkMode("none"); m != purfecterm.BlinkModeNone {
+		t.Errorf("ParseBlinkMode(\"none\") = %v", m)
+	}
+}
+
+// SetGridSize resizes the buffer and asks for exactly the pixels that grid
+// needs; a configure at another size leaves the grid alone
+func TestSetGridSize(t *testing.T) {
+	if w, h := gridPixelSize(80, 24, 9, 18); w != 80*9+terminalLeftPadding || h != 24*18 {
+		t.Errorf("gridPixelSize = %dx%d", w, h)
+	}
+
+	if err := gtk.InitCheck(nil); err != nil {
+		t.Skip("no display:", err)
+	}
+	w, err := NewWidget(40, 10, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resized [2]int
+	w.SetResizeCallback(func(cols, rows int) { resized = [2]int{cols, rows} })
+
+	w.SetGridSize(100, 30)
+	if cols, rows := w.Buffer().GetSize(); cols != 100 || rows != 30 {
+		t.Errorf("buffer %dx%d, want 100x30", cols, rows)
+	}
+	if resized != [2]int{100, 30} {
+		t.Errorf("resize callback got %v, want [100 30]", resized)
+	}
+	cw, ch := w.scaledCellSize()
+	wantW, wantH := gridPixelSize(100, 30, cw, ch)
+	if rw, rh := w.DrawingArea().GetSizeRequest(); rw != wantW || rh != wantH {
+		t.Errorf("size request %dx%d, want %dx%d", rw, rh, wantW, wantH)
+	}
+
+	w.onConfigure(w.DrawingArea(), nil) // Allocated some other size
+	if cols, rows := w.Buffer().GetSize(); cols != 100 || rows != 30 {
+		t.Errorf("after configure: buffer %dx%d, want the forced 100x30", cols, rows)
+	}
+
+	w.ClearGridSize()
+	if rw, rh := w.DrawingArea().GetSizeRequest(); rw != minDrawingAreaWidth || rh != minDrawingAreaHeight {
+		t.Errorf("size request after ClearGridSize %dx%d, want the minimum", rw, rh)
+	}
+
+	// A manual resize also ends the forced grid
+	w.SetGridSize(100, 30)
+	w.Resize(60, 20)
+	if rw, rh := w.DrawingArea().GetSizeRequest(); rw != minDrawingAreaWidth || rh != minDrawingAreaHeight {
+		t.Errorf("size request after Resize %dx%d, want the minimum, not the old forced grid", rw, rh)
+	}
+	if cols, rows := w.gridCols, w.gridRows; cols != 0 || rows != 0 {
+		t.Errorf("forced grid %dx%d after Resize, want none", cols, rows)
+	}
+	if cols, rows := w.Buffer().GetSize(); cols != 60 || rows != 20 {
+		t.Errorf("buffer %dx%d after Resize, want 60x20", cols, rows)
+	}
+}
+
+// With the trailing-newline option on, a two-line selection is copied with
+// a final newline; a selection within one line is left alone.
+func TestCopyTrailingNewline(t *testing.T) {
+	if got := copyText("one", true); got != "one" {
+		t.Errorf("single line = %q, want it unchanged", got)
+	}
+	if got := copyText("one\ntwo\n", true); got != "one\ntwo\n" {
+		t.Errorf("already ending in a newline = %q, want no second one", got)
+	}
+
+	if err := gtk.InitCheck(nil); err != nil {
+		t.Skip("no display:", err)
+	}
+	w, err := NewWidget(20, 5, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.FeedString("one\r\ntwo")
+	b := w.Buffer()
+	b.StartSelection(0, 0)
+	b.UpdateSelection(2, 1)
+	b.EndSelection()
+
+	if got := w.selectionCopyText(); got != "one\ntwo" {
+		t.Errorf("default copy = %q, want %q", got, "one\ntwo")
+	}
+	w.SetCopyTrailingNewline(true)
+	if got := w.selectionCopyText(); got != "one\ntwo\n" {
+		t.Errorf("with trailing newline = %q, want %q", got, "one\ntwo\n")
+	}
+}
@@ -0,0 +1,70 @@
+package purfecterm
+
+import "testing"
+
+// The cursor move callback fires, outside the lock, with the new position.
+func TestCursorMoveCallback(t *testing.T) {
+	b := NewBuffer(10, 5, 100)
+	var gotX, gotY, calls int
+	b.SetCursorMoveCallback(func(x, y int) {
+		gotX, gotY = b.GetCursor() // would deadlock if the lock were held
+		if x != gotX || y != gotY {
+			t.Errorf("callback got %d,%d but cursor is %d,%d", x, y, gotX, gotY)
+		}
+		calls++
+	})
+
+	b.MoveCursorDown(2)
+	if calls != 1 || gotX != 0 || gotY != 2 {
+		t.Fatalf("after MoveCursorDown(2): calls=%d pos=%d,%d", calls, gotX, gotY)
+	}
+	b.MoveCursorDown(10)
+	if gotY != 4 {
+		t.Fatalf("MoveCursorDown should clamp to the last row, got %d", gotY)
+	}
+	b.MoveCursorDown(1) // already at the bottom: no movement, no callback
+	if calls != 2 {
+		t.Fatalf("callback fired without movement (calls=%d)", calls)
+	}
+}
+
+func TestModeChangeCallback(t *testing.T) {
+	b := NewBuffer(10, 5, 100)
+	var changes []string
+	b.SetModeChangeCallback(func(mode string, enabled bool) {
+		if enabled {
+			changes = append(changes, "+"+mode)
+		} else {
+			changes = append(changes, "-"+mode)
+		}
+	})
+
+	NewParser(b).Parse([]byte("\x1b[?2004h\x1b[?7l\x1b[?7l\x1b[?2004l\x1b[?6h\x1b[?6h\x1b[?6l"))
+	want := []string{"+bracketedpaste", "-autowrap", "-bracketedpaste", "+origin", "-origin"}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("changes = %v, want %v", changes, want)
+		}
+	}
+}
+
+// OSC 9999 comments reach the comment callback and never the screen.
+func TestCommentCallback(t *testing.T) {
+	b := NewBuffer(20, 2, 0)
+	var got []string
+	b.SetCommentCallback(func(text string) { got = append(got, text) })
+
+	NewParser(b).Parse([]byte("\x1b]9999;saved 2026-10-16 host=x\x1b\\ab\x1b]9999;second\x07"))
+	if len(got) != 2 || got[0] != "saved 2026-10-16 host=x" || got[1] != "second" {
+		t.Fatalf("comments = %q", got)
+	}
+	if c := b.GetCell(0, 0).Char; c != 'a' {
+		t.Fatalf("cell 0,0 = %q, want 'a' (comment must not print)", c)
+	}
+	if x, _ := b.GetCursor(); x != 2 {
+		t.Fatalf("cursor x = %d, want 2", x)
+	}
+}
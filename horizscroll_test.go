@@ -0,0 +1,35 @@
+package purfecterm
+
+import "testing"
+
+func TestHorizScrollRange(t *testing.T) {
+	cases := []struct {
+		longest, cols, offset, want int
+	}{
+		{40, 80, 0, 0},    // content fits
+		{120, 80, 0, 40},  // last column lines up with the right edge
+		{120, 80, 25, 40}, // offset within range
+		{100, 80, 30, 30}, // offset past contentBasedMax is preserved
+	}
+	for _, tc := range cases {
+		if got := horizScrollRange(tc.longest, tc.cols, tc.offset); got != tc.want {
+			t.Errorf("horizScrollRange(%d, %d, %d) = %d, want %d",
+				tc.longest, tc.cols, tc.offset, got, tc.want)
+		}
+	}
+}
+
+// Split content wider than the screen extends the horizontal range.
+func TestMaxHorizOffsetSplitWidth(t *testing.T) {
+	b := NewBuffer(10, 3, 100)
+	if b.NeedsHorizScrollbar() {
+		t.Fatal("empty buffer should not need a horizontal scrollbar")
+	}
+	b.SetSplitContentWidth(25)
+	if !b.NeedsHorizScrollbar() {
+		t.Fatal("wide split content should need a horizontal scrollbar")
+	}
+	if got := b.GetMaxHorizOffset(); got != 15 {
+		t.Fatalf("GetMaxHorizOffset = %d, want 15", got)
+	}
+}
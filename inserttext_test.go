@@ -0,0 +1,46 @@
+package purfecterm
+
+import "testing"
+
+// InsertText lays text out exactly as writing it one WriteChar at a time
+// does, wrapping included.
+func TestInsertTextMatchesWriteChar(t *testing.T) {
+	for _, s := range []string{
+		"the quick brown fox jumps over the lazy dog",
+		"wide 中文字符 and é combining, past the edge",
+	} {
+		want := NewBuffer(10, 6, 0)
+		for _, ch := range s {
+			want.WriteChar(ch)
+		}
+		got := NewBuffer(10, 6, 0)
+		got.InsertText(s)
+
+		if diffs := got.DiffVisible(want); len(diffs) > 0 {
+			t.Errorf("%q: InsertText differs from WriteChar at %d cells, first %+v", s, len(diffs), diffs[0])
+		}
+		gx, gy := got.GetCursor()
+		wx, wy := want.GetCursor()
+		if gx != wx || gy != wy {
+			t.Errorf("%q: cursor (%d,%d), want (%d,%d)", s, gx, gy, wx, wy)
+		}
+		if gy == 0 {
+			t.Errorf("%q: text longer than the line did not wrap", s)
+		}
+	}
+}
+
+// Control characters are skipped, not interpreted
+func TestInsertTextSkipsControls(t *testing.T) {
+	b := NewBuffer(20, 3, 0)
+	b.InsertText("a\r\nb\x1b[31mc\x07\x7f\u009bd")
+	if got := b.CurrentLineText(); got != "ab[31mcd" {
+		t.Errorf("line = %q, want %q", got, "ab[31mcd")
+	}
+	if x, y := b.GetCursor(); x != 8 || y != 0 {
+		t.Errorf("cursor = (%d,%d), want (8,0)", x, y)
+	}
+	if fg := b.GetCell(3, 0).Foreground; fg == StandardColor(1) {
+		t.Error("escape sequence was interpreted")
+	}
+}
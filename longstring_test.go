@@ -0,0 +1,70 @@
+package purfecterm
+
+import (
+	"bytes"
+	"testing"
+)
+
+// An unterminated OSC is abandoned once it passes the maximum string
+// length: the parser stops buffering it, reports it, and skips the rest of
+// it up to the terminator.
+func TestLongOSCAbandoned(t *testing.T) {
+	b := NewBuffer(20, 5, 100)
+	p := NewParser(b)
+	var unknown []string
+	p.SetUnknownSequenceCallback(func(seq string) { unknown = append(unknown, seq) })
+
+	p.Parse([]byte("\x1b]2;"))
+	chunk := bytes.Repeat([]byte("A"), 64*1024)
+	for i := 0; i < 32; i++ { // 2MB, twice the default cap
+		p.Parse(chunk)
+		if n := p.oscBuf.Len(); n > DefaultMaxStringLength {
+			t.Fatalf("OSC buffer grew to %d bytes", n)
+		}
+	}
+	if len(unknown) != 1 || unknown[0] != "OSC 2" {
+		t.Fatalf("unknown-sequence callback got %q, want [\"OSC 2\"]", unknown)
+	}
+	if !p.InEscapeSequence() {
+		t.Fatal("rest of the abandoned string should still be skipped")
+	}
+
+	// The terminator ends it without applying it; text after it prints
+	p.Parse([]byte("\x1b\\X"))
+	if got := b.GetTitle(); got != "" {
+		t.Errorf("title = %d bytes, want the abandoned OSC not applied", len(got))
+	}
+	if got := b.GetCell(0, 0).Char; got != 'X' {
+		t.Errorf("cell 0 = %q, want 'X'", got)
+	}
+	if x, _ := b.GetCursor(); x != 1 {
+		t.Errorf("cursor x = %d, want 1", x)
+	}
+}
+
+func TestMaxStringLength(t *testing.T) {
+	b := NewBuffer(20, 5, 100)
+	p := NewParser(b)
+	var unknown []string
+	p.SetUnknownSequenceCallback(func(seq string) { unknown = append(unknown, seq) })
+	p.SetMaxStringLength(8)
+
+	p.Parse([]byte("\x1b]2;short\x07"))
+	if got := b.GetTitle(); got != "short" {
+		t.Errorf("title = %q, want %q", got, "short")
+	}
+
+	p.Parse([]byte("\x1b]2;much too long\x07"))
+	if got := b.GetTitle(); got != "short" {
+		t.Errorf("title = %q, want the long OSC dropped", got)
+	}
+
+	// DCS is capped the same way; CAN ends the skipped string
+	p.Parse([]byte("\x1bP" + string(bytes.Repeat([]byte("q"), 20)) + "\x18Y"))
+	if got := b.GetCell(0, 0).Char; got != 'Y' {
+		t.Errorf("cell 0 = %q, want 'Y'", got)
+	}
+	if len(unknown) != 2 || unknown[0] != "OSC 2" || unknown[1] != "DCS" {
+		t.Errorf("unknown-sequence callback got %q", unknown)
+	}
+}
@@ -0,0 +1,26 @@
+package purfecterm
+
+// GridSizeForPixels maps a drawing area in pixels to a terminal grid, given
+// the font's cell size and the buffer's screen scaling (see
+// GetHorizontalScale/GetVerticalScale). Widget adapters call this from their
+// resize handlers; the result is never smaller than 1x1.
+func GridSizeForPixels(widthPx, heightPx, charWidth, charHeight int, horizScale, vertScale float64) (cols, rows int) {
+	scaledCharWidth := int(float64(charWidth) * horizScale)
+	scaledCharHeight := int(float64(charHeight) * vertScale)
+	if scaledCharWidth < 1 {
+		scaledCharWidth = 1
+	}
+	if scaledCharHeight < 1 {
+		scaledCharHeight = 1
+	}
+
+	cols = widthPx / scaledCharWidth
+	rows = heightPx / scaledCharHeight
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return cols, rows
+}
@@ -0,0 +1,17 @@
+package purfecterm
+
+import "testing"
+
+// An 800x600 area with a 10x20 font gives 80x30; 132-column scaling and
+// tiny areas are handled.
+func TestGridSizeForPixels(t *testing.T) {
+	if c, r := GridSizeForPixels(800, 600, 10, 20, 1.0, 1.0); c != 80 || r != 30 {
+		t.Errorf("800x600 @ 10x20 = %dx%d, want 80x30", c, r)
+	}
+	if c, _ := GridSizeForPixels(800, 600, 10, 20, 0.5, 1.0); c != 160 {
+		t.Errorf("half-width scaling = %d cols, want 160", c)
+	}
+	if c, r := GridSizeForPixels(3, 3, 10, 20, 1.0, 1.0); c != 1 || r != 1 {
+		t.Errorf("tiny area = %dx%d, want 1x1", c, r)
+	}
+}
@@ -36,3 +36,37 @@ func TestModeNumberMigration(t *testing.T) {
 		t.Fatal("?7029h should set ambiguous width narrow")
 	}
 }
+
+// SaveScrollbackANS emits the width modes inline, so reparsing its output
+// must bring flex-width ambiguous characters back at the widths they had.
+func TestFlexWidthANSRoundTrip(t *testing.T) {
+	src := NewBuffer(20, 3, 100)
+	p := NewParser(src)
+	p.ParseString("\x1b[?7027h\x1b[?7030haαb\x1b[?7029hβ\x1b[?7027l")
+
+	widths := func(b *Buffer) map[rune]float64 {
+		got := map[rune]float64{}
+		for x := 0; x < 20; x++ {
+			c := b.GetCell(x, 0)
+			if c.Char != 0 && c.Char != ' ' {
+				got[c.Char] = c.CellWidth
+			}
+		}
+		return got
+	}
+	want := widths(src)
+	if want['α'] != 2.0 || want['β'] != 1.0 {
+		t.Fatalf("source widths α=%v β=%v, want 2 and 1", want['α'], want['β'])
+	}
+
+	// Taller than the source so the saved lines' trailing newlines don't
+	// scroll the first one away
+	dst := NewBuffer(20, 5, 100)
+	NewParser(dst).ParseString(src.SaveScrollbackANS())
+	got := widths(dst)
+	for r, w := range want {
+		if got[r] != w {
+			t.Errorf("%q reloaded at CellWidth %v, want %v", r, got[r], w)
+		}
+	}
+}
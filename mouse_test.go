@@ -0,0 +1,35 @@
+package purfecterm
+
+import "testing"
+
+// A left click at (10,5) encodes per the mode the application enabled.
+func TestBufferEncodeMouseEvent(t *testing.T) {
+	b := NewBuffer(80, 24, 100)
+	p := NewParser(b)
+
+	if got := b.EncodeMouseEvent(MouseButtonLeft, 10, 5, true); got != nil {
+		t.Fatalf("no tracking should not report, got %q", got)
+	}
+
+	p.Parse([]byte("\x1b[?1000h"))
+	if got := string(b.EncodeMouseEvent(MouseButtonLeft, 10, 5, true)); got != "\x1b[M *%" {
+		t.Fatalf("X10 encoding = %q", got)
+	}
+
+	p.Parse([]byte("\x1b[?1006h"))
+	if tr, enc := b.MouseMode(); tr != 1000 || enc != 1006 {
+		t.Fatalf("MouseMode = %d, %d", tr, enc)
+	}
+	if got := string(b.EncodeMouseEvent(MouseButtonLeft, 10, 5, true)); got != "\x1b[<0;10;5M" {
+		t.Fatalf("SGR press = %q", got)
+	}
+	if got := string(b.EncodeMouseEvent(MouseButtonLeft, 10, 5, false)); got != "\x1b[<0;10;5m" {
+		t.Fatalf("SGR release = %q", got)
+	}
+
+	// DEC 9 (X10 compatibility) reports presses only
+	p.Parse([]byte("\x1b[?1006l\x1b[?9h"))
+	if got := b.EncodeMouseEvent(MouseButtonLeft, 10, 5, false); got != nil {
+		t.Fatalf("mode 9 should not report releases, got %q", got)
+	}
+}
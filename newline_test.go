@@ -0,0 +1,34 @@
+package purfecterm
+
+import "testing"
+
+// LF keeps the column unless LNM (CSI 20 h) is on, when it also returns
+// the carriage; Enter follows the same mode.
+func TestNewlineMode(t *testing.T) {
+	b := NewBuffer(20, 5, 100)
+	p := NewParser(b)
+
+	p.Parse([]byte("hello\n"))
+	if x, y := b.GetCursor(); x != 5 || y != 1 {
+		t.Fatalf("LF without LNM: cursor %d,%d, want 5,1", x, y)
+	}
+	if got := string(b.EnterKeySequence()); got != "\r" {
+		t.Errorf("Enter without LNM sends %q, want CR", got)
+	}
+
+	p.Parse([]byte("\x1b[20h\x1b[2;6H\n"))
+	if !b.IsNewlineModeEnabled() {
+		t.Fatal("CSI 20 h should enable LNM")
+	}
+	if x, y := b.GetCursor(); x != 0 || y != 2 {
+		t.Errorf("LF under LNM: cursor %d,%d, want 0,2", x, y)
+	}
+	if got := string(b.EnterKeySequence()); got != "\r\n" {
+		t.Errorf("Enter under LNM sends %q, want CR LF", got)
+	}
+
+	p.Parse([]byte("\x1b[20l"))
+	if b.IsNewlineModeEnabled() {
+		t.Error("CSI 20 l should disable LNM")
+	}
+}
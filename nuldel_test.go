@@ -0,0 +1,49 @@
+package purfecterm
+
+import "testing"
+
+// NUL and DEL are padding: they never reach the screen, and inside an
+// escape sequence they neither end nor corrupt it.
+func TestNulDelIgnored(t *testing.T) {
+	b := NewBuffer(20, 5, 100)
+	p := NewParser(b)
+
+	p.Parse([]byte("A\x00B\x7fC"))
+	for x, want := range "ABC" {
+		if got := b.GetCell(x, 0).Char; got != want {
+			t.Errorf("cell %d = %q, want %q", x, got, want)
+		}
+	}
+	if x, _ := b.GetCursor(); x != 3 {
+		t.Errorf("cursor x = %d, want 3", x)
+	}
+
+	// Byte-at-a-time goes through processByte rather than the text run
+	for _, c := range []byte("\r\nA\x00B\x7fC") {
+		p.Parse([]byte{c})
+	}
+	for x, want := range "ABC" {
+		if got := b.GetCell(x, 1).Char; got != want {
+			t.Errorf("row 1 cell %d = %q, want %q", x, got, want)
+		}
+	}
+
+	// Mid-sequence: CSI 3 DEL 1 NUL m is still SGR 31
+	p.Parse([]byte("\r\n\x1b[3\x7f1\x00mX"))
+	cell := b.GetCell(0, 2)
+	if cell.Char != 'X' {
+		t.Fatalf("cell after CSI = %q, want 'X'", cell.Char)
+	}
+	if cell.Foreground != StandardColor(1) {
+		t.Errorf("foreground = %+v, want red", cell.Foreground)
+	}
+	if x, _ := b.GetCursor(); x != 1 {
+		t.Errorf("cursor x = %d, want 1 (no stray output from the CSI)", x)
+	}
+
+	// An overlong UTF-8 NUL (C0 80) is invalid, not a NUL cell
+	p.Parse([]byte("\r\n\xc0\x80"))
+	if got := b.GetCell(0, 3).Char; got != 0xFFFD {
+		t.Errorf("overlong NUL = %U, want U+FFFD", got)
+	}
+}
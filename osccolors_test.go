@@ -0,0 +1,74 @@
+package purfecterm
+
+import "testing"
+
+// OSC 4 replaces a palette color and OSC 104 puts the scheme's back, so a
+// program that sets and resets colors leaves the terminal as it was.
+func TestOSCPaletteReset(t *testing.T) {
+	b := NewBuffer(10, 3, 0)
+	p := NewParser(b)
+	scheme := DefaultColorScheme()
+	green := StandardColor(2)
+	def := scheme.ResolveColor(green, true, true)
+
+	p.Parse([]byte("\x1b]4;2;rgb:12/34/56\x07"))
+	if got := b.EffectiveScheme(scheme).ResolveColor(green, true, true); got != TrueColor(0x12, 0x34, 0x56) {
+		t.Errorf("after OSC 4: %+v, want #123456", got)
+	}
+	if got := scheme.ResolveColor(green, true, true); got != def {
+		t.Error("OSC 4 changed the host's scheme")
+	}
+
+	p.Parse([]byte("\x1b]104;2\x1b\\"))
+	if got := b.EffectiveScheme(scheme).ResolveColor(green, true, true); got != def {
+		t.Errorf("after OSC 104;2: %+v, want the default %+v", got, def)
+	}
+
+	// With no indexes OSC 104 resets every entry
+	p.Parse([]byte("\x1b]4;1;#ff0000;9;rgb:f/f/f\x07\x1b]104\x07"))
+	if pal := b.EffectiveScheme(scheme).DarkPalette; pal[1] != scheme.DarkPalette[1] || pal[9] != scheme.DarkPalette[9] {
+		t.Errorf("after OSC 104: entries 1 and 9 = %+v, %+v, want the scheme's", pal[1], pal[9])
+	}
+}
+
+// OSC 10/11/12 set the default foreground, background and cursor colors;
+// OSC 110/111/112 reset each one.
+func TestOSCDynamicColors(t *testing.T) {
+	b := NewBuffer(10, 3, 0)
+	p := NewParser(b)
+	scheme := DefaultColorScheme()
+
+	p.Parse([]byte("\x1b]10;#111111;#222222\x07\x1b]12;rgb:3333/3333/3333\x07"))
+	s := b.EffectiveScheme(scheme)
+	if s.Foreground(true) != TrueColor(0x11, 0x11, 0x11) || s.Background(false) != TrueColor(0x22, 0x22, 0x22) || s.Cursor != TrueColor(0x33, 0x33, 0x33) {
+		t.Errorf("after OSC 10/12: fg %+v bg %+v cursor %+v", s.Foreground(true), s.Background(false), s.Cursor)
+	}
+
+	p.Parse([]byte("\x1b]110\x07\x1b]111\x07\x1b]112\x07"))
+	s = b.EffectiveScheme(scheme)
+	if s.Foreground(true) != scheme.Foreground(true) || s.Background(true) != scheme.Background(true) || s.Cursor != scheme.Cursor {
+		t.Error("OSC 110/111/112 did not restore the scheme's colors")
+	}
+}
+
+// OSC 4 reaches past the ANSI colors into the rest of the 256-color
+// palette, and OSC 104 puts those back too.
+func TestOSCPalette256(t *testing.T) {
+	b := NewBuffer(10, 3, 0)
+	p := NewParser(b)
+	scheme := DefaultColorScheme()
+	orange := PaletteColor(208)
+
+	p.Parse([]byte("\x1b]4;208;#102030\x07"))
+	if got := b.EffectiveScheme(scheme).ResolveColor(orange, true, true); got != TrueColor(0x10, 0x20, 0x30) {
+		t.Errorf("after OSC 4;208: %+v, want #102030", got)
+	}
+	if got := b.EffectiveScheme(scheme).ResolveColor(PaletteColor(209), true, true); got != PaletteColor(209) {
+		t.Errorf("index 209 = %+v, want the fixed color", got)
+	}
+
+	p.Parse([]byte("\x1b]104;208\x07"))
+	if got := b.EffectiveScheme(scheme).ResolveColor(orange, true, true); got != orange {
+		t.Errorf("after OSC 104;208: %+v, want the fixed color", got)
+	}
+}
@@ -0,0 +1,23 @@
+package purfecterm
+
+import (
+	"strings"
+	"testing"
+)
+
+// SGR 53 sets overline and SGR 55 clears it; the ANS export preserves it.
+// (GTK and Qt draw it as a line along the top edge of the cell.)
+func TestSGROverline(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	NewParser(b).Parse([]byte("\x1b[53mA\x1b[55mB"))
+
+	if !b.GetCell(0, 0).Overline {
+		t.Fatal("SGR 53 should set overline")
+	}
+	if b.GetCell(1, 0).Overline {
+		t.Fatal("SGR 55 should clear overline")
+	}
+	if ans := b.SaveScrollbackANS(); !strings.Contains(ans, "\x1b[53m") {
+		t.Fatalf("ANS export lost overline: %q", ans)
+	}
+}
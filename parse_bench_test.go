@@ -0,0 +1,99 @@
+package purfecterm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// mixedOutput builds about 1MB of text lines interleaved with SGR changes,
+// roughly what colored build or log output looks like.
+func mixedOutput() []byte {
+	var sb strings.Builder
+	for i := 0; sb.Len() < 1<<20; i++ {
+		fmt.Fprintf(&sb, "\x1b[1;3%dmINFO\x1b[0m %06d \x1b[38;5;%dmcompiling package\x1b[m ", i%8, i, i%256)
+		fmt.Fprintf(&sb, "\x1b[38:2::%d:%d:%dmok\x1b[39m done in %dms\r\n", i%256, (i*3)%256, (i*7)%256, i%1000)
+	}
+	return []byte(sb.String())
+}
+
+func BenchmarkParseMixed(b *testing.B) {
+	data := mixedOutput()
+	buf := NewBuffer(80, 24, 1000)
+	p := NewParser(buf)
+	p.Parse(data) // fill the scrollback so the loop measures steady state
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Parse(data)
+	}
+}
+
+// plainASCII is about 100KB of printable lines with CRLFs
+func plainASCII() []byte {
+	var sb strings.Builder
+	for i := 0; sb.Len() < 100<<10; i++ {
+		fmt.Fprintf(&sb, "%05d the quick brown fox jumps over the lazy dog, again and again\r\n", i)
+	}
+	return []byte(sb.String())
+}
+
+func BenchmarkWriteCharASCII(b *testing.B) {
+	data := plainASCII()
+	buf := NewBuffer(80, 24, 1000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, c := range data {
+			switch c {
+			case '\r':
+				buf.CarriageReturn()
+			case '\n':
+				buf.LineFeed()
+			default:
+				buf.WriteChar(rune(c))
+			}
+		}
+	}
+}
+
+func BenchmarkWriteRunASCII(b *testing.B) {
+	data := plainASCII()
+	buf := NewBuffer(80, 24, 1000)
+	p := NewParser(buf)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Parse(data)
+	}
+}
+
+// Batched text must land exactly as byte-at-a-time parsing places it,
+// including wide glyphs, combining marks, wrapping and split UTF-8.
+func TestParseBatchedMatchesPerByte(t *testing.T) {
+	input := []byte("héllo 世界 é \x1b[31mred\x1b[m " + strings.Repeat("wrap-", 6) + "\x1b(0lqk\x1b(B ok")
+	whole := NewBuffer(12, 6, 10)
+	NewParser(whole).Parse(input)
+	split := NewBuffer(12, 6, 10)
+	sp := NewParser(split)
+	for i := range input {
+		sp.Parse(input[i : i+1])
+	}
+
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 12; x++ {
+			a, c := whole.GetCell(x, y), split.GetCell(x, y)
+			if a.Char != c.Char || a.Combining != c.Combining || a.Foreground != c.Foreground {
+				t.Errorf("cell %d,%d: batched %q%q, per-byte %q%q", x, y, a.Char, a.Combining, c.Char, c.Combining)
+			}
+		}
+	}
+	x1, y1 := whole.GetCursor()
+	x2, y2 := split.GetCursor()
+	if x1 != x2 || y1 != y2 {
+		t.Errorf("cursor: batched %d,%d, per-byte %d,%d", x1, y1, x2, y2)
+	}
+}
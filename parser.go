@@ -1,6 +1,8 @@
 package purfecterm
 
 import (
+	"bytes"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -15,10 +17,16 @@ const (
 	stateCSIParam                // Reading CSI parameters
 	stateOSC                     // After ESC ]
 	stateOSCString               // Reading OSC string
-	stateCharset                 // After ESC ( or ESC )
+	stateCharset                 // After ESC (, ESC ), ESC * or ESC +
 	stateDECLineAttr             // After ESC # (waiting for line attribute command)
+	stateDCS                     // Reading a DCS string (after ESC P)
+	stateStringIgnore            // Discarding the rest of an abandoned OSC/DCS string
 )
 
+// DefaultMaxStringLength is the longest OSC or DCS string a Parser reads
+// before abandoning it, unless changed with SetMaxStringLength
+const DefaultMaxStringLength = 1 << 20
+
 // SGRParam represents an SGR parameter with optional subparameters
 // For example, "38:2:255:128:0" becomes {Base: 38, Subs: [2, 255, 128, 0]}
 type SGRParam struct {
@@ -26,25 +34,53 @@ type SGRParam struct {
 	Subs []int // Subparameters (colon-separated values after the base)
 }
 
-// Parser parses ANSI escape sequences and updates a Buffer
+// Parser parses ANSI escape sequences and updates a Buffer.
+// Partial escape sequences and UTF-8 characters are kept across Parse
+// calls, so input may be split into chunks at any byte.
 type Parser struct {
 	buffer *Buffer
 	state  parserState
 
 	// CSI sequence accumulator
 	csiParams       []int
-	csiRawParams    []string // Raw parameter strings for subparameter parsing
+	csiSubs         [][2]int // Per-parameter [start, end) range of its ':' subparameters in csiSubStore
+	csiSubStore     []int    // Subparameter values for the current sequence
 	csiPrivate      byte     // For private sequences like ?25h
 	csiIntermediate byte     // For sequences with intermediate bytes like DECSCUSR (SP q)
-	csiBuf          strings.Builder
+	csiMalformed    bool     // Bytes out of order; the sequence is read to its end and dropped
+	csiBuf          []byte
 
 	// OSC accumulator
 	oscCmd int             // OSC command number (e.g., 7000 for palette, 7001 for glyph)
 	oscBuf strings.Builder // OSC command arguments
 
+	// DCS accumulator (only short control strings like DECRQSS are kept)
+	dcsBuf []byte
+
+	// Bytes read of the current OSC/DCS string, and the cap past which it
+	// is abandoned (so an unterminated string can't grow without bound)
+	stringLen int
+	maxString int
+
+	// Told about sequences the parser gave up on; nil ignores them
+	onUnknown func(seq string)
+
+	// Replies to queries (DECRQSS) go here; nil drops them
+	response io.Writer
+
 	// UTF-8 multi-byte handling
 	utf8Buf  []byte
 	utf8Need int
+
+	// Reused scratch for batching printable text into Buffer.WriteRun
+	runBuf []rune
+
+	// Character sets: G0-G3 designations, the slot an SCS is targeting,
+	// whether SO has invoked G1, and a pending single shift (2 or 3)
+	charsets    [4]byte
+	charsetSlot int
+	shiftOut    bool
+	singleShift int
 }
 
 // NewParser creates a new ANSI parser for the given buffer
@@ -53,19 +89,141 @@ func NewParser(buffer *Buffer) *Parser {
 		buffer:    buffer,
 		state:     stateGround,
 		csiParams: make([]int, 0, 16),
+		maxString: DefaultMaxStringLength,
+		charsets:  [4]byte{CharsetASCII, CharsetASCII, CharsetASCII, CharsetASCII},
 	}
 }
 
 // Parse processes input data and updates the terminal buffer
 func (p *Parser) Parse(data []byte) {
-	for _, b := range data {
-		p.processByte(b)
-	}
+	parseInput(p, data)
 }
 
 // ParseString processes a string and updates the terminal buffer
 func (p *Parser) ParseString(data string) {
-	p.Parse([]byte(data))
+	parseInput(p, data)
+}
+
+// Reset abandons any partially received escape sequence or UTF-8
+// character and returns to the ground state. Character set designations
+// are terminal state and are kept.
+func (p *Parser) Reset() {
+	p.state = stateGround
+	p.csiParams = p.csiParams[:0]
+	p.csiSubs = p.csiSubs[:0]
+	p.csiSubStore = p.csiSubStore[:0]
+	p.csiPrivate = 0
+	p.csiIntermediate = 0
+	p.csiMalformed = false
+	p.csiBuf = p.csiBuf[:0]
+	p.oscCmd = 0
+	p.oscBuf.Reset()
+	p.dcsBuf = p.dcsBuf[:0]
+	p.stringLen = 0
+	p.utf8Buf = p.utf8Buf[:0]
+	p.utf8Need = 0
+	p.singleShift = 0
+}
+
+// InEscapeSequence reports whether the parser is partway through an escape
+// sequence, waiting for more input to complete it
+func (p *Parser) InEscapeSequence() bool {
+	return p.state != stateGround
+}
+
+// SetResponseWriter sets where replies to terminal queries are written,
+// normally the input side of the PTY so the hosted program reads them.
+// With no writer (the default) queries are ignored.
+func (p *Parser) SetResponseWriter(w io.Writer) {
+	p.response = w
+}
+
+// SetMaxStringLength sets how many bytes of an OSC or DCS string are read
+// before the string is abandoned: the rest of it, up to its terminator, is
+// discarded and the unknown-sequence callback is told. n <= 0 restores
+// DefaultMaxStringLength.
+func (p *Parser) SetMaxStringLength(n int) {
+	if n <= 0 {
+		n = DefaultMaxStringLength
+	}
+	p.maxString = n
+}
+
+// SetUnknownSequenceCallback sets a callback invoked with the name of each
+// sequence the parser gives up on (e.g. "OSC 2" or "DCS" for a string
+// longer than the maximum string length, "CSI > m" for a CSI sequence with
+// a marker or intermediate it doesn't implement)
+func (p *Parser) SetUnknownSequenceCallback(fn func(seq string)) {
+	p.onUnknown = fn
+}
+
+// Write implements io.Writer by parsing p, so a Parser can stand in as the
+// output of a command (cmd.Stdout = parser). It never fails.
+func (p *Parser) Write(data []byte) (int, error) {
+	p.Parse(data)
+	return len(data), nil
+}
+
+// parseInput feeds data through the state machine. Runs of printable text
+// in the ground state go to the buffer in one WriteRun call instead of one
+// locked WriteChar per character.
+func parseInput[T string | []byte](p *Parser, data T) {
+	for i := 0; i < len(data); {
+		// A single shift applies to one character, so that one goes alone
+		if p.state == stateGround && p.utf8Need == 0 && p.singleShift == 0 {
+			if n := textRun(p, data[i:]); n > 0 {
+				p.buffer.WriteRun(p.runBuf)
+				i += n
+				continue
+			}
+		}
+		p.processByte(data[i])
+		i++
+	}
+}
+
+// textRun collects the printable text at the start of data into p.runBuf
+// and returns how many bytes it used. ASCII is mapped through the active
+// character set; only complete UTF-8 sequences are taken, so split or
+// malformed ones are left to processByte.
+func textRun[T string | []byte](p *Parser, data T) int {
+	charset := p.activeCharset()
+	p.runBuf = p.runBuf[:0]
+	i := 0
+	for i < len(data) {
+		c := data[i]
+		if c >= 0x20 && c < 0x7F {
+			p.runBuf = append(p.runBuf, translateCharset(charset, c))
+			i++
+			continue
+		}
+		var seq [4]byte
+		n := 0
+		switch {
+		case c&0xE0 == 0xC0:
+			n = 2
+		case c&0xF0 == 0xE0:
+			n = 3
+		case c&0xF8 == 0xF0:
+			n = 4
+		}
+		if n == 0 || i+n > len(data) {
+			break
+		}
+		complete := true
+		for k := 0; k < n; k++ {
+			seq[k] = data[i+k]
+			if k > 0 && seq[k]&0xC0 != 0x80 {
+				complete = false
+			}
+		}
+		if !complete {
+			break
+		}
+		p.runBuf = append(p.runBuf, decodeUTF8(seq[:n]))
+		i += n
+	}
+	return i
 }
 
 func (p *Parser) processByte(b byte) {
@@ -78,6 +236,7 @@ func (p *Parser) processByte(b byte) {
 				// Complete UTF-8 sequence
 				r := decodeUTF8(p.utf8Buf)
 				if p.state == stateGround {
+					p.singleShift = 0 // Non-ASCII is never shifted, but ends the shift
 					p.buffer.WriteChar(r)
 				}
 				p.utf8Buf = p.utf8Buf[:0]
@@ -109,6 +268,12 @@ func (p *Parser) processByte(b byte) {
 		}
 	}
 
+	// NUL and DEL are fill characters: dropped inside sequences too, so
+	// they never end or corrupt one (handleGround drops them in text)
+	if (b == 0x00 || b == 0x7F) && p.state != stateGround {
+		return
+	}
+
 	switch p.state {
 	case stateGround:
 		p.handleGround(b)
@@ -121,10 +286,13 @@ func (p *Parser) processByte(b byte) {
 	case stateOSCString:
 		p.handleOSCString(b)
 	case stateCharset:
-		// Consume one character and return to ground
-		p.state = stateGround
+		p.handleCharset(b)
 	case stateDECLineAttr:
 		p.handleDECLineAttr(b)
+	case stateDCS:
+		p.handleDCS(b)
+	case stateStringIgnore:
+		p.handleStringIgnore(b)
 	}
 }
 
@@ -132,21 +300,28 @@ func decodeUTF8(buf []byte) rune {
 	if len(buf) == 0 {
 		return 0xFFFD
 	}
+	var r, least rune
 	switch len(buf) {
 	case 2:
-		return rune(buf[0]&0x1F)<<6 | rune(buf[1]&0x3F)
+		r, least = rune(buf[0]&0x1F)<<6|rune(buf[1]&0x3F), 0x80
 	case 3:
-		return rune(buf[0]&0x0F)<<12 | rune(buf[1]&0x3F)<<6 | rune(buf[2]&0x3F)
+		r, least = rune(buf[0]&0x0F)<<12|rune(buf[1]&0x3F)<<6|rune(buf[2]&0x3F), 0x800
 	case 4:
-		return rune(buf[0]&0x07)<<18 | rune(buf[1]&0x3F)<<12 | rune(buf[2]&0x3F)<<6 | rune(buf[3]&0x3F)
+		r, least = rune(buf[0]&0x07)<<18|rune(buf[1]&0x3F)<<12|rune(buf[2]&0x3F)<<6|rune(buf[3]&0x3F), 0x10000
 	default:
 		return 0xFFFD
 	}
+	// Overlong forms are invalid; this also keeps an encoded NUL
+	// (C0 80) from reaching the screen as a cell
+	if r < least || r > 0x10FFFF {
+		return 0xFFFD
+	}
+	return r
 }
 
 func (p *Parser) handleGround(b byte) {
 	switch b {
-	case 0x00: // NUL - ignore
+	case 0x00, 0x7F: // NUL, DEL - padding, ignore
 	case 0x07: // BEL - bell (ignore for now)
 	case 0x08: // BS - backspace
 		p.buffer.Backspace()
@@ -158,12 +333,16 @@ func (p *Parser) handleGround(b byte) {
 		p.buffer.LineFeed()
 	case 0x0D: // CR - carriage return
 		p.buffer.CarriageReturn()
+	case 0x0E: // SO - shift out (invoke G1)
+		p.shiftOut = true
+	case 0x0F: // SI - shift in (invoke G0)
+		p.shiftOut = false
 	case 0x1B: // ESC
 		p.state = stateEscape
 	default:
 		if b >= 0x20 && b < 0x7F {
-			// Printable ASCII
-			p.buffer.WriteChar(rune(b))
+			// Printable ASCII, mapped through the active character set
+			p.buffer.WriteChar(translateCharset(p.graphicCharset(), b))
 		}
 	}
 }
@@ -173,15 +352,38 @@ func (p *Parser) handleEscape(b byte) {
 	case '[': // CSI - Control Sequence Introducer
 		p.state = stateCSI
 		p.csiParams = p.csiParams[:0]
-		p.csiRawParams = p.csiRawParams[:0]
+		p.csiSubs = p.csiSubs[:0]
+		p.csiSubStore = p.csiSubStore[:0]
 		p.csiPrivate = 0
 		p.csiIntermediate = 0
-		p.csiBuf.Reset()
+		p.csiMalformed = false
+		p.csiBuf = p.csiBuf[:0]
 	case ']': // OSC - Operating System Command
 		p.state = stateOSC
 		p.oscBuf.Reset()
-	case '(', ')': // Character set designation
+		p.stringLen = 0
+	case 'P': // DCS - Device Control String
+		p.state = stateDCS
+		p.dcsBuf = p.dcsBuf[:0]
+		p.stringLen = 0
+	case '(': // SCS - designate G0 character set
+		p.charsetSlot = 0
+		p.state = stateCharset
+	case ')': // SCS - designate G1 character set
+		p.charsetSlot = 1
 		p.state = stateCharset
+	case '*': // SCS - designate G2 character set
+		p.charsetSlot = 2
+		p.state = stateCharset
+	case '+': // SCS - designate G3 character set
+		p.charsetSlot = 3
+		p.state = stateCharset
+	case 'N': // SS2 - Single Shift 2 (G2 for the next character)
+		p.singleShift = 2
+		p.state = stateGround
+	case 'O': // SS3 - Single Shift 3 (G3 for the next character)
+		p.singleShift = 3
+		p.state = stateGround
 	case '#': // DEC line attribute commands (DECDHL, DECDWL, DECSWL, DECALN)
 		p.state = stateDECLineAttr
 	case '7': // DECSC - Save Cursor
@@ -191,30 +393,19 @@ func (p *Parser) handleEscape(b byte) {
 		p.buffer.RestoreCursor()
 		p.state = stateGround
 	case 'c': // RIS - Reset to Initial State
+		p.resetCharsets()
 		p.buffer.ClearScreen()
 		p.buffer.SetCursor(0, 0)
 		p.buffer.ResetAttributes()
 		p.state = stateGround
-	case 'D': // IND - Index (move down one line, scroll if needed)
-		_, rows := p.buffer.GetSize()
-		_, y := p.buffer.GetCursor()
-		if y >= rows-1 {
-			p.buffer.ScrollUp(1)
-		} else {
-			p.buffer.MoveCursorDown(1)
-		}
+	case 'D': // IND - Index (move down one line, scroll region if needed)
+		p.buffer.Index()
 		p.state = stateGround
 	case 'E': // NEL - Next Line
-		p.buffer.CarriageReturn()
-		p.buffer.LineFeed()
+		p.buffer.NextLine()
 		p.state = stateGround
-	case 'M': // RI - Reverse Index (move up one line, scroll if needed)
-		_, y := p.buffer.GetCursor()
-		if y == 0 {
-			p.buffer.ScrollDown(1)
-		} else {
-			p.buffer.MoveCursorUp(1)
-		}
+	case 'M': // RI - Reverse Index (move up one line, scroll region if needed)
+		p.buffer.ReverseIndex()
 		p.state = stateGround
 	case '=': // DECKPAM - Keypad Application Mode
 		p.state = stateGround
@@ -258,8 +449,9 @@ func (p *Parser) handleDECLineAttr(b byte) {
 
 func (p *Parser) handleCSI(b byte) {
 	if p.state == stateCSI {
-		// First byte after ESC [
-		if b == '?' || b == '>' || b == '!' || b == '<' {
+		// First byte after ESC [: a private marker ('<', '=', '>' or '?').
+		// '!', '$', SP and the like are intermediates, read before the final.
+		if b >= 0x3C && b <= 0x3F {
 			p.csiPrivate = b
 			p.state = stateCSIParam
 			return
@@ -267,27 +459,39 @@ func (p *Parser) handleCSI(b byte) {
 		p.state = stateCSIParam
 	}
 
+	// A parameter byte after an intermediate, or a private marker past the
+	// first byte, is out of order (ECMA-48 5.4): the sequence is read to its
+	// final byte and then dropped rather than run with garbled parameters
+	if b >= 0x30 && b <= 0x3F && (p.csiIntermediate != 0 || b >= 0x3C) {
+		p.csiMalformed = true
+		return
+	}
+
 	// Collect parameter bytes
 	if b >= '0' && b <= '9' {
-		p.csiBuf.WriteByte(b)
+		p.csiBuf = append(p.csiBuf, b)
 		return
 	}
 
 	if b == ';' {
 		// Parameter separator
 		p.parseCSIParam()
-		p.csiBuf.Reset()
+		p.csiBuf = p.csiBuf[:0]
 		return
 	}
 
 	if b == ':' {
 		// Sub-parameter separator (used in some SGR sequences)
-		p.csiBuf.WriteByte(b)
+		p.csiBuf = append(p.csiBuf, b)
 		return
 	}
 
 	// Intermediate bytes (0x20-0x2F) - used in sequences like DECSCUSR (ESC [ Ps SP q)
 	if b >= 0x20 && b <= 0x2F {
+		if p.csiIntermediate != 0 {
+			p.csiMalformed = true // No control we know takes two
+			return
+		}
 		p.parseCSIParam() // Parse any parameter before the intermediate
 		p.csiIntermediate = b
 		return
@@ -295,46 +499,81 @@ func (p *Parser) handleCSI(b byte) {
 
 	// Final byte - execute the sequence
 	p.parseCSIParam() // Parse any remaining parameter
-	p.executeCSI(b)
+	if p.csiMalformed {
+		p.reportUnknownCSI(b)
+	} else {
+		p.executeCSI(b)
+	}
 	p.state = stateGround
 }
 
-func (p *Parser) parseCSIParam() {
-	s := p.csiBuf.String()
-	if s == "" {
-		p.csiParams = append(p.csiParams, 0) // Default value
-		p.csiRawParams = append(p.csiRawParams, "")
-	} else {
-		// Store raw string for subparameter parsing
-		p.csiRawParams = append(p.csiRawParams, s)
-		// For legacy int params, extract base value (before any colon)
-		base := s
-		if colonIdx := strings.IndexByte(s, ':'); colonIdx >= 0 {
-			base = s[:colonIdx]
-		}
-		n, _ := strconv.Atoi(base)
-		p.csiParams = append(p.csiParams, n)
+// reportUnknownCSI tells the unknown-sequence callback about a CSI sequence
+// that is not run, naming it by its marker, intermediate and final byte
+// (e.g. "CSI > m" or "CSI SP q")
+func (p *Parser) reportUnknownCSI(finalByte byte) {
+	if p.onUnknown == nil {
+		return
 	}
+	seq := "CSI"
+	if p.csiPrivate != 0 {
+		seq += " " + string(rune(p.csiPrivate))
+	}
+	switch {
+	case p.csiIntermediate == ' ':
+		seq += " SP"
+	case p.csiIntermediate != 0:
+		seq += " " + string(rune(p.csiIntermediate))
+	}
+	p.onUnknown(seq + " " + string(rune(finalByte)))
 }
 
-// parseSGRParam parses a raw parameter string into an SGRParam with subparameters
-func parseSGRParam(raw string) SGRParam {
-	if raw == "" {
-		return SGRParam{Base: 0}
+func (p *Parser) parseCSIParam() {
+	// For legacy int params, the base value is the part before any colon
+	s := p.csiBuf
+	base := s
+	colonIdx := bytes.IndexByte(s, ':')
+	if colonIdx >= 0 {
+		base = s[:colonIdx]
 	}
-	parts := strings.Split(raw, ":")
-	base, _ := strconv.Atoi(parts[0])
-	var subs []int
-	for i := 1; i < len(parts); i++ {
-		if parts[i] == "" {
+	p.csiParams = append(p.csiParams, atoiDigits(base)) // Empty is the default 0
+
+	// Subparameters go to the shared store, so no per-sequence allocation
+	start := len(p.csiSubStore)
+	for colonIdx >= 0 {
+		s = s[colonIdx+1:]
+		part := s
+		colonIdx = bytes.IndexByte(s, ':')
+		if colonIdx >= 0 {
+			part = s[:colonIdx]
+		}
+		if len(part) == 0 {
 			// Empty subparameter (e.g., "58:2::255:0:0" has empty colorspace)
-			subs = append(subs, -1) // Use -1 to indicate empty/default
+			p.csiSubStore = append(p.csiSubStore, -1) // Use -1 to indicate empty/default
 		} else {
-			n, _ := strconv.Atoi(parts[i])
-			subs = append(subs, n)
+			p.csiSubStore = append(p.csiSubStore, atoiDigits(part))
+		}
+	}
+	p.csiSubs = append(p.csiSubs, [2]int{start, len(p.csiSubStore)})
+}
+
+// atoiDigits converts a run of ASCII digits to an int, saturating instead
+// of overflowing on absurdly long parameters
+func atoiDigits(digits []byte) int {
+	n := 0
+	for _, c := range digits {
+		if n > (1<<31-1)/10 {
+			return 1<<31 - 1
 		}
+		n = n*10 + int(c-'0')
 	}
-	return SGRParam{Base: base, Subs: subs}
+	return n
+}
+
+// sgrParam returns CSI parameter idx with its subparameters. Subs aliases
+// parser storage and is only valid until the next sequence is parsed.
+func (p *Parser) sgrParam(idx int) SGRParam {
+	r := p.csiSubs[idx]
+	return SGRParam{Base: p.csiParams[idx], Subs: p.csiSubStore[r[0]:r[1]]}
 }
 
 func (p *Parser) getParam(idx, defaultVal int) int {
@@ -345,6 +584,20 @@ func (p *Parser) getParam(idx, defaultVal int) int {
 }
 
 func (p *Parser) executeCSI(finalByte byte) {
+	// An intermediate makes a different control of the same final byte
+	// (SP q is DECSCUSR, ! p is DECSTR, $ p is DECRQM), never the plain one
+	if p.csiIntermediate != 0 {
+		p.executeCSIIntermediate(finalByte)
+		return
+	}
+	// '<', '=' and '>' mark extensions we don't implement (CSI > 4 ; 1 m is
+	// xterm's modifyOtherKeys, not underline and bold); '?' (DEC private)
+	// is checked per final byte below
+	if p.csiPrivate != 0 && p.csiPrivate != '?' {
+		p.reportUnknownCSI(finalByte)
+		return
+	}
+
 	switch finalByte {
 	case 'A': // CUU - Cursor Up
 		p.buffer.MoveCursorUp(p.getParam(0, 1))
@@ -366,13 +619,14 @@ func (p *Parser) executeCSI(finalByte byte) {
 		p.buffer.MoveCursorUp(p.getParam(0, 1))
 		p.buffer.CarriageReturn()
 
-	case 'G': // CHA - Cursor Horizontal Absolute
-		x := p.getParam(0, 1) - 1 // 1-indexed to 0-indexed
-		_, y := p.buffer.GetCursor()
-		p.buffer.SetCursorVisual(x, y)
+	case 'G', '`': // CHA/HPA - Cursor Horizontal (Position) Absolute
+		p.buffer.SetCursorColumn(p.getParam(0, 1) - 1) // 1-indexed to 0-indexed
+
+	case 'a': // HPR - Horizontal Position Relative
+		p.buffer.MoveCursorForwardVisual(p.getParam(0, 1))
 
 	case 'H', 'f': // CUP/HVP - Cursor Position
-		row := p.getParam(0, 1) - 1
+		row := p.buffer.originRow(p.getParam(0, 1) - 1)
 		col := p.getParam(1, 1) - 1
 		p.buffer.SetCursorVisual(col, row)
 
@@ -382,9 +636,10 @@ func (p *Parser) executeCSI(finalByte byte) {
 			p.buffer.ClearToEndOfScreen()
 		case 1:
 			p.buffer.ClearToStartOfScreen()
-		case 2, 3:
-			p.buffer.ClearScreen()
-			p.buffer.SetCursor(0, 0)
+		case 2:
+			p.buffer.EraseScreen(false) // The cursor stays put, as on a VT100
+		case 3: // xterm: erase saved lines, leaving the screen alone
+			p.buffer.ClearScrollback()
 		}
 
 	case 'K': // EL - Erase in Line
@@ -419,9 +674,10 @@ func (p *Parser) executeCSI(finalByte byte) {
 		p.buffer.ScrollDown(p.getParam(0, 1))
 
 	case 'd': // VPA - Vertical Position Absolute
-		y := p.getParam(0, 1) - 1
-		x, _ := p.buffer.GetCursor()
-		p.buffer.SetCursor(x, y)
+		p.buffer.SetCursorRow(p.buffer.originRow(p.getParam(0, 1) - 1))
+
+	case 'e': // VPR - Vertical Position Relative
+		p.buffer.MoveCursorDown(p.getParam(0, 1))
 
 	case 'm': // SGR - Select Graphic Rendition
 		p.executeSGR()
@@ -429,11 +685,15 @@ func (p *Parser) executeCSI(finalByte byte) {
 	case 'h': // SM - Set Mode
 		if p.csiPrivate == '?' {
 			p.executePrivateModeSet(true)
+		} else if p.csiPrivate == 0 {
+			p.executeModeSet(true)
 		}
 
 	case 'l': // RM - Reset Mode
 		if p.csiPrivate == '?' {
 			p.executePrivateModeSet(false)
+		} else if p.csiPrivate == 0 {
+			p.executeModeSet(false)
 		}
 
 	case 's': // SCP - Save Cursor Position
@@ -446,18 +706,43 @@ func (p *Parser) executeCSI(finalByte byte) {
 		// Would need to send response - ignore for now
 
 	case 'r': // DECSTBM - Set Top and Bottom Margins
-		// Scroll region - not yet implemented
+		if p.csiPrivate == 0 {
+			_, rows := p.buffer.GetLogicalSize()
+			if rows == 0 {
+				_, rows = p.buffer.GetSize()
+			}
+			top := p.getParam(0, 1)
+			bottom := p.getParam(1, rows)
+			p.buffer.SetScrollRegion(top-1, bottom-1)
+			p.buffer.SetCursor(0, p.buffer.originRow(0)) // Home (the top margin under DECOM)
+		}
 
 	case 'c': // DA - Device Attributes
 		// Would need to send response - ignore
 
 	case 't': // Window manipulation
 		p.executeWindowManipulation()
+	}
+}
 
-	case 'q': // DECSCUSR - Set Cursor Style (with space intermediate)
-		if p.csiIntermediate == ' ' {
-			p.executeDECSCUSR()
-		}
+// executeCSIIntermediate runs CSI sequences carrying an intermediate byte
+func (p *Parser) executeCSIIntermediate(finalByte byte) {
+	switch {
+	case p.csiIntermediate == ' ' && finalByte == 'q' && p.csiPrivate == 0:
+		// DECSCUSR - Set Cursor Style
+		p.executeDECSCUSR()
+
+	case p.csiIntermediate == '!' && finalByte == 'p' && p.csiPrivate == 0:
+		// DECSTR - Soft Terminal Reset
+		p.resetCharsets()
+		p.buffer.SoftReset()
+
+	case p.csiIntermediate == '$' && finalByte == 'p' && (p.csiPrivate == 0 || p.csiPrivate == '?'):
+		// DECRQM - Request Mode (ANSI, or DEC private with ?)
+		p.executeDECRQM(p.csiPrivate == '?')
+
+	default:
+		p.reportUnknownCSI(finalByte)
 	}
 }
 
@@ -522,22 +807,28 @@ func (p *Parser) executeDECSCUSR() {
 	// Ps = 4: Steady underline
 	// Ps = 5: Blinking bar
 	// Ps = 6: Steady bar
+	//
+	// Blinking styles blink slow, or stay fast if DECSET 12 already chose
+	// fast: DECSCUSR says whether the cursor blinks, not how quickly.
 	var shape, blink int
 	switch style {
 	case 0, 1: // Blinking block (default)
-		shape, blink = 0, 1
+		shape, blink = 0, CursorBlinkSlow
 	case 2: // Steady block
-		shape, blink = 0, 0
+		shape, blink = 0, CursorBlinkNone
 	case 3: // Blinking underline
-		shape, blink = 1, 1
+		shape, blink = 1, CursorBlinkSlow
 	case 4: // Steady underline
-		shape, blink = 1, 0
+		shape, blink = 1, CursorBlinkNone
 	case 5: // Blinking bar
-		shape, blink = 2, 1
+		shape, blink = 2, CursorBlinkSlow
 	case 6: // Steady bar
-		shape, blink = 2, 0
+		shape, blink = 2, CursorBlinkNone
 	default:
-		shape, blink = 0, 1 // Default to blinking block
+		shape, blink = 0, CursorBlinkSlow // Default to blinking block
+	}
+	if _, current := p.buffer.GetCursorStyle(); blink == CursorBlinkSlow && current == CursorBlinkFast {
+		blink = CursorBlinkFast
 	}
 	p.buffer.SetCursorStyle(shape, blink)
 }
@@ -556,14 +847,14 @@ func (p *Parser) executeSGR() {
 			p.buffer.ResetAttributes()
 		case 1: // Bold
 			p.buffer.SetBold(true)
-		case 2: // Dim (treat as not bold)
-			p.buffer.SetBold(false)
+		case 2: // Faint (dim)
+			p.buffer.SetFaint(true)
 		case 3: // Italic
 			p.buffer.SetItalic(true)
 		case 4: // Underline (with optional subparameter for style)
 			// Check for subparameters: 4:0=off, 4:1=single, 4:2=double, 4:3=curly, 4:4=dotted, 4:5=dashed
-			if i < len(p.csiRawParams) {
-				sgr := parseSGRParam(p.csiRawParams[i])
+			if i < len(p.csiSubs) {
+				sgr := p.sgrParam(i)
 				if len(sgr.Subs) > 0 {
 					switch sgr.Subs[0] {
 					case 0:
@@ -588,10 +879,14 @@ func (p *Parser) executeSGR() {
 			} else {
 				p.buffer.SetUnderlineStyle(UnderlineSingle)
 			}
-		case 5, 6: // Blink (slow=5, rapid=6) - rendered as bobbing wave animation
+		case 5: // Slow blink - rendered as bobbing wave animation
 			p.buffer.SetBlink(true)
+		case 6: // Rapid blink - same animation at twice the rate
+			p.buffer.SetBlinkRapid(true)
 		case 7: // Reverse video
 			p.buffer.SetReverse(true)
+		case 8: // Conceal (hidden)
+			p.buffer.SetConceal(true)
 		case 9: // Strikethrough
 			p.buffer.SetStrikethrough(true)
 		case 10: // Primary font (font slot 0)
@@ -600,20 +895,27 @@ func (p *Parser) executeSGR() {
 			p.buffer.SetFont(int(param) - 10)
 		case 20: // Fraktur / gothic (font slot 10)
 			p.buffer.SetFont(10)
-		case 21: // Bold off (double underline in some terminals)
-			p.buffer.SetBold(false)
-		case 22: // Normal intensity
+		case 21: // Double underline (ECMA-48; not bold off)
+			p.buffer.SetUnderlineStyle(UnderlineDouble)
+		case 22: // Normal intensity (neither bold nor faint)
 			p.buffer.SetBold(false)
+			p.buffer.SetFaint(false)
 		case 23: // Italic off
 			p.buffer.SetItalic(false)
 		case 24: // Underline off
 			p.buffer.SetUnderlineStyle(UnderlineNone)
-		case 25: // Blink off
+		case 25: // Blink off (slow and rapid)
 			p.buffer.SetBlink(false)
 		case 27: // Reverse off
 			p.buffer.SetReverse(false)
+		case 28: // Conceal off (reveal)
+			p.buffer.SetConceal(false)
 		case 29: // Strikethrough off
 			p.buffer.SetStrikethrough(false)
+		case 53: // Overline
+			p.buffer.SetOverline(true)
+		case 55: // Overline off
+			p.buffer.SetOverline(false)
 
 		// Foreground colors (30-37)
 		case 30, 31, 32, 33, 34, 35, 36, 37:
@@ -633,8 +935,8 @@ func (p *Parser) executeSGR() {
 
 		case 38: // Extended foreground color
 			// Check for subparameter format first: 38:5:N or 38:2::R:G:B
-			if i < len(p.csiRawParams) {
-				sgr := parseSGRParam(p.csiRawParams[i])
+			if i < len(p.csiSubs) {
+				sgr := p.sgrParam(i)
 				if len(sgr.Subs) >= 2 && sgr.Subs[0] == 5 {
 					// Subparam format: 38:5:N
 					p.buffer.SetForeground(PaletteColor(sgr.Subs[1]))
@@ -681,8 +983,8 @@ func (p *Parser) executeSGR() {
 
 		case 48: // Extended background color
 			// Check for subparameter format first: 48:5:N or 48:2::R:G:B
-			if i < len(p.csiRawParams) {
-				sgr := parseSGRParam(p.csiRawParams[i])
+			if i < len(p.csiSubs) {
+				sgr := p.sgrParam(i)
 				if len(sgr.Subs) >= 2 && sgr.Subs[0] == 5 {
 					// Subparam format: 48:5:N
 					p.buffer.SetBackground(PaletteColor(sgr.Subs[1]))
@@ -727,8 +1029,8 @@ func (p *Parser) executeSGR() {
 
 		case 58: // Underline color
 			// Check for subparameter format: 58:5:N or 58:2::R:G:B
-			if i < len(p.csiRawParams) {
-				sgr := parseSGRParam(p.csiRawParams[i])
+			if i < len(p.csiSubs) {
+				sgr := p.sgrParam(i)
 				if len(sgr.Subs) >= 2 && sgr.Subs[0] == 5 {
 					// Subparam format: 58:5:N (256-color)
 					p.buffer.SetUnderlineColor(PaletteColor(sgr.Subs[1]))
@@ -741,7 +1043,31 @@ func (p *Parser) executeSGR() {
 						r, g, b = sgr.Subs[1], sgr.Subs[2], sgr.Subs[3]
 					}
 					p.buffer.SetUnderlineColor(TrueColor(uint8(r), uint8(g), uint8(b)))
+				} else if i+2 < len(p.csiParams) && p.csiParams[i+1] == 5 {
+					// Semicolon format: 58;5;N
+					p.buffer.SetUnderlineColor(PaletteColor(p.csiParams[i+2]))
+					i += 2
+				} else if i+4 < len(p.csiParams) && p.csiParams[i+1] == 2 {
+					// Semicolon format: 58;2;R;G;B
+					p.buffer.SetUnderlineColor(TrueColor(
+						uint8(p.csiParams[i+2]),
+						uint8(p.csiParams[i+3]),
+						uint8(p.csiParams[i+4]),
+					))
+					i += 4
 				}
+			} else if i+2 < len(p.csiParams) && p.csiParams[i+1] == 5 {
+				// Fallback semicolon format: 58;5;N
+				p.buffer.SetUnderlineColor(PaletteColor(p.csiParams[i+2]))
+				i += 2
+			} else if i+4 < len(p.csiParams) && p.csiParams[i+1] == 2 {
+				// Fallback semicolon format: 58;2;R;G;B
+				p.buffer.SetUnderlineColor(TrueColor(
+					uint8(p.csiParams[i+2]),
+					uint8(p.csiParams[i+3]),
+					uint8(p.csiParams[i+4]),
+				))
+				i += 4
 			}
 
 		case 59: // Reset underline color (use foreground color)
@@ -770,18 +1096,36 @@ func (p *Parser) executeSGR() {
 	}
 }
 
+// executeModeSet handles ANSI (non-private) SM/RM modes
+func (p *Parser) executeModeSet(set bool) {
+	for _, param := range p.csiParams {
+		switch param {
+		case 20: // LNM - Line feed/new line mode
+			p.buffer.SetNewlineMode(set)
+		}
+	}
+}
+
 func (p *Parser) executePrivateModeSet(set bool) {
 	for _, param := range p.csiParams {
 		switch param {
 		case 3: // DECCOLM - 132 Column Mode (horizontal scale 0.6060)
 			p.buffer.Set132ColumnMode(set)
 		case 5: // DECSCNM - Screen Mode (reverse video)
-			// h = reverse video (light mode), l = normal video (dark mode)
-			p.buffer.SetDarkTheme(!set)
+			// h = default colors swapped, l = normal video
+			p.buffer.SetReverseScreen(set)
+		case 6: // DECOM - Origin mode (rows relative to the scroll region)
+			p.buffer.SetOriginMode(set)
 		case 25: // DECTCEM - Cursor visibility
 			p.buffer.SetCursorVisible(set)
 		case 1049: // Alternate screen buffer
 			// Not yet implemented
+		case 9: // X10 Mouse Tracking (button press only)
+			if set {
+				p.buffer.SetMouseTrackingMode(9)
+			} else {
+				p.buffer.SetMouseTrackingMode(0)
+			}
 		case 1000: // X11 Normal Mouse Tracking (button press/release)
 			if set {
 				p.buffer.SetMouseTrackingMode(1000)
@@ -800,19 +1144,23 @@ func (p *Parser) executePrivateModeSet(set bool) {
 			} else {
 				p.buffer.SetMouseTrackingMode(0)
 			}
-		case 1006: // SGR Extended Mouse Encoding
+		case 1006, 1015: // SGR / urxvt Extended Mouse Encoding
 			if set {
-				p.buffer.SetMouseEncodingMode(1006)
-			} else {
+				p.buffer.SetMouseEncodingMode(param)
+			} else if p.buffer.GetMouseEncodingMode() == param {
 				p.buffer.SetMouseEncodingMode(0)
 			}
+		case 1004: // Focus in/out reporting
+			p.buffer.SetFocusReporting(set)
 		case 2004: // Bracketed paste mode
 			p.buffer.SetBracketedPasteMode(set)
+		case 2026: // Synchronized output (hold redraws until the frame ends)
+			p.buffer.SetSynchronizedOutput(set)
 		case 2027: // terminal-wg grapheme clustering: accepted, inherently satisfied.
 			// PurfecTerm always clusters combining marks (appendCombiningMark) and
 			// the default STANDARD contract already advances the cursor by visual
 			// column width — exactly what a mode-2027 probe asks for. There is no
-			// state to toggle; report it set under DECRQM when that lands. Flex
+			// state to toggle; DECRQM reports it permanently set. Flex
 			// mode moved to the private ?7027 to avoid colliding with this.
 		case 7027: // PurfecTerm: Flexible East Asian Width mode (Contract B opt-in)
 			p.buffer.SetFlexWidthMode(set)
@@ -844,9 +1192,9 @@ func (p *Parser) executePrivateModeSet(set bool) {
 		case 12: // Cursor blink rate: h=fast, l=slow
 			shape, _ := p.buffer.GetCursorStyle()
 			if set {
-				p.buffer.SetCursorStyle(shape, 2) // Fast blink
+				p.buffer.SetCursorStyle(shape, CursorBlinkFast)
 			} else {
-				p.buffer.SetCursorStyle(shape, 1) // Slow blink
+				p.buffer.SetCursorStyle(shape, CursorBlinkSlow)
 			}
 		case 7700: // PurfecTerm: Disable scrollback buffer (for games)
 			// h = disable scrollback accumulation, l = re-enable
@@ -864,6 +1212,10 @@ func (p *Parser) executePrivateModeSet(set bool) {
 
 func (p *Parser) handleOSC(b byte) {
 	if b >= '0' && b <= '9' {
+		if p.stringLen++; p.stringLen > p.maxString {
+			p.abandonString("OSC")
+			return
+		}
 		p.oscBuf.WriteByte(b)
 		return
 	}
@@ -875,6 +1227,14 @@ func (p *Parser) handleOSC(b byte) {
 		p.state = stateOSCString
 		return
 	}
+	if (b == 0x07 || b == 0x1B) && p.oscBuf.Len() > 0 {
+		// A command with no arguments (e.g. OSC 104 or OSC 110)
+		p.oscCmd, _ = strconv.Atoi(p.oscBuf.String())
+		p.oscBuf.Reset()
+		p.state = stateOSCString
+		p.handleOSCString(b)
+		return
+	}
 	// Invalid OSC, return to ground
 	p.state = stateGround
 }
@@ -887,7 +1247,11 @@ func (p *Parser) handleOSCString(b byte) {
 	}
 	if b == 0x1B { // ESC might start ST (ESC \)
 		p.executeOSC()
-		p.state = stateGround
+		p.state = stateEscape // The '\' of ST then ends the escape unprinted
+		return
+	}
+	if p.stringLen++; p.stringLen > p.maxString {
+		p.abandonString("OSC " + strconv.Itoa(p.oscCmd))
 		return
 	}
 	p.oscBuf.WriteByte(b)
@@ -898,6 +1262,16 @@ func (p *Parser) executeOSC() {
 	args := p.oscBuf.String()
 
 	switch p.oscCmd {
+	case 0, 2: // Set icon name and window title / set window title
+		p.buffer.SetTitle(args)
+	case 4: // Set palette colors: 4;index;spec[;index;spec...]
+		p.executeOSCSetPalette(args)
+	case 10, 11, 12: // Set foreground, background, cursor color
+		p.executeOSCSetDynamic(DynamicColor(p.oscCmd-10), args)
+	case 104: // Reset palette colors (all when no indexes are given)
+		p.executeOSCResetPalette(args)
+	case 110, 111, 112: // Reset foreground, background, cursor color
+		p.buffer.ResetDynamicColor(DynamicColor(p.oscCmd - 110))
 	case 7000: // Palette management
 		p.executeOSCPalette(args)
 	case 7001: // Glyph management
@@ -910,8 +1284,204 @@ func (p *Parser) executeOSC() {
 		p.executeOSCScriptFont(args)
 	case 7003: // Screen crop and splits
 		p.executeOSCScreenCrop(args)
-	// Other OSC commands (title, etc.) could be added here
+	case 9999: // Header comment (e.g. from a saved session); not displayed
+		p.buffer.reportComment(args)
+	// Other OSC commands could be added here
+	}
+}
+
+// executeOSCSetPalette applies OSC 4's index/color pairs for indexes
+// 0-255. Queries ("?"), other indexes and colors that don't parse are
+// skipped.
+func (p *Parser) executeOSCSetPalette(args string) {
+	fields := strings.Split(args, ";")
+	for i := 0; i+1 < len(fields); i += 2 {
+		index, err := strconv.Atoi(fields[i])
+		if err != nil {
+			continue
+		}
+		if c, ok := ParseXColor(fields[i+1]); ok {
+			p.buffer.SetPaletteColor(index, c)
+		}
+	}
+}
+
+// executeOSCSetDynamic applies OSC 10/11/12. As in xterm, further colors
+// after the first set the next dynamic colors in turn (OSC 10;fg;bg).
+func (p *Parser) executeOSCSetDynamic(which DynamicColor, args string) {
+	for _, spec := range strings.Split(args, ";") {
+		if which > DynamicCursor {
+			return
+		}
+		if c, ok := ParseXColor(spec); ok {
+			p.buffer.SetDynamicColor(which, c)
+		}
+		which++
+	}
+}
+
+// executeOSCResetPalette applies OSC 104: reset the listed indexes, or the
+// whole palette when there are none
+func (p *Parser) executeOSCResetPalette(args string) {
+	var indexes []int
+	for _, field := range strings.Split(args, ";") {
+		if index, err := strconv.Atoi(field); err == nil {
+			indexes = append(indexes, index)
+		}
+	}
+	p.buffer.ResetPaletteColors(indexes...)
+}
+
+// maxDCSLen bounds the DCS bytes kept; longer strings (sixel, ReGIS) are
+// consumed but not understood
+const maxDCSLen = 64
+
+func (p *Parser) handleDCS(b byte) {
+	switch b {
+	case 0x1B: // ESC starts ST (ESC \)
+		p.executeDCS()
+		p.state = stateEscape // The '\' of ST then ends the escape unprinted
+	case 0x18, 0x1A: // CAN, SUB abort the string
+		p.state = stateGround
+	default:
+		if p.stringLen++; p.stringLen > p.maxString {
+			p.abandonString("DCS")
+			return
+		}
+		if len(p.dcsBuf) < maxDCSLen {
+			p.dcsBuf = append(p.dcsBuf, b)
+		}
+	}
+}
+
+// abandonString drops the OSC/DCS string being read, which has grown past
+// the maximum string length, and skips the rest of it
+func (p *Parser) abandonString(seq string) {
+	p.oscBuf.Reset()
+	p.dcsBuf = p.dcsBuf[:0]
+	p.state = stateStringIgnore
+	if p.onUnknown != nil {
+		p.onUnknown(seq)
+	}
+}
+
+// handleStringIgnore discards an abandoned string up to its terminator
+func (p *Parser) handleStringIgnore(b byte) {
+	switch b {
+	case 0x07, 0x18, 0x1A: // BEL (OSC), CAN and SUB end it
+		p.state = stateGround
+	case 0x1B: // ESC starts ST (ESC \)
+		p.state = stateEscape
+	}
+}
+
+// executeDCS processes a complete DCS string
+func (p *Parser) executeDCS() {
+	if req, ok := bytes.CutPrefix(p.dcsBuf, []byte("$q")); ok {
+		p.executeDECRQSS(string(req))
+	}
+}
+
+// executeDECRQSS answers DECRQSS (DCS $ q Pt ST) for SGR ("m") and the
+// scroll margins ("r") with DCS 1 $ r <setting> ST, or DCS 0 $ r ST when
+// the setting isn't one we report
+func (p *Parser) executeDECRQSS(req string) {
+	if p.response == nil {
+		return
+	}
+	var reply string
+	switch req {
+	case "m":
+		reply = "\x1bP1$r" + p.buffer.CurrentSGR() + "m\x1b\\"
+	case "r":
+		top, bottom := p.buffer.GetScrollRegion()
+		reply = "\x1bP1$r" + strconv.Itoa(top+1) + ";" + strconv.Itoa(bottom+1) + "r\x1b\\"
+	default:
+		reply = "\x1bP0$r\x1b\\"
+	}
+	p.response.Write([]byte(reply))
+}
+
+// executeDECRQM answers DECRQM (CSI Ps $ p, or CSI ? Ps $ p for a DEC
+// private mode) with DECRPM, CSI [?] Ps ; Pm $ y, where Pm is 1 set,
+// 2 reset, 3 permanently set, or 0 for a mode we don't recognize
+func (p *Parser) executeDECRQM(private bool) {
+	if p.response == nil {
+		return
+	}
+	mode := p.getParam(0, 0)
+	prefix := ""
+	state := p.ansiModeState(mode)
+	if private {
+		prefix = "?"
+		state = p.privateModeState(mode)
+	}
+	p.response.Write([]byte("\x1b[" + prefix + strconv.Itoa(mode) + ";" + strconv.Itoa(state) + "$y"))
+}
+
+// modeSetting is the DECRPM value for a mode that is on or off
+func modeSetting(on bool) int {
+	if on {
+		return 1
+	}
+	return 2
+}
+
+// ansiModeState returns the DECRPM value of an ANSI (SM/RM) mode
+func (p *Parser) ansiModeState(mode int) int {
+	switch mode {
+	case 20: // LNM
+		return modeSetting(p.buffer.IsNewlineModeEnabled())
+	}
+	return 0
+}
+
+// privateModeState returns the DECRPM value of a DEC private mode, for the
+// modes executePrivateModeSet keeps state for
+func (p *Parser) privateModeState(mode int) int {
+	b := p.buffer
+	switch mode {
+	case 3: // DECCOLM
+		return modeSetting(b.Get132ColumnMode())
+	case 5: // DECSCNM
+		return modeSetting(b.IsReverseScreen())
+	case 6: // DECOM
+		return modeSetting(b.IsOriginMode())
+	case 7: // DECAWM
+		return modeSetting(b.IsAutoWrapModeEnabled())
+	case 12: // Fast cursor blink
+		_, blink := b.GetCursorStyle()
+		return modeSetting(blink == CursorBlinkFast)
+	case 25: // DECTCEM
+		return modeSetting(b.IsCursorVisible())
+	case 9, 1000, 1002, 1003: // Mouse tracking
+		return modeSetting(b.GetMouseTrackingMode() == mode)
+	case 1006, 1015: // Mouse encoding
+		return modeSetting(b.GetMouseEncodingMode() == mode)
+	case 1004: // Focus reporting
+		return modeSetting(b.IsFocusReportingEnabled())
+	case 2004: // Bracketed paste
+		return modeSetting(b.IsBracketedPasteModeEnabled())
+	case 2026: // Synchronized output
+		return modeSetting(b.IsSynchronizedOutput())
+	case 2027: // Grapheme clustering: always on
+		return 3
+	case 7027: // Flexible East Asian Width
+		return modeSetting(b.IsFlexWidthModeEnabled())
+	case 7028: // Visual width wrap
+		return modeSetting(b.IsVisualWidthWrapEnabled())
+	case 7029: // Ambiguous width narrow
+		return modeSetting(b.GetAmbiguousWidthMode() == AmbiguousWidthNarrow)
+	case 7030: // Ambiguous width wide
+		return modeSetting(b.GetAmbiguousWidthMode() == AmbiguousWidthWide)
+	case 7700: // Scrollback disabled
+		return modeSetting(b.IsScrollbackDisabled())
+	case 7701: // Auto-scroll disabled
+		return modeSetting(b.IsAutoScrollDisabled())
+	case 7702: // Smart word wrap
+		return modeSetting(b.IsSmartWordWrapEnabled())
 	}
+	return 0
 }
 
 // executeOSCPalette handles OSC 7000 palette commands
@@ -0,0 +1,83 @@
+package purfecterm
+
+import "testing"
+
+// Writing exactly cols characters leaves the wrap pending: the next
+// printable character wraps, CR LF doesn't make a blank line. In pending
+// wrap mode the cursor sits on the last column meanwhile, as on xterm.
+func TestPendingWrap(t *testing.T) {
+	for _, pending := range []bool{false, true} {
+		b := NewBuffer(10, 5, 0)
+		b.SetPendingWrapMode(pending)
+		p := NewParser(b)
+
+		wantX := 10
+		if pending {
+			wantX = 9
+		}
+		p.Parse([]byte("0123456789"))
+		if x, y := b.GetCursor(); x != wantX || y != 0 || !b.IsWrapPending() {
+			t.Errorf("pending=%v: after cols chars cursor (%d,%d) wrap pending %v, want (%d,0) pending",
+				pending, x, y, b.IsWrapPending(), wantX)
+		}
+		p.Parse([]byte("A"))
+		if x, y := b.GetCursor(); x != 1 || y != 1 || b.GetCell(0, 1).Char != 'A' {
+			t.Errorf("pending=%v: after cols+1 chars cursor (%d,%d), want (1,1) with 'A' wrapped", pending, x, y)
+		}
+
+		p.Parse([]byte("\r\n0123456789\r\nB"))
+		if got := b.GetCell(0, 3).Char; got != 'B' {
+			t.Errorf("pending=%v: full line + CR LF put %q on row 3, want 'B' with no blank line", pending, got)
+		}
+	}
+}
+
+// In pending wrap mode anything but a printable character drops the
+// pending wrap from the last column, as xterm does
+func TestPendingWrapModeMoves(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	b.SetPendingWrapMode(true)
+	p := NewParser(b)
+	for _, tt := range []struct {
+		row  string // CUP row the full line is written on
+		seq  string
+		x, y int
+	}{
+		{"1", "\b", 8, 0},
+		{"2", "\x1b[D", 8, 1},
+		{"3", "\n", 9, 3},
+		{"4", "\x1b[K", 9, 3},
+	} {
+		p.Parse([]byte("\x1b[" + tt.row + ";1H0123456789" + tt.seq))
+		if x, y := b.GetCursor(); x != tt.x || y != tt.y {
+			t.Errorf("%q from a pending wrap: cursor (%d,%d), want (%d,%d)", tt.seq, x, y, tt.x, tt.y)
+		}
+		if b.IsWrapPending() {
+			t.Errorf("%q left the wrap pending", tt.seq)
+		}
+	}
+	// EL from a pending wrap erases the last column, where the cursor is
+	if got := b.GetCell(9, 3).Char; got == '9' {
+		t.Error("CSI K from a pending wrap left the last column")
+	}
+
+	// After LF the next character overwrites the last column, no wrap
+	p.Parse([]byte("\x1b[3;1H0123456789\nZ"))
+	if got := b.GetCell(9, 3).Char; got != 'Z' {
+		t.Errorf("char after LF from a pending wrap: cell (9,3) = %q, want 'Z'", got)
+	}
+	if x, y := b.GetCursor(); x != 9 || y != 3 || !b.IsWrapPending() {
+		t.Errorf("cursor after 'Z' = (%d,%d), want (9,3) with the wrap pending again", x, y)
+	}
+}
+
+// In pending wrap mode a cursor report from a pending wrap names the last
+// column, like GetCursor, not the column past the margin
+func TestPendingWrapReportedPosition(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	b.SetPendingWrapMode(true)
+	NewParser(b).Parse([]byte("0123456789"))
+	if row, col := b.ReportedCursorPosition(); row != 1 || col != 10 {
+		t.Errorf("reported position (%d,%d), want (1,10)", row, col)
+	}
+}
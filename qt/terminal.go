@@ -96,25 +96,30 @@ func New(opts Options) (*Terminal, error) {
 		}
 	})
 
-	// Set resize callback to notify PTY when widget resizes
+	// Set resize callback to notify PTY (TIOCSWINSZ, so the child gets
+	// SIGWINCH) when the widget resizes, then the embedder's callback
 	widget.SetResizeCallback(func(cols, rows int) {
 		t.mu.Lock()
 		pty := t.pty
+		callback := t.resizeCallback
 		t.mu.Unlock()
 		if pty != nil {
 			pty.Resize(cols, rows)
 		}
+		if callback != nil {
+			callback(cols, rows)
+		}
 	})
 
 	return t, nil
 }
 
 // SetResizeCallback sets a callback that's called when the terminal resizes
+// The PTY is resized before the callback runs
 func (t *Terminal) SetResizeCallback(fn func(cols, rows int)) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.resizeCallback = fn
-	t.widget.SetResizeCallback(fn)
 }
 
 // Widget returns the Qt widget
@@ -224,6 +224,7 @@ func NewWidget(cols, rows, scrollbackSize int) *Widget {
 	// Create buffer and parser
 	w.buffer = purfecterm.NewBuffer(cols, rows, scrollbackSize)
 	w.parser = purfecterm.NewParser(w.buffer)
+	w.parser.SetResponseWriter(responseWriter{w})
 
 	// Initialize terminal capabilities (auto-updated on resize)
 	w.termCaps = &purfecterm.TerminalCapabilities{
@@ -307,7 +308,9 @@ func NewWidget(cols, rows, scrollbackSize int) *Widget {
 		w.resizeEvent(event)
 	})
 
-	// Create context menu for right-click
+	// Create context menu for right-click. Copy/Paste/Select All call into
+	// the buffer; the selection itself is built by the mouse handlers through
+	// Buffer.StartSelection/UpdateSelection.
 	w.contextMenu = qt.NewQMenu(w.widget)
 
 	copyAction := w.contextMenu.AddAction("Copy")
@@ -583,8 +586,11 @@ func (w *Widget) updateHorizScrollbar() {
 
 		w.horizScrollbar.Show()
 	} else {
-		// Reset offset and hide scrollbar
-		w.buffer.SetHorizOffset(0)
+		// Reset offset and hide scrollbar (only touch the buffer when
+		// needed: this runs after every paint and SetHorizOffset marks dirty)
+		if w.buffer.GetHorizOffset() > 0 {
+			w.buffer.SetHorizOffset(0)
+		}
 		w.horizScrollbar.SetMaximum(0)
 		w.horizScrollbar.SetValue(0)
 		w.horizScrollbar.Hide()
@@ -601,11 +607,8 @@ func (w *Widget) onBlinkTimer() {
 	// Handle cursor blink timing
 	w.blinkTickCount++
 	_, cursorBlink := w.buffer.GetCursorStyle()
-	if cursorBlink > 0 && w.hasFocus {
-		ticksNeeded := 10
-		if cursorBlink >= 2 {
-			ticksNeeded = 5
-		}
+	// 50ms ticks: 10 per phase for slow blink, 5 for fast, none when steady
+	if ticksNeeded := int(purfecterm.CursorBlinkInterval(cursorBlink).Milliseconds() / 50); ticksNeeded > 0 && w.hasFocus {
 		if w.blinkTickCount >= ticksNeeded {
 			w.blinkTickCount = 0
 			w.cursorBlinkOn = !w.cursorBlinkOn
@@ -881,6 +884,20 @@ func (w *Widget) FeedString(data string) {
 	w.parser.ParseString(data)
 }
 
+// responseWriter passes the parser's query replies to the input callback,
+// so they reach the hosted program like typed input
+type responseWriter struct{ w *Widget }
+
+func (r responseWriter) Write(data []byte) (int, error) {
+	r.w.mu.Lock()
+	onInput := r.w.onInput
+	r.w.mu.Unlock()
+	if onInput != nil {
+		onInput(data)
+	}
+	return len(data), nil
+}
+
 // Clear clears the terminal screen
 func (w *Widget) Clear() {
 	w.buffer.ClearScreen()
@@ -1004,7 +1021,7 @@ func (w *Widget) renderCustomGlyph(painter *qt.QPainter, cell *purfecterm.Cell,
 	// Calculate wave offset for blink bounce mode
 	yOffset := 0.0
 	if cell.Blink && blinkMode == purfecterm.BlinkModeBounce {
-		wavePhase := blinkPhase + float64(cellCol)*0.5
+		wavePhase := cell.BlinkPhase(blinkPhase) + float64(cellCol)*0.5
 		yOffset = math.Sin(wavePhase) * 3.0
 	}
 
@@ -1176,13 +1193,16 @@ func (w *Widget) renderSprite(painter *qt.QPainter, sprite *purfecterm.Sprite, u
 
 			// Apply crop rectangle if specified (relative to logical screen)
 			if cropRect != nil {
-				cropMinX := spriteCoordToPixelsQt(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-				cropMinY := spriteCoordToPixelsQt(cropRect.MinY, unitY, charHeight) + scrollPixelY
-				cropMaxX := spriteCoordToPixelsQt(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-				cropMaxY := spriteCoordToPixelsQt(cropRect.MaxY, unitY, charHeight) + scrollPixelY
+				pixelCrop := purfecterm.CropRectangle{
+					MinX:     spriteCoordToPixelsQt(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+					MinY:     spriteCoordToPixelsQt(cropRect.MinY, unitY, charHeight) + scrollPixelY,
+					MaxX:     spriteCoordToPixelsQt(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+					MaxY:     spriteCoordToPixelsQt(cropRect.MaxY, unitY, charHeight) + scrollPixelY,
+					Inverted: cropRect.Inverted,
+				}
 
-				if pixelX+tileW <= cropMinX || pixelX >= cropMaxX ||
-					pixelY+tileH <= cropMinY || pixelY >= cropMaxY {
+				// Skip if the crop hides the whole tile
+				if pixelCrop.HidesArea(pixelX, pixelY, pixelX+tileW, pixelY+tileH) {
 					continue
 				}
 			}
@@ -1215,19 +1235,30 @@ func (w *Widget) renderSpriteGlyph(painter *qt.QPainter, glyph *purfecterm.Custo
 	pixelH := tileH / float64(glyphH)
 
 	// Calculate crop bounds in pixels if needed (relative to logical screen)
-	var cropMinX, cropMinY, cropMaxX, cropMaxY float64
+	var pixelCrop purfecterm.CropRectangle
 	hasCrop := cropRect != nil
 	if hasCrop {
-		cropMinX = spriteCoordToPixelsQt(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-		cropMinY = spriteCoordToPixelsQt(cropRect.MinY, unitY, charHeight) + scrollPixelY
-		cropMaxX = spriteCoordToPixelsQt(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX
-		cropMaxY = spriteCoordToPixelsQt(cropRect.MaxY, unitY, charHeight) + scrollPixelY
+		pixelCrop = purfecterm.CropRectangle{
+			MinX:     spriteCoordToPixelsQt(cropRect.MinX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+			MinY:     spriteCoordToPixelsQt(cropRect.MinY, unitY, charHeight) + scrollPixelY,
+			MaxX:     spriteCoordToPixelsQt(cropRect.MaxX, unitX, charWidth) + float64(terminalLeftPadding) - scrollPixelX,
+			MaxY:     spriteCoordToPixelsQt(cropRect.MaxY, unitY, charHeight) + scrollPixelY,
+			Inverted: cropRect.Inverted,
+		}
 	}
 
 	// Determine default foreground color for this sprite
 	defaultFg := scheme.Foreground(isDark)
 	defaultBg := scheme.Background(isDark)
 
+	// A translucent sprite is drawn with the color's alpha and without the
+	// seam strips, which would double-blend where they overlap a neighbor
+	alpha := sprite.Opacity()
+	if alpha <= 0 {
+		return
+	}
+	translucent := alpha < 1
+
 	// Render each pixel of the glyph
 	for gy := 0; gy < glyphH; gy++ {
 		for gx := 0; gx < glyphW; gx++ {
@@ -1241,11 +1272,8 @@ func (w *Widget) renderSpriteGlyph(painter *qt.QPainter, glyph *purfecterm.Custo
 			belowNeighborIdx := glyph.GetPixel(gx, gy+1)
 
 			// Apply crop if specified
-			if hasCrop {
-				if px+pixelW <= cropMinX || px >= cropMaxX ||
-					py+pixelH <= cropMinY || py >= cropMaxY {
-					continue
-				}
+			if hasCrop && pixelCrop.HidesArea(px, py, px+pixelW, py+pixelH) {
+				continue
 			}
 
 			// Resolve color using sprite's FGP
@@ -1255,9 +1283,15 @@ func (w *Widget) renderSpriteGlyph(painter *qt.QPainter, glyph *purfecterm.Custo
 			}
 
 			qColor := qt.NewQColor3(int(color.R), int(color.G), int(color.B))
+			if translucent {
+				qColor.SetAlphaF(alpha)
+			}
 
 			// Draw main pixel
 			painter.FillRect5(int(px), int(py), int(pixelW+0.5), int(pixelH+0.5), qColor)
+			if translucent {
+				continue
+			}
 
 			// Draw seam extensions as separate strips (1 screen pixel each)
 			// to prevent hairline gaps without creating corner artifacts
@@ -1388,9 +1422,8 @@ func (w *Widget) renderScreenSplits(painter *qt.QPainter, splits []*purfecterm.S
 
 			painter.Save()
 			painter.SetClipRect2(0, startPixelY, cols*charWidth+terminalLeftPadding, endPixelY-startPixelY)
-			schemeBgSplit := scheme.Background(isDark)
-			bgColor := qt.NewQColor3(int(schemeBgSplit.R), int(schemeBgSplit.G), int(schemeBgSplit.B))
-			painter.FillRect5(0, startPixelY, cols*charWidth+terminalLeftPadding, endPixelY-startPixelY, bgColor)
+			fillBackground(painter, 0, startPixelY, cols*charWidth+terminalLeftPadding, endPixelY-startPixelY,
+				scheme.Background(isDark), w.buffer.IsTransparentBackground())
 			painter.Restore()
 		}
 
@@ -1419,7 +1452,7 @@ func (w *Widget) renderScreenSplits(painter *qt.QPainter, splits []*purfecterm.S
 		painter.SetClipRect2(terminalLeftPadding, startPixelY, cols*charWidth, endPixelY-startPixelY)
 
 		// Get line attribute for this buffer row
-		lineAttr := w.buffer.GetLineAttributeForSplit(rowInSplit, currentSplit.BufferRow)
+		lineAttr := w.buffer.GetLineAttributeForScrolledSplit(rowInSplit, currentSplit.BufferRow, currentSplit.ScrollOffset)
 
 		effectiveCols := cols
 		if lineAttr != purfecterm.LineAttrNormal {
@@ -1427,7 +1460,7 @@ func (w *Widget) renderScreenSplits(painter *qt.QPainter, splits []*purfecterm.S
 		}
 
 		// Get the content length for this row (excluding content before BufferCol)
-		contentLen := w.buffer.GetLineLengthForSplit(rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol)
+		contentLen := w.buffer.GetLineLengthForScrolledSplit(rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset)
 
 		// Determine where to stop rendering:
 		// - At screen edge (effectiveCols)
@@ -1456,7 +1489,7 @@ func (w *Widget) renderScreenSplits(painter *qt.QPainter, splits []*purfecterm.S
 		// will clip the left portion of the first cell when LeftFineScroll > 0
 		// horizOffset accounts for the global horizontal scroll position
 		for screenCol := 0; screenCol < maxRenderCol; screenCol++ {
-			cell := w.buffer.GetCellForSplit(screenCol+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol)
+			cell := w.buffer.GetCellForScrolledSplit(screenCol+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset)
 
 			// Calculate cell position (shifted left by fine scroll)
 			var cellX, cellW int
@@ -1480,24 +1513,23 @@ func (w *Widget) renderScreenSplits(painter *qt.QPainter, splits []*purfecterm.S
 				continue
 			}
 
-			fg := scheme.ResolveColor(cell.Foreground, true, isDark)
-			bg := scheme.ResolveColor(cell.Background, false, isDark)
+			fg, bg := scheme.ResolveCellColors(&cell, isDark)
 
 			// Draw cell background if different from terminal background
-			if bg != scheme.Background(isDark) {
+			if !w.buffer.SkipBackgroundFill(&cell, bg, scheme.Background(isDark)) {
 				bgQColor := qt.NewQColor3(int(bg.R), int(bg.G), int(bg.B))
 				painter.FillRect5(cellX, rowPixelY, cellW, cellH, bgQColor)
 			}
 
 			// Draw character
-			if cell.Char != ' ' && cell.Char != 0 {
+			if cell.Char != ' ' && cell.Char != 0 && !cell.Conceal {
 				// Arabic contextual joining from the neighbor cells (visual order).
 				var leftCh, rightCh rune
 				if screenCol > 0 {
-					leftCh = w.buffer.GetCellForSplit(screenCol-1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol).Char
+					leftCh = w.buffer.GetCellForScrolledSplit(screenCol-1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset).Char
 				}
 				if screenCol+1 < maxRenderCol {
-					rightCh = w.buffer.GetCellForSplit(screenCol+1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol).Char
+					rightCh = w.buffer.GetCellForScrolledSplit(screenCol+1+horizOffset, rowInSplit, currentSplit.BufferRow, currentSplit.BufferCol, currentSplit.ScrollOffset).Char
 				}
 				shapedChar, suppress := purfecterm.ShapeArabicCellVisual(leftCh, cell.Char, rightCh)
 				if !suppress {
@@ -1530,6 +1562,22 @@ func (w *Widget) renderScreenSplits(painter *qt.QPainter, splits []*purfecterm.S
 	return maxSplitContentWidth
 }
 
+// fillBackground paints the terminal background over a rectangle: the
+// scheme color bg, or with a transparent background (see
+// Buffer.SetTransparentBackground) fully clear pixels, so whatever is
+// behind the widget shows through. The host window needs
+// WA_TranslucentBackground for that to be visible.
+func fillBackground(painter *qt.QPainter, x, y, width, height int, bg purfecterm.Color, transparent bool) {
+	if transparent {
+		mode := painter.CompositionMode()
+		painter.SetCompositionMode(qt.QPainter__CompositionMode_Source)
+		painter.FillRect5(x, y, width, height, qt.NewQColor2(qt.Transparent))
+		painter.SetCompositionMode(mode)
+		return
+	}
+	painter.FillRect5(x, y, width, height, qt.NewQColor3(int(bg.R), int(bg.G), int(bg.B)))
+}
+
 func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 	w.mu.Lock()
 	scheme := w.scheme
@@ -1540,6 +1588,7 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 	baseCharAscent := w.charAscent
 	blinkPhase := w.blinkPhase
 	w.mu.Unlock()
+	scheme = w.buffer.EffectiveScheme(scheme)
 
 	// Get current theme mode (dark/light) from buffer's DECSCNM state
 	isDark := w.buffer.IsDarkTheme()
@@ -1580,8 +1629,7 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 
 	// Fill background with theme-appropriate color
 	schemeBg := scheme.Background(isDark)
-	bgColor := qt.NewQColor3(int(schemeBg.R), int(schemeBg.G), int(schemeBg.B))
-	painter.FillRect5(0, 0, w.widget.Width(), w.widget.Height(), bgColor)
+	fillBackground(painter, 0, 0, w.widget.Width(), w.widget.Height(), schemeBg, w.buffer.IsTransparentBackground())
 
 	// Apply screen crop clipping if set (crop values are in sprite coordinate units)
 	widthCrop, heightCrop := w.buffer.GetScreenCrop()
@@ -1645,6 +1693,11 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 			// GetVisibleCell takes screen position and applies horizOffset internally
 			cell := w.buffer.GetVisibleCell(x, y)
 
+			// A wide-char continuation cell is covered by its glyph
+			if cell.Continuation {
+				continue
+			}
+
 			// Calculate this cell's visual width
 			// Standard-mode cells carry real widths too, so key on CellWidth
 			// regardless of the FlexWidth flag.
@@ -1653,8 +1706,7 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 				cellVisualWidth = cell.CellWidth
 			}
 
-			fg := scheme.ResolveColor(cell.Foreground, true, isDark)
-			bg := scheme.ResolveColor(cell.Background, false, isDark)
+			fg, bg := scheme.ResolveCellColors(&cell, isDark)
 
 			// Handle blink
 			blinkVisible := true
@@ -1672,19 +1724,24 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 						}
 					}
 				case purfecterm.BlinkModeBlink:
-					blinkVisible = blinkPhase < 3.14159
+					blinkVisible = cell.BlinkPhase(blinkPhase) < 3.14159
 				}
 			}
 
+			// Search matches are shaded first so a selection stays visible on top
+			if w.buffer.IsCellInSearchHighlight(logicalX, y) {
+				bg = scheme.SearchHighlight
+			}
+
 			// Handle selection (use logicalX for buffer position)
 			if w.buffer.IsInSelection(logicalX, y) {
-				bg = scheme.Selection
+				bg = scheme.SelectionBackground(bg)
 			}
 
 			// Handle cursor (compare against logical position)
 			isCursor := cursorVisible && x == cursorVisibleX && y == cursorVisibleY && w.cursorBlinkOn
 			if isCursor && w.hasFocus && cursorShape == 0 {
-				fg, bg = bg, fg
+				fg, bg = scheme.BlockCursorColors(fg, bg)
 			}
 
 			// Calculate cell position and size based on line attributes and flex width
@@ -1715,13 +1772,13 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 			visibleAccumulatedWidth += cellVisualWidth
 
 			// Draw background if different from terminal background
-			if bg != scheme.Background(isDark) {
+			if !w.buffer.SkipBackgroundFill(&cell, bg, schemeBg) {
 				bgQColor := qt.NewQColor3(int(bg.R), int(bg.G), int(bg.B))
 				painter.FillRect5(cellX, cellY, cellW, cellH, bgQColor)
 			}
 
 			// Draw character
-			if cell.Char != ' ' && cell.Char != 0 && blinkVisible {
+			if cell.Char != ' ' && cell.Char != 0 && blinkVisible && !cell.Conceal {
 				// Check for custom glyph first
 				if w.renderCustomGlyph(painter, &cell, cellX, cellY, cellW, cellH, x, blinkPhase, scheme.BlinkMode, lineAttr) {
 					// Custom glyph was rendered, skip normal text rendering
@@ -1797,7 +1854,7 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 				// Calculate bobbing wave offset
 				yOffset := 0.0
 				if cell.Blink && scheme.BlinkMode == purfecterm.BlinkModeBounce {
-					wavePhase := blinkPhase + float64(x)*0.5
+					wavePhase := cell.BlinkPhase(blinkPhase) + float64(x)*0.5
 					yOffset = math.Sin(wavePhase) * 3.0
 				}
 
@@ -1920,8 +1977,9 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 			}
 		afterCharRenderQt:
 
-			// Draw underline (with style support)
-			if cell.UnderlineStyle != purfecterm.UnderlineNone {
+			// Draw underline (with style support); the top half of a
+			// double-height line has no bottom edge of its own
+			if cell.UnderlineStyle != purfecterm.UnderlineNone && lineAttr != purfecterm.LineAttrDoubleTop {
 				// Use underline color if set, otherwise use foreground color
 				ulColor := fg
 				if cell.HasUnderlineColor {
@@ -2003,6 +2061,17 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 				painter.FillRect5(cellX, strikeY, cellW, strikeH, fgQColor)
 			}
 
+			// Draw overline (the bottom half of a double-height line has no
+			// top edge of its own)
+			if cell.Overline && lineAttr != purfecterm.LineAttrDoubleBottom {
+				fgQColor := qt.NewQColor3(int(fg.R), int(fg.G), int(fg.B))
+				overH := 1
+				if lineAttr == purfecterm.LineAttrDoubleTop {
+					overH = 2
+				}
+				painter.FillRect5(cellX, cellY, cellW, overH, fgQColor)
+			}
+
 			// Draw cursor
 			if isCursor {
 				cursorQColor := qt.NewQColor3(int(scheme.Cursor.R), int(scheme.Cursor.G), int(scheme.Cursor.B))
@@ -2090,12 +2159,12 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 		w.buffer.SetSplitContentWidth(0)
 	}
 
-	// Draw yellow dashed line between scrollback and logical screen
+	// Draw dashed line between scrollback and logical screen
 	boundaryRow := w.buffer.GetScrollbackBoundaryVisibleRow()
-	if boundaryRow > 0 {
+	if boundaryRow > 0 && w.buffer.GetShowScrollbackBoundary() {
 		lineY := boundaryRow * charHeight
-		yellowColor := qt.NewQColor3(255, 200, 0)
-		pen := qt.NewQPen3(yellowColor)
+		lineColor := scheme.ScrollbackBoundaryColor(isDark)
+		pen := qt.NewQPen3(qt.NewQColor3(int(lineColor.R), int(lineColor.G), int(lineColor.B)))
 		pen.SetWidth(1)
 		pen.SetStyle(qt.DashLine)
 		painter.SetPenWithPen(pen)
@@ -2122,8 +2191,10 @@ func (w *Widget) paintEvent(event *qt.QPaintEvent) {
 		// Horizontal scroll happened, redraw will be triggered by markDirty
 	}
 
-	// Update scrollbars after rendering (safe here since we're not holding buffer lock)
+	// Update scrollbars after rendering (safe here since we're not holding buffer lock).
+	// The horizontal range follows the widest visible content, which changes with output.
 	w.updateScrollbar()
+	w.updateHorizScrollbar()
 
 	w.buffer.ClearDirty()
 }
@@ -2174,6 +2245,11 @@ func (w *Widget) screenToCell(screenX, screenY int) (cellX, cellY int) {
 	for col := horizOffset; col < cols+horizOffset; col++ {
 		cell := w.buffer.GetVisibleCell(col, cellY)
 
+		// A wide-char continuation cell is covered by its glyph
+		if cell.Continuation {
+			continue
+		}
+
 		// Calculate this cell's visual width
 		// Standard-mode cells carry real widths too, so key on CellWidth
 		// regardless of the FlexWidth flag.
@@ -2241,6 +2317,15 @@ func (w *Widget) keyPressEvent(super func(event *qt.QKeyEvent), event *qt.QKeyEv
 		hasCtrl, hasMeta = hasMeta, hasCtrl
 	}
 
+	// Handle clipboard copy (Ctrl+C with selection only, matching GTK)
+	// Ctrl+C without a selection falls through to send the interrupt; paste
+	// and Select All are reached through the right-click context menu
+	if hasCtrl && !hasAlt && !hasMeta && qt.Key(key) == qt.Key_C {
+		if w.buffer.CopySelectionTo(qt.QGuiApplication_Clipboard()) {
+			return
+		}
+	}
+
 	var data []byte
 	hasModifiers := hasShift || hasCtrl || hasAlt || hasMeta
 
@@ -2250,7 +2335,7 @@ func (w *Widget) keyPressEvent(super func(event *qt.QKeyEvent), event *qt.QKeyEv
 			mod := w.calcMod(hasShift, hasCtrl, hasAlt, hasMeta)
 			data = []byte(fmt.Sprintf("\x1b[13;%du", mod)) // CSI 13 ; mod u (kitty protocol)
 		} else {
-			data = []byte{'\r'}
+			data = w.buffer.EnterKeySequence() // CR, or CR LF under LNM
 		}
 	case qt.Key_Backspace:
 		if hasCtrl {
@@ -2740,7 +2825,6 @@ func (w *Widget) sendMouseEvent(button, cellX, cellY int, press bool) bool {
 		return false
 	}
 
-	encodingMode := w.buffer.GetMouseEncodingMode()
 	// screenToCell yields a LOGICAL cell index. Under the standard contract
 	// the hosted application addresses in VISUAL columns, so translate; under
 	// flex mode (?7027h) it addresses logical cells, so report as-is.
@@ -2748,7 +2832,7 @@ func (w *Widget) sendMouseEvent(button, cellX, cellY int, press bool) bool {
 	if !w.buffer.IsFlexWidthModeEnabled() {
 		reportX = w.buffer.LogicalToVisualCol(cellY, cellX)
 	}
-	data := purfecterm.EncodeMouseEvent(button, reportX+1, cellY+1, press, encodingMode)
+	data := w.buffer.EncodeMouseEvent(button, reportX+1, cellY+1, press)
 	if data != nil {
 		onInput(data)
 		return true
@@ -3078,6 +3162,7 @@ func (w *Widget) wheelEvent(event *qt.QWheelEvent) {
 
 	deltaY := event.AngleDelta().Y()
 	deltaX := event.AngleDelta().X()
+	step := w.buffer.GetScrollLineStep()
 
 	// Check if mouse reporting should handle scroll events
 	// Shift bypasses mouse reporting for local scrollback
@@ -3107,12 +3192,12 @@ func (w *Widget) wheelEvent(event *qt.QWheelEvent) {
 		maxOffset := w.buffer.GetMaxHorizOffset()
 
 		if delta > 0 {
-			offset -= 3
+			offset -= step
 			if offset < 0 {
 				offset = 0
 			}
 		} else if delta < 0 {
-			offset += 3
+			offset += step
 			if offset > maxOffset {
 				offset = maxOffset
 			}
@@ -3124,28 +3209,11 @@ func (w *Widget) wheelEvent(event *qt.QWheelEvent) {
 		return
 	}
 
-	// Vertical scrolling
-	offset := w.buffer.GetScrollOffset()
-	scrollbackSize := w.buffer.GetScrollbackSize()
-
+	// Vertical scrolling (only scrolling DOWN snaps in the magnetic zone)
 	if deltaY > 0 {
-		// Scrolling UP into scrollback - don't normalize, let them push through
-		offset += 3
-		if offset > scrollbackSize {
-			offset = scrollbackSize
-		}
-		w.buffer.SetScrollOffset(offset)
-		w.buffer.NotifyManualVertScroll() // User initiated scroll
+		w.buffer.ScrollViewWheel(1)
 	} else if deltaY < 0 {
-		// Scrolling DOWN toward logical screen
-		offset -= 3
-		if offset < 0 {
-			offset = 0
-		}
-		w.buffer.SetScrollOffset(offset)
-		// Only snap to 0 when scrolling DOWN into the magnetic zone
-		w.buffer.NormalizeScrollOffset()
-		w.buffer.NotifyManualVertScroll() // User initiated scroll
+		w.buffer.ScrollViewWheel(-1)
 	}
 
 	w.updateScrollbar()
@@ -3156,11 +3224,24 @@ func (w *Widget) focusInEvent(event *qt.QFocusEvent) {
 	w.hasFocus = true
 	w.cursorBlinkOn = true
 	w.widget.Update()
+	w.reportFocus(true)
 }
 
 func (w *Widget) focusOutEvent(event *qt.QFocusEvent) {
 	w.hasFocus = false
 	w.widget.Update()
+	w.reportFocus(false)
+}
+
+// reportFocus sends a focus in/out report to the PTY if the application
+// enabled focus reporting (DEC 1004)
+func (w *Widget) reportFocus(focused bool) {
+	w.mu.Lock()
+	onInput := w.onInput
+	w.mu.Unlock()
+	if data := w.buffer.FocusEvent(focused); data != nil && onInput != nil {
+		onInput(data)
+	}
 }
 
 func (w *Widget) resizeEvent(event *qt.QResizeEvent) {
@@ -3199,28 +3280,12 @@ func (w *Widget) resizeEvent(event *qt.QResizeEvent) {
 		}
 	}
 
-	// Apply screen scaling to character dimensions
-	horizScale := w.buffer.GetHorizontalScale()
-	vertScale := w.buffer.GetVerticalScale()
-	scaledCharWidth := int(float64(w.charWidth) * horizScale)
-	scaledCharHeight := int(float64(w.charHeight) * vertScale)
-	if scaledCharWidth < 1 {
-		scaledCharWidth = 1
-	}
-	if scaledCharHeight < 1 {
-		scaledCharHeight = 1
-	}
-
-	// Account for scrollbars when calculating columns
-	newCols := (widgetWidth - terminalLeftPadding - scrollbarWidth) / scaledCharWidth
-	newRows := effectiveHeight / scaledCharHeight
-
-	if newCols < 1 {
-		newCols = 1
-	}
-	if newRows < 1 {
-		newRows = 1
-	}
+	// Recompute the grid from the widget size and font metrics (with screen
+	// scaling), accounting for padding and scrollbars - same as GTK onConfigure
+	newCols, newRows := purfecterm.GridSizeForPixels(
+		widgetWidth-terminalLeftPadding-scrollbarWidth, effectiveHeight,
+		w.charWidth, w.charHeight,
+		w.buffer.GetHorizontalScale(), w.buffer.GetVerticalScale())
 
 	// Check if size actually changed
 	oldCols, oldRows := w.buffer.GetSize()
@@ -3234,8 +3299,11 @@ func (w *Widget) resizeEvent(event *qt.QResizeEvent) {
 	}
 
 	// Notify PTY of size change
-	if sizeChanged && w.onResize != nil {
-		w.onResize(newCols, newRows)
+	w.mu.Lock()
+	onResize := w.onResize
+	w.mu.Unlock()
+	if sizeChanged && onResize != nil {
+		onResize(newCols, newRows)
 	}
 
 	w.updateScrollbar()
@@ -3260,6 +3328,11 @@ func (w *Widget) GetTerminalCapabilities() *purfecterm.TerminalCapabilities {
 	return w.termCaps
 }
 
+// Capabilities is an alias for GetTerminalCapabilities.
+func (w *Widget) Capabilities() *purfecterm.TerminalCapabilities {
+	return w.termCaps
+}
+
 // GetSelectedText returns the currently selected text
 func (w *Widget) GetSelectedText() string {
 	return w.buffer.GetSelectedText()
@@ -3267,14 +3340,13 @@ func (w *Widget) GetSelectedText() string {
 
 // CopySelection copies selected text to clipboard
 func (w *Widget) CopySelection() {
-	if w.buffer.HasSelection() {
-		text := w.buffer.GetSelectedText()
-		clipboard := qt.QGuiApplication_Clipboard()
-		clipboard.SetText(text)
-	}
+	w.buffer.CopySelectionTo(qt.QGuiApplication_Clipboard())
 }
 
 // PasteClipboard pastes text from clipboard
+// Uses bracketed paste if enabled by the application or if the pasted text
+// contains control characters, then applies the buffer's paste filter
+// (see purfecterm.Buffer.PreparePaste)
 func (w *Widget) PasteClipboard() {
 	w.mu.Lock()
 	onInput := w.onInput
@@ -3287,23 +3359,8 @@ func (w *Widget) PasteClipboard() {
 	clipboard := qt.QGuiApplication_Clipboard()
 	text := clipboard.Text()
 	if text != "" {
-		useBracketedPaste := w.buffer.IsBracketedPasteModeEnabled()
-
-		if !useBracketedPaste {
-			for _, c := range text {
-				if c == '\n' || c == '\r' || c == '\x1b' || c < 32 {
-					useBracketedPaste = true
-					break
-				}
-			}
-		}
-
-		if useBracketedPaste {
-			onInput([]byte("\x1b[200~"))
-			onInput([]byte(text))
-			onInput([]byte("\x1b[201~"))
-		} else {
-			onInput([]byte(text))
+		if data := w.buffer.PreparePaste(text); len(data) > 0 {
+			onInput(data)
 		}
 	}
 }
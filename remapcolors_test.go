@@ -0,0 +1,38 @@
+package purfecterm
+
+import "testing"
+
+// RemapColors rewrites colors already on screen and in scrollback, and the
+// padding past each line's end, leaving other colors alone.
+func TestRemapColors(t *testing.T) {
+	white, black := TrueColor(255, 255, 255), TrueColor(0, 0, 0)
+	b := NewBuffer(10, 2, 100)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b[38;2;255;255;255mold\r\n"))
+	p.Parse([]byte("\x1b[38;2;255;255;255;48;2;255;255;255mw\x1b[K\x1b[0;31mr\r\nnext"))
+
+	b.ClearDirty()
+	b.RemapColors(func(c Color) Color {
+		if c == white {
+			return black
+		}
+		return c
+	})
+
+	if !b.IsDirty() {
+		t.Error("RemapColors should mark the buffer dirty")
+	}
+	if got := b.GetScrollbackLine(0)[0].Foreground; got != black {
+		t.Errorf("scrollback fg = %+v, want black", got)
+	}
+	c := b.GetCell(0, 0)
+	if c.Foreground != black || c.Background != black {
+		t.Errorf("screen cell fg/bg = %+v/%+v, want black", c.Foreground, c.Background)
+	}
+	if got := b.GetCell(1, 0).Foreground; got != StandardColor(1) {
+		t.Errorf("unmatched color changed to %+v", got)
+	}
+	if got := b.GetCell(8, 0).Background; got != black {
+		t.Errorf("line padding bg = %+v, want black", got)
+	}
+}
@@ -0,0 +1,20 @@
+package purfecterm
+
+import "testing"
+
+// A short escape-sequence script renders to the expected golden text, with
+// and without the cursor marker.
+func TestRenderPlain(t *testing.T) {
+	b := NewBuffer(10, 3, 100)
+	p := NewParser(b)
+	p.Parse([]byte("hello\r\n\x1b[1;31mworld\x1b[0m  \r\n\x1b[3;4Hx\x1b[1;2H"))
+
+	want := "hello\nworld\n   x"
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("RenderPlain:\n%q\nwant\n%q", got, want)
+	}
+	wantCursor := "h[e]llo\nworld\n   x"
+	if got := b.RenderWithCursor(); got != wantCursor {
+		t.Fatalf("RenderWithCursor:\n%q\nwant\n%q", got, wantCursor)
+	}
+}
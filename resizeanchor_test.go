@@ -0,0 +1,58 @@
+package purfecterm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// While scrolled back, Resize keeps the anchored line on its row, even
+// when shrinking pushes screen lines into a full scrollback.
+func TestResizeAnchor(t *testing.T) {
+	row := func(b *Buffer, y int) string { return strings.Split(b.RenderPlain(), "\n")[y] }
+
+	for _, tc := range []struct {
+		name   string
+		anchor ResizeAnchor
+		row    func(rows int) int
+	}{
+		{"top", ResizeAnchorTop, func(int) int { return 0 }},
+		{"bottom", ResizeAnchorBottom, func(rows int) int { return rows - 1 }},
+	} {
+		b := NewBuffer(20, 10, 100)
+		p := NewParser(b)
+		for i := 0; i < 200; i++ {
+			p.Parse([]byte(fmt.Sprintf("\r\nline%d", i)))
+		}
+		b.SetResizeAnchor(tc.anchor)
+		b.SetScrollOffset(40)
+		want := row(b, tc.row(10))
+
+		for _, rows := range []int{6, 14, 3, 10} {
+			b.Resize(20, rows)
+			if got := row(b, tc.row(rows)); got != want {
+				t.Errorf("%s: after resize to %d rows, anchor row shows %q, want %q", tc.name, rows, got, want)
+			}
+		}
+	}
+
+	// The cursor's line keeps its distance from the top: three rows down
+	// from where it sits unscrolled
+	b := NewBuffer(20, 10, 100)
+	p := NewParser(b)
+	for i := 0; i < 20; i++ {
+		p.Parse([]byte(fmt.Sprintf("\r\nline%d", i)))
+	}
+	p.Parse([]byte("\x1b[5H"))
+	b.SetResizeAnchor(ResizeAnchorCursor)
+	b.SetScrollOffset(b.getMagneticThreshold() + 3)
+	if got := row(b, 7); got != "line14" {
+		t.Fatalf("before resize row 7 shows %q, want the cursor's line14", got)
+	}
+	for _, rows := range []int{8, 12} {
+		b.Resize(20, rows)
+		if got := row(b, 7); got != "line14" {
+			t.Errorf("after resize to %d rows, row 7 shows %q, want line14", rows, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package purfecterm
+
+import "testing"
+
+// DECSCNM inverts every cell through EffectiveScheme without touching the
+// theme, explicit colors included, and Reset clears it.
+func TestDECSCNMReverseScreen(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	b.SetDarkTheme(false)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b[?5hA"))
+
+	if !b.IsReverseScreen() || b.IsDarkTheme() {
+		t.Fatal("?5h should set reverse video and leave the theme alone")
+	}
+	scheme := DefaultColorScheme()
+	cell := b.GetCell(0, 0)
+	eff := b.EffectiveScheme(scheme)
+	if fg := eff.ResolveColor(cell.Foreground, true, false); fg != scheme.Background(false) {
+		t.Fatalf("reversed fg = %v, want the normal background %v", fg, scheme.Background(false))
+	}
+	if bg := eff.ResolveColor(cell.Background, false, false); bg != scheme.Foreground(false) {
+		t.Fatalf("reversed bg = %v, want the normal foreground %v", bg, scheme.Foreground(false))
+	}
+
+	// An explicitly black-on-white cell is inverted too, in either theme
+	p.Parse([]byte("\x1b[30;47mB"))
+	cell = b.GetCell(1, 0)
+	for _, isDark := range []bool{true, false} {
+		fg, bg := eff.ResolveCellColors(&cell, isDark)
+		if fg != StandardColor(7) || bg != StandardColor(0) {
+			t.Errorf("dark=%v: SGR 30;47 under ?5h = %v on %v, want white on black", isDark, fg, bg)
+		}
+	}
+	if fg, bg := scheme.ResolveCellColors(&cell, false); fg != StandardColor(0) || bg != StandardColor(7) {
+		t.Errorf("without ?5h = %v on %v, want black on white", fg, bg)
+	}
+
+	b.Reset()
+	if b.IsReverseScreen() {
+		t.Fatal("Reset should clear reverse video")
+	}
+}
@@ -0,0 +1,77 @@
+package purfecterm
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The byte cap evicts the oldest lines until the estimated size fits,
+// independently of the line cap.
+func TestScrollbackByteLimit(t *testing.T) {
+	b := NewBuffer(10, 1, 100)
+	b.SetScrollbackByteLimit(10 * scrollbackCellBytes)
+
+	push := func(n int) {
+		line := make([]Cell, n)
+		for i := range line {
+			line[i] = EmptyCell()
+		}
+		b.mu.Lock()
+		b.pushLineToScrollback(line, b.makeDefaultLineInfo())
+		b.mu.Unlock()
+	}
+
+	push(4)
+	push(4)
+	if n := b.GetScrollbackSize(); n != 2 {
+		t.Fatalf("8 cells fit in a 10-cell budget, got %d lines", n)
+	}
+	push(6) // 14 cells: the oldest 4-cell line must go
+	if n, bytes := b.GetScrollbackSize(), b.GetScrollbackBytes(); n != 2 || bytes != 10*scrollbackCellBytes {
+		t.Fatalf("after eviction want 2 lines / 10 cells, got %d lines / %d bytes", n, bytes)
+	}
+	push(1) // 11 cells: the 4-cell line goes, leaving 6+1
+	if n := b.GetScrollbackSize(); n != 2 {
+		t.Fatalf("want 2 lines, got %d", n)
+	}
+
+	// Disabling the byte cap leaves only the line cap.
+	b.SetScrollbackByteLimit(0)
+	for i := 0; i < 5; i++ {
+		push(8)
+	}
+	if n := b.GetScrollbackSize(); n != 7 {
+		t.Fatalf("with the byte cap off all lines stay, got %d", n)
+	}
+}
+
+// Shrinking the line cap keeps the newest lines and a valid scroll offset.
+func TestSetMaxScrollback(t *testing.T) {
+	b := NewBuffer(10, 2, 1000)
+	p := NewParser(b)
+	for i := 0; i < 1002; i++ {
+		p.Parse([]byte(fmt.Sprintf("%d\r\n", i)))
+	}
+	if n := b.GetScrollbackSize(); n != 1000 {
+		t.Fatalf("scrollback = %d lines, want 1000", n)
+	}
+	b.SetScrollOffset(900)
+
+	b.SetMaxScrollback(100)
+	if n := b.GetScrollbackSize(); n != 100 || b.GetMaxScrollback() != 100 {
+		t.Fatalf("after shrink: %d lines, cap %d; want 100", n, b.GetMaxScrollback())
+	}
+	// Scrollback holds lines 901..1000; the screen shows 1001 and the blank row
+	if c := b.scrollback[0]; len(c) != 3 || string([]rune{c[0].Char, c[1].Char, c[2].Char}) != "901" {
+		t.Fatalf("oldest kept line has %d cells, want \"901\"", len(c))
+	}
+	if off, max := b.GetScrollOffset(), b.GetMaxScrollOffset(); off > max {
+		t.Fatalf("scroll offset %d beyond max %d", off, max)
+	}
+
+	b.SetMaxScrollback(500)
+	p.Parse([]byte("x\r\ny\r\n"))
+	if n := b.GetScrollbackSize(); n != 102 {
+		t.Fatalf("growing the cap should keep lines and allow more, got %d", n)
+	}
+}
@@ -0,0 +1,41 @@
+package purfecterm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// Reading through ScrollbackReader, in any chunk size, yields exactly
+// SaveScrollbackText
+func TestScrollbackReader(t *testing.T) {
+	b := NewBuffer(20, 5, 1000)
+	p := NewParser(b)
+	for i := 0; i < 300; i++ {
+		p.Parse([]byte(fmt.Sprintf("line %d é中\r\n", i)))
+	}
+	want := b.SaveScrollbackText()
+
+	got, err := io.ReadAll(b.ScrollbackReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("ReadAll: %d bytes differing from SaveScrollbackText (%d bytes)", len(got), len(want))
+	}
+
+	// One byte at a time splits runes across reads
+	got, err = io.ReadAll(iotest.OneByteReader(b.ScrollbackReader()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, []byte(want)) {
+		t.Error("one byte at a time: text differs from SaveScrollbackText")
+	}
+
+	if err := iotest.TestReader(b.ScrollbackReader(), []byte(want)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,46 @@
+package purfecterm
+
+import (
+	"strings"
+	"testing"
+)
+
+// Scrolling back through the magnetic zone, the boundary stays hidden while
+// MagneticProgress climbs from 0 to 1; past the zone the line appears.
+func TestScrollBoundaryMagneticProgress(t *testing.T) {
+	b := NewBuffer(20, 10, 1000)
+	NewParser(b).Parse([]byte(strings.Repeat("line\r\n", 200)))
+
+	if s := b.ScrollBoundaryState(); s.VisibleRow != -1 || s.InMagneticZone || s.MagneticProgress != 0 {
+		t.Fatalf("at the bottom: %+v, want no boundary and no progress", s)
+	}
+
+	threshold := b.getMagneticThreshold()
+	if threshold < 2 {
+		t.Fatalf("threshold = %d, want a zone to scroll through", threshold)
+	}
+	last := 0.0
+	for offset := 1; offset <= threshold; offset++ {
+		b.SetScrollOffset(offset)
+		s := b.ScrollBoundaryState()
+		if !s.InMagneticZone || s.VisibleRow != -1 {
+			t.Fatalf("offset %d: %+v, want in the zone with the line hidden", offset, s)
+		}
+		if s.MagneticProgress <= last || s.MagneticProgress > 1 {
+			t.Fatalf("offset %d: progress %v after %v, want rising to at most 1", offset, s.MagneticProgress, last)
+		}
+		last = s.MagneticProgress
+	}
+	if last != 1 {
+		t.Errorf("progress at the end of the zone = %v, want 1", last)
+	}
+
+	b.SetScrollOffset(threshold + 1)
+	s := b.ScrollBoundaryState()
+	if s.InMagneticZone || s.MagneticProgress != 1 || s.VisibleRow != 1 {
+		t.Errorf("past the zone: %+v, want the line on row 1 at full progress", s)
+	}
+	if row := b.GetScrollbackBoundaryVisibleRow(); row != s.VisibleRow {
+		t.Errorf("GetScrollbackBoundaryVisibleRow = %d, want %d", row, s.VisibleRow)
+	}
+}
@@ -0,0 +1,169 @@
+package purfecterm
+
+import "testing"
+
+// With a 2-5 scroll region (DECSTBM), RI on the region's top row scrolls only
+// the region down; rows outside it are untouched.
+func TestReverseIndexScrollRegion(t *testing.T) {
+	b := NewBuffer(10, 6, 100)
+	p := NewParser(b)
+	p.Parse([]byte("r1\r\nr2\r\nr3\r\nr4\r\nr5\r\nr6"))
+	p.Parse([]byte("\x1b[2;5r")) // region rows 2-5 (1-based), homes cursor
+	p.Parse([]byte("\x1b[2;1H\x1bM"))
+
+	want := "r1\n\nr2\nr3\nr4\nr6"
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("after RI:\n%q\nwant\n%q", got, want)
+	}
+	if _, y := b.GetCursor(); y != 1 {
+		t.Fatalf("cursor should stay on the top margin, got row %d", y)
+	}
+}
+
+// IND on the bottom margin scrolls the region up without feeding scrollback.
+func TestIndexScrollRegion(t *testing.T) {
+	b := NewBuffer(10, 6, 100)
+	p := NewParser(b)
+	p.Parse([]byte("r1\r\nr2\r\nr3\r\nr4\r\nr5\r\nr6"))
+	p.Parse([]byte("\x1b[2;5r\x1b[5;1H\x1bD"))
+
+	want := "r1\nr3\nr4\nr5\n\nr6"
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("after IND:\n%q\nwant\n%q", got, want)
+	}
+	if n := b.GetScrollbackSize(); n != 0 {
+		t.Fatalf("region scroll must not push scrollback, got %d lines", n)
+	}
+}
+
+// Under origin mode (DECOM) CUP rows count from the top margin and the
+// reported position is region-relative; without it, reports are absolute.
+func TestReportedCursorPositionOriginMode(t *testing.T) {
+	b := NewBuffer(20, 10, 100)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b[4;8r\x1b[3;5H"))
+	if row, col := b.ReportedCursorPosition(); row != 3 || col != 5 {
+		t.Fatalf("absolute report = %d,%d, want 3,5", row, col)
+	}
+
+	p.Parse([]byte("\x1b[?6h"))
+	if _, y := b.GetCursor(); y != 3 {
+		t.Fatalf("DECOM should home to the top margin (row 3), got %d", y)
+	}
+	p.Parse([]byte("\x1b[2;5H"))
+	if _, y := b.GetCursor(); y != 4 {
+		t.Errorf("CUP row 2 under DECOM should land on screen row 4, got %d", y)
+	}
+	if row, col := b.ReportedCursorPosition(); row != 2 || col != 5 {
+		t.Errorf("region-relative report = %d,%d, want 2,5", row, col)
+	}
+
+	p.Parse([]byte("\x1b[20;1H")) // Clamped to the bottom margin
+	if row, _ := b.ReportedCursorPosition(); row != 5 {
+		t.Errorf("CUP below the region should clamp to its last row (5), got %d", row)
+	}
+
+	p.Parse([]byte("\x1b[?6l"))
+	if row, col := b.ReportedCursorPosition(); row != 1 || col != 1 {
+		t.Errorf("leaving DECOM should home to 1,1, got %d,%d", row, col)
+	}
+}
+
+// IL/DL inside a 2-6 region shift only the region's rows: row 7 and
+// beyond stay put, and row 1 is never touched.
+func TestInsertDeleteLinesScrollRegion(t *testing.T) {
+	b := NewBuffer(10, 8, 100)
+	p := NewParser(b)
+	p.Parse([]byte("r1\r\nr2\r\nr3\r\nr4\r\nr5\r\nr6\r\nr7\r\nr8"))
+	p.Parse([]byte("\x1b[2;6r\x1b[3;1H\x1b[M")) // DL at row 3
+
+	want := "r1\nr2\nr4\nr5\nr6\n\nr7\nr8"
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("after DL:\n%q\nwant\n%q", got, want)
+	}
+
+	p.Parse([]byte("\x1b[3;1H\x1b[2L")) // IL 2 at row 3
+	want = "r1\nr2\n\n\nr4\nr5\nr7\nr8"
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("after IL:\n%q\nwant\n%q", got, want)
+	}
+
+	// Outside the region IL/DL do nothing
+	p.Parse([]byte("\x1b[7;1H\x1b[M\x1b[1;1H\x1b[L"))
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("IL/DL outside the region changed the screen:\n%q", got)
+	}
+
+	// A count larger than the region just clears to the bottom margin
+	p.Parse([]byte("\x1b[5;1H\x1b[99M"))
+	want = "r1\nr2\n\n\n\n\nr7\nr8"
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("after DL 99:\n%q\nwant\n%q", got, want)
+	}
+
+	// Zero or negative counts from the API do nothing
+	p.Parse([]byte("r5\x1b[3;1H"))
+	want = b.RenderPlain()
+	for _, n := range []int{0, -1} {
+		b.InsertLines(n)
+		b.DeleteLines(n)
+	}
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("IL/DL with n <= 0 changed the screen:\n%q\nwant\n%q", got, want)
+	}
+}
+
+// ED 2 erases the whole screen whatever the margins and leaves the cursor
+// where it was; ClearScrollRegion erases inside the margins only.
+func TestEraseWithScrollRegion(t *testing.T) {
+	b := NewBuffer(10, 6, 100)
+	p := NewParser(b)
+	p.Parse([]byte("r1\r\nr2\r\nr3\r\nr4\r\nr5\r\nr6"))
+	p.Parse([]byte("\x1b[2;4r\x1b[3;2H"))
+
+	b.ClearScrollRegion()
+	want := "r1\n\n\n\nr5\nr6"
+	if got := b.RenderPlain(); got != want {
+		t.Fatalf("after ClearScrollRegion:\n%q\nwant\n%q", got, want)
+	}
+	if x, y := b.GetCursor(); x != 1 || y != 2 {
+		t.Fatalf("ClearScrollRegion moved the cursor to %d,%d", x, y)
+	}
+
+	p.Parse([]byte("\x1b[5;3H\x1b[2J"))
+	if got := b.RenderPlain(); got != "\n\n\n\n\n" {
+		t.Fatalf("ED 2 should clear every row, got %q", got)
+	}
+	if x, y := b.GetCursor(); x != 2 || y != 4 {
+		t.Fatalf("ED 2 moved the cursor to %d,%d, want 2,4", x, y)
+	}
+}
+
+// EraseScreen only returns the view to the bottom when asked; ClearScreen
+// always does and homes the cursor.
+func TestEraseScreenKeepsScroll(t *testing.T) {
+	b := NewBuffer(10, 3, 100)
+	p := NewParser(b)
+	for i := 0; i < 10; i++ {
+		p.Parse([]byte("line\r\n"))
+	}
+	b.SetScrollOffset(2)
+
+	b.EraseScreen(false)
+	if got := b.GetScrollOffset(); got != 2 {
+		t.Fatalf("EraseScreen(false) scroll offset = %d, want 2", got)
+	}
+	b.EraseScreen(true)
+	if got := b.GetScrollOffset(); got != 0 {
+		t.Fatalf("EraseScreen(true) scroll offset = %d, want 0", got)
+	}
+
+	b.SetScrollOffset(2)
+	b.ClearScreen()
+	if got := b.GetScrollOffset(); got != 0 {
+		t.Fatalf("ClearScreen scroll offset = %d, want 0", got)
+	}
+	if x, y := b.GetCursor(); x != 0 || y != 0 {
+		t.Fatalf("ClearScreen cursor %d,%d, want home", x, y)
+	}
+}
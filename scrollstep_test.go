@@ -0,0 +1,74 @@
+package purfecterm
+
+import "testing"
+
+// A wheel notch scrolls the view by the line step (3 lines by default),
+// back into the scrollback going up and toward the live screen going down.
+func TestScrollLineStep(t *testing.T) {
+	b := NewBuffer(10, 5, 100)
+	p := NewParser(b)
+	for i := 0; i < 40; i++ {
+		p.Parse([]byte("x\r\n"))
+	}
+	start := b.getMagneticThreshold() + 10 // Clear of the magnetic zone
+
+	b.SetScrollOffset(start)
+	b.ScrollViewWheel(1)
+	if got := b.GetScrollOffset(); got != start+defaultScrollLineStep {
+		t.Fatalf("default notch up: want offset %d, got %d", start+defaultScrollLineStep, got)
+	}
+
+	b.SetScrollOffset(start)
+	b.SetScrollLineStep(7)
+	b.ScrollViewWheel(1)
+	if got := b.GetScrollOffset(); got != start+7 {
+		t.Fatalf("7-line notch up: want offset %d, got %d", start+7, got)
+	}
+	b.ScrollViewWheel(-2)
+	if got := b.GetScrollOffset(); got != start-7 {
+		t.Fatalf("two 7-line notches down: want offset %d, got %d", start-7, got)
+	}
+
+	// Wheeling down into the magnetic zone snaps to the live screen
+	b.SetScrollOffset(b.getMagneticThreshold() + 1)
+	b.SetScrollLineStep(1)
+	b.ScrollViewWheel(-1)
+	if got := b.GetScrollOffset(); got != 0 {
+		t.Fatalf("notch down into the magnetic zone: want offset 0, got %d", got)
+	}
+
+	b.SetScrollLineStep(0)
+	if got := b.GetScrollLineStep(); got != defaultScrollLineStep {
+		t.Fatalf("zero should restore default step, got %d", got)
+	}
+}
+
+// A page scroll moves the view by the page step, one screen minus a line
+// by default.
+func TestScrollPageStep(t *testing.T) {
+	b := NewBuffer(10, 5, 100)
+	p := NewParser(b)
+	for i := 0; i < 40; i++ {
+		p.Parse([]byte("x\r\n"))
+	}
+	if got := b.GetScrollPageStep(); got != 4 {
+		t.Fatalf("default page step should be rows-1, got %d", got)
+	}
+	b.ScrollViewPages(2)
+	if got := b.GetScrollOffset(); got != 8 {
+		t.Fatalf("two pages up: want offset 8, got %d", got)
+	}
+
+	b.SetScrollPageStep(2)
+	if got := b.GetScrollPageStep(); got != 2 {
+		t.Fatalf("want page step 2, got %d", got)
+	}
+	b.ScrollViewPages(-1)
+	if got := b.GetScrollOffset(); got != 6 {
+		t.Fatalf("one 2-line page down: want offset 6, got %d", got)
+	}
+	b.ScrollViewPages(-10)
+	if got := b.GetScrollOffset(); got != 0 {
+		t.Fatalf("paging past the bottom: want offset 0, got %d", got)
+	}
+}
@@ -0,0 +1,77 @@
+package purfecterm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSearchHighlight(t *testing.T) {
+	b := NewBuffer(10, 3, 100)
+	b.SetSearchMatches([]Match{{Row: 1, StartCol: 2, EndCol: 4}})
+
+	for x := 0; x < 10; x++ {
+		want := x >= 2 && x <= 4
+		if got := b.IsCellInSearchHighlight(x, 1); got != want {
+			t.Errorf("cell %d,1: highlighted=%v, want %v", x, got, want)
+		}
+	}
+	if b.IsCellInSearchHighlight(3, 0) {
+		t.Error("row 0 should not be highlighted")
+	}
+
+	b.ClearSearchMatches()
+	if b.IsCellInSearchHighlight(3, 1) {
+		t.Error("ClearSearchMatches left a highlight")
+	}
+}
+
+// ScrollToMatch centers the match row, including across the magnetic zone.
+func TestScrollToMatch(t *testing.T) {
+	b := NewBuffer(10, 5, 100)
+	p := NewParser(b)
+	for i := 0; i < 60; i++ {
+		p.Parse([]byte(fmt.Sprintf("line%d\r\n", i)))
+	}
+
+	m := Match{Row: 20, StartCol: 0, EndCol: 5}
+	b.SetSearchMatches([]Match{m})
+	b.ScrollToMatch(m)
+	if !b.IsCellInSearchHighlight(0, 2) {
+		t.Fatalf("match row should be on the middle visible row (offset %d)", b.GetScrollOffset())
+	}
+	if got := b.GetVisibleCell(4, 2).Char; got != '2' {
+		t.Fatalf("middle row should show line20, got %q", got)
+	}
+}
+
+// EnsureVisibleBufferY puts a scrollback row on the first, middle or last
+// visible row, and clamps at the ends of the scrollable range.
+func TestEnsureVisibleBufferY(t *testing.T) {
+	b := NewBuffer(10, 5, 100)
+	p := NewParser(b)
+	for i := 0; i < 60; i++ {
+		p.Parse([]byte(fmt.Sprintf("line%d\r\n", i)))
+	}
+
+	for where, row := range map[Position]int{PositionTop: 0, PositionCenter: 2, PositionBottom: 4} {
+		b.EnsureVisibleBufferY(20, where)
+		b.mu.RLock()
+		got := b.bufferToScreenY(20)
+		b.mu.RUnlock()
+		if got != row {
+			t.Errorf("position %d: row 20 on visible row %d, want %d", where, got, row)
+		}
+		if c := b.GetVisibleCell(4, row).Char; c != '2' {
+			t.Errorf("position %d: visible row %d shows %q, want line20", where, row, c)
+		}
+	}
+
+	// The oldest line can't be centered; it stays on top
+	b.EnsureVisibleBufferY(0, PositionCenter)
+	if off, max := b.GetScrollOffset(), b.GetMaxScrollOffset(); off != max {
+		t.Errorf("offset = %d, want clamped to %d", off, max)
+	}
+	if c := b.GetVisibleCell(4, 0).Char; c != '0' {
+		t.Errorf("top row shows %q, want line0", c)
+	}
+}
@@ -0,0 +1,36 @@
+package purfecterm
+
+import "testing"
+
+// Extended colors parse in both the ITU colon form (with or without the
+// empty colorspace field) and the common semicolon form.
+func TestSGRExtendedColorForms(t *testing.T) {
+	red := TrueColor(255, 0, 0)
+	tests := []struct {
+		seq    string
+		fg, bg Color
+	}{
+		{"\x1b[38:2::255:0:0m", red, DefaultBackground},
+		{"\x1b[38:2:255:0:0m", red, DefaultBackground},
+		{"\x1b[38:2:0:255:0:0m", red, DefaultBackground},
+		{"\x1b[38;2;255;0;0m", red, DefaultBackground},
+		{"\x1b[38:5:196m", PaletteColor(196), DefaultBackground},
+		{"\x1b[38;5;196m", PaletteColor(196), DefaultBackground},
+		{"\x1b[48:2::255:0:0m", DefaultForeground, red},
+		{"\x1b[48:5:196m", DefaultForeground, PaletteColor(196)},
+		{"\x1b[1;38:2::255:0:0;4m", red, DefaultBackground}, // Mixed with other parameters
+	}
+	for _, tt := range tests {
+		b := NewBuffer(10, 2, 0)
+		NewParser(b).Parse([]byte(tt.seq + "X"))
+		cell := b.GetCell(0, 0)
+		if cell.Foreground != tt.fg || cell.Background != tt.bg {
+			t.Errorf("%q: fg %+v bg %+v, want fg %+v bg %+v", tt.seq, cell.Foreground, cell.Background, tt.fg, tt.bg)
+		}
+	}
+
+	// Both spellings of 256-color 196 are red
+	if c := PaletteColor(196); c.R != 255 || c.G != 0 || c.B != 0 {
+		t.Errorf("palette 196 = %+v, want red", c)
+	}
+}
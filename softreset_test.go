@@ -0,0 +1,43 @@
+package purfecterm
+
+import "testing"
+
+// DECSTR (CSI ! p) resets modes and attributes but keeps the screen text
+// and the cursor position.
+func TestDECSTRSoftReset(t *testing.T) {
+	b := NewBuffer(20, 5, 100)
+	p := NewParser(b)
+	p.Parse([]byte("hello\x1b[2;4r\x1b[?7l\x1b[?25l\x1b[4 q\x1b[1;31m\x1b[3;5H\x1b7\x1b[4;6H"))
+
+	p.Parse([]byte("\x1b[!p"))
+
+	for i, ch := range "hello" {
+		if c := b.GetCell(i, 0); c.Char != ch {
+			t.Fatalf("screen text lost at col %d: got %q", i, c.Char)
+		}
+	}
+	if x, y := b.GetCursor(); x != 5 || y != 3 {
+		t.Fatalf("cursor moved to %d,%d", x, y)
+	}
+	if top, bottom := b.GetScrollRegion(); top != 0 || bottom != 4 {
+		t.Fatalf("scroll region not reset: %d..%d", top, bottom)
+	}
+	if !b.IsAutoWrapModeEnabled() {
+		t.Fatal("auto-wrap should be re-enabled")
+	}
+	if !b.IsCursorVisible() {
+		t.Fatal("cursor should be visible")
+	}
+	if shape, blink := b.GetCursorStyle(); shape != 0 || blink != 0 {
+		t.Fatalf("cursor style not reset: %d/%d", shape, blink)
+	}
+
+	p.Parse([]byte("X"))
+	if c := b.GetCell(5, 3); c.Bold || c.Foreground != DefaultForeground {
+		t.Fatalf("SGR not reset: %+v", c)
+	}
+	p.Parse([]byte("\x1b8"))
+	if x, y := b.GetCursor(); x != 0 || y != 0 {
+		t.Fatalf("saved cursor should return home, got %d,%d", x, y)
+	}
+}
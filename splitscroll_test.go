@@ -0,0 +1,47 @@
+package purfecterm
+
+import "testing"
+
+// A split's ScrollOffset shifts the rows it fetches back into scrollback
+// while the main view stays live.
+func TestScreenSplitScroll(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	p := NewParser(b)
+	p.Parse([]byte("a\r\nb\r\nc\r\nd")) // scrollback: a, b; screen: c, d
+
+	b.SetScreenSplit(1, 0, 0, 0, 0, 0, 0, 0)
+	split := b.GetScreenSplit(1)
+	if c := b.GetCellForScrolledSplit(0, 0, split.BufferRow, split.BufferCol, split.ScrollOffset).Char; c != 'c' {
+		t.Fatalf("unscrolled split row 0 = %q, want 'c'", c)
+	}
+
+	if !b.SetScreenSplitScroll(1, 2) {
+		t.Fatal("SetScreenSplitScroll on an existing split returned false")
+	}
+	// The split is replaced, not changed under a renderer holding it
+	if split.ScrollOffset != 0 {
+		t.Fatal("SetScreenSplitScroll modified a split already handed out")
+	}
+	split = b.GetScreenSplit(1)
+	if c := b.GetCellForSplit(0, 0, split.BufferRow, split.BufferCol).Char; c != 'c' {
+		t.Fatalf("GetCellForSplit row 0 = %q, want 'c' ignoring the split's scroll", c)
+	}
+	if c := b.GetCellForScrolledSplit(0, 0, split.BufferRow, split.BufferCol, split.ScrollOffset).Char; c != 'a' {
+		t.Fatalf("split scrolled by 2, row 0 = %q, want 'a'", c)
+	}
+	if n := b.GetLineLengthForScrolledSplit(1, split.BufferRow, split.BufferCol, split.ScrollOffset); n != 1 {
+		t.Fatalf("split scrolled by 2, row 1 length = %d, want 1", n)
+	}
+	if c := b.GetVisibleCell(0, 0).Char; c != 'c' {
+		t.Fatalf("main view must stay live, row 0 = %q", c)
+	}
+
+	// Updating the split's geometry keeps its scroll position.
+	b.SetScreenSplit(1, 0, 0, 0, 0, 0, 0, 0)
+	if got := b.GetScreenSplit(1).ScrollOffset; got != 2 {
+		t.Fatalf("SetScreenSplit reset ScrollOffset to %d", got)
+	}
+	if b.SetScreenSplitScroll(9, 1) {
+		t.Fatal("SetScreenSplitScroll on a missing split should return false")
+	}
+}
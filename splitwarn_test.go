@@ -0,0 +1,64 @@
+package purfecterm
+
+import (
+	"strings"
+	"testing"
+)
+
+// Out-of-range split parameters are clamped and leave a diagnostic, as
+// does a split starting where another one does; a clean call clears it.
+func TestScreenSplitWarnings(t *testing.T) {
+	b := NewBuffer(10, 4, 100)
+
+	b.SetScreenSplit(1, 0, 40, 0, 0, 0, 0, 0)
+	if w := b.LastSplitWarning(); !strings.Contains(w, "bufferRow 40") {
+		t.Fatalf("out-of-range bufferRow should warn, got %q", w)
+	}
+	if row := b.GetScreenSplit(1).BufferRow; row != 3 {
+		t.Fatalf("bufferRow should clamp to 3, got %d", row)
+	}
+
+	// Every clamp in one call is reported, not just the last
+	b.SetScreenSplit(2, 1000, -1, 0, 0, 0, 0, 0)
+	w := b.LastSplitWarning()
+	for _, want := range []string{"screenY 1000", "bufferRow -1"} {
+		if !strings.Contains(w, want) {
+			t.Errorf("warning %q does not mention %q", w, want)
+		}
+	}
+
+	// A split may start past the screen width, for lines wider than it
+	b.SetScreenSplit(2, 1, 1, 50, 0, 0, 0, 0)
+	if w := b.LastSplitWarning(); w != "" {
+		t.Fatalf("bufferCol past the screen width should not warn, got %q", w)
+	}
+	if col := b.GetScreenSplit(2).BufferCol; col != 50 {
+		t.Fatalf("bufferCol should be kept as 50, got %d", col)
+	}
+
+	b.SetScreenSplit(2, 1, 1, 0, 0, 0, 0, 0)
+	if w := b.LastSplitWarning(); w != "" {
+		t.Fatalf("a valid split should clear the warning, got %q", w)
+	}
+	b.SetScreenSplit(2, 0, 1, 0, 0, 0, 0, 0)
+	if w := b.LastSplitWarning(); !strings.Contains(w, "split 1 also starts at screenY 0") {
+		t.Fatalf("duplicate ScreenY should be reported, got %q", w)
+	}
+
+	// Sorting for a frame reports the splits still sharing a ScreenY
+	b.SetScreenSplit(3, 2, 0, 0, 0, 0, 0, 0)
+	if w := b.LastSplitWarning(); w != "" {
+		t.Fatalf("a valid split should clear the warning, got %q", w)
+	}
+	b.GetScreenSplitsSorted()
+	if w := b.LastSplitWarning(); w != "splits [1 2] all start at screenY 0; only one will be visible" {
+		t.Fatalf("GetScreenSplitsSorted should report the shared ScreenY, got %q", w)
+	}
+
+	// Without duplicates sorting leaves the diagnostic alone
+	b.SetScreenSplit(2, 1, 1, 0, 0, 0, 0, 0)
+	b.GetScreenSplitsSorted()
+	if w := b.LastSplitWarning(); w != "" {
+		t.Fatalf("GetScreenSplitsSorted set a warning: %q", w)
+	}
+}
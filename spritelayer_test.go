@@ -0,0 +1,128 @@
+package purfecterm
+
+import "testing"
+
+func spriteIDs(sprites []*Sprite) map[int]bool {
+	ids := make(map[int]bool)
+	for _, s := range sprites {
+		ids[s.ID] = true
+	}
+	return ids
+}
+
+// Hiding a layer drops its sprites from rendering; other layers remain.
+func TestSpriteLayerVisibility(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	b.SetSprite(1, 0, 0, 0, -1, 0, 1, 1, -1, []rune("@"))
+	b.SetSprite(2, 8, 0, 0, -1, 0, 1, 1, -1, []rune("H"))
+	b.SetSprite(3, 16, 0, -1, -1, 0, 1, 1, -1, []rune("#"))
+	b.SetSpriteLayer(2, 5)
+	b.SetSpriteLayer(3, 5)
+	b.SetSprite(2, 8, 8, 0, -1, 0, 1, 1, -1, []rune("H")) // an update keeps the layer
+
+	b.SetLayerVisible(5, false)
+	behind, front := b.GetSpritesForRendering()
+	if len(behind) != 0 {
+		t.Errorf("hidden layer sprite rendered behind text: %d sprites", len(behind))
+	}
+	if ids := spriteIDs(front); len(ids) != 1 || !ids[1] {
+		t.Errorf("front sprites = %v, want only 1", ids)
+	}
+	if b.IsLayerVisible(5) || !b.IsLayerVisible(0) {
+		t.Error("IsLayerVisible disagrees with SetLayerVisible")
+	}
+
+	b.SetLayerVisible(5, true)
+	behind, front = b.GetSpritesForRendering()
+	if len(behind) != 1 || len(front) != 2 {
+		t.Errorf("after showing: %d behind, %d front; want 1, 2", len(behind), len(front))
+	}
+
+	b.DeleteSpritesInLayer(5)
+	if b.GetSprite(2) != nil || b.GetSprite(3) != nil || b.GetSprite(1) == nil {
+		t.Error("DeleteSpritesInLayer removed the wrong sprites")
+	}
+}
+
+// Sprites start opaque; SetSpriteAlpha stores the opacity and updates keep it.
+func TestSpriteAlpha(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	b.SetSprite(1, 0, 0, 0, -1, 0, 1, 1, -1, []rune("@"))
+	if a := b.GetSprite(1).Opacity(); a != 1 {
+		t.Fatalf("new sprite opacity = %v, want 1", a)
+	}
+	if b.SetSpriteAlpha(9, 0.5) {
+		t.Error("SetSpriteAlpha on a missing sprite should report false")
+	}
+
+	b.SetSpriteAlpha(1, 0.25)
+	b.MoveSprite(1, 4, 4)
+	b.SetSprite(1, 8, 8, 0, -1, 0, 1, 1, -1, []rune("@"))
+	if a := b.GetSprite(1).Alpha; a != 0.25 {
+		t.Errorf("alpha after updates = %v, want 0.25", a)
+	}
+
+	b.SetSpriteAlpha(1, 1.5)
+	if a := b.GetSprite(1).Opacity(); a != 1 {
+		t.Errorf("opacity is clamped to 1, got %v", a)
+	}
+}
+
+func spriteOrder(sprites []*Sprite) []int {
+	ids := make([]int, len(sprites))
+	for i, s := range sprites {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+// Bring-to-front and send-to-back reorder a sprite within its side of the
+// text; a sprite behind the text stays behind it.
+func TestSpriteZOrder(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	for id, z := range map[int]int{1: 0, 2: 3, 3: 3, 4: -1, 5: -4} {
+		b.SetSprite(id, 0, 0, z, -1, 0, 1, 1, -1, []rune("@"))
+	}
+
+	b.BringSpriteToFront(1)
+	_, front := b.GetSpritesForRendering()
+	if got := spriteOrder(front); len(got) != 3 || got[2] != 1 {
+		t.Fatalf("front order after BringSpriteToFront(1) = %v, want 1 last", got)
+	}
+
+	b.SendSpriteToBack(3)
+	_, front = b.GetSpritesForRendering()
+	if got := spriteOrder(front); got[0] != 3 || got[2] != 1 {
+		t.Fatalf("front order after SendSpriteToBack(3) = %v, want 3 first, 1 last", got)
+	}
+
+	// With another sprite already at 0 there is no room: the others move up
+	b.SetSpriteZ(2, 0)
+	b.SendSpriteToBack(1)
+	_, front = b.GetSpritesForRendering()
+	if got := spriteOrder(front); got[0] != 1 || b.GetSprite(1).ZIndex != 0 {
+		t.Fatalf("front order after SendSpriteToBack(1) = %v (z %d), want 1 first at 0",
+			got, b.GetSprite(1).ZIndex)
+	}
+	if z := b.GetSprite(2).ZIndex; z != 1 {
+		t.Fatalf("sprite 2 should have moved up to 1, got %d", z)
+	}
+
+	b.BringSpriteToFront(5) // Highest behind Z is -1: stays behind the text
+	behind, _ := b.GetSpritesForRendering()
+	if got := spriteOrder(behind); len(got) != 2 || got[1] != 5 {
+		t.Fatalf("behind order after BringSpriteToFront(5) = %v, want 5 last", got)
+	}
+	if z := b.GetSprite(5).ZIndex; z >= 0 {
+		t.Fatalf("sprite 5 crossed in front of the text (z %d)", z)
+	}
+
+	b.SetSpriteZ(4, 10)
+	_, front = b.GetSpritesForRendering()
+	if got := spriteOrder(front); got[len(got)-1] != 4 {
+		t.Fatalf("SetSpriteZ(4, 10) order = %v, want 4 last", got)
+	}
+	if b.SetSpriteZ(9, 1) || b.BringSpriteToFront(9) || b.SendSpriteToBack(9) {
+		t.Error("z-order calls on a missing sprite should report false")
+	}
+}
@@ -0,0 +1,66 @@
+package purfecterm
+
+import (
+	"testing"
+	"time"
+)
+
+// A frame wrapped in ?2026h / ?2026l fires the dirty callback exactly once,
+// when the frame ends.
+func TestSynchronizedOutput(t *testing.T) {
+	b := NewBuffer(20, 3, 100)
+	p := NewParser(b)
+	calls := 0
+	b.SetDirtyCallback(func() { calls++ })
+
+	p.Parse([]byte("\x1b[?2026h\x1b[2J\x1b[Hframe\r\nline two"))
+	if calls != 0 {
+		t.Fatalf("dirty callback fired %d times during the frame", calls)
+	}
+	p.Parse([]byte("\x1b[?2026l"))
+	if calls != 1 {
+		t.Fatalf("dirty callback fired %d times, want 1 at frame end", calls)
+	}
+
+	// An unterminated frame stops holding redraws after the timeout
+	p.Parse([]byte("\x1b[?2026h"))
+	b.mu.Lock()
+	b.syncOutputStart = time.Now().Add(-2 * syncOutputTimeout)
+	b.mu.Unlock()
+	calls = 0
+	p.Parse([]byte("x"))
+	if calls == 0 || b.IsSynchronizedOutput() {
+		t.Fatal("a timed-out synchronized update should resume redraws")
+	}
+}
+
+// A program that starts a synchronized update and then sends nothing more
+// doesn't freeze the display: the update ends by itself after the timeout
+// and the held-back redraw fires.
+func TestSynchronizedOutputTimesOutWithoutOutput(t *testing.T) {
+	b := NewBuffer(20, 3, 100)
+	p := NewParser(b)
+	redrawn := make(chan struct{}, 1)
+	b.SetDirtyCallback(func() {
+		select {
+		case redrawn <- struct{}{}:
+		default:
+		}
+	})
+
+	p.Parse([]byte("\x1b[?2026hframe"))
+	select {
+	case <-redrawn:
+		t.Fatal("dirty callback fired during the frame")
+	default:
+	}
+
+	select {
+	case <-redrawn:
+	case <-time.After(4 * syncOutputTimeout):
+		t.Fatal("no redraw after the synchronized update timed out")
+	}
+	if b.IsSynchronizedOutput() {
+		t.Error("synchronized update still active after the timeout")
+	}
+}
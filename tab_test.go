@@ -0,0 +1,75 @@
+package purfecterm
+
+import "testing"
+
+// HT moves to the next 8-column stop, leaving the cells it crosses
+// untouched; with expand tabs on it fills them with spaces instead.
+func TestTabStops(t *testing.T) {
+	b := NewBuffer(20, 3, 0)
+	p := NewParser(b)
+
+	p.Parse([]byte("\x1b[41ma\tb\x1b[0m"))
+	if got := b.GetCell(8, 0).Char; got != 'b' {
+		t.Errorf("cell 8 = %q, want 'b'", got)
+	}
+	if x, _ := b.GetCursor(); x != 9 {
+		t.Errorf("cursor x = %d, want 9", x)
+	}
+	if bg := b.GetCell(1, 0).Background; bg == StandardColor(1) {
+		t.Error("cell 1 is red, want the tab to leave it unwritten")
+	}
+
+	// Byte-at-a-time and in one bulk write land the same
+	p.Parse([]byte("\r\n"))
+	for _, c := range []byte("ab\tc\td") {
+		p.Parse([]byte{c})
+	}
+	for x, want := range map[int]rune{8: 'c', 16: 'd'} {
+		if got := b.GetCell(x, 1).Char; got != want {
+			t.Errorf("row 1 cell %d = %q, want %q", x, got, want)
+		}
+	}
+
+	// Past the last stop the tab stops at the right margin
+	p.Parse([]byte("\t\tX"))
+	if got := b.GetCell(19, 1).Char; got != 'X' {
+		t.Errorf("cell 19 = %q, want 'X' at the margin", got)
+	}
+}
+
+func TestExpandTabs(t *testing.T) {
+	b := NewBuffer(20, 3, 0)
+	p := NewParser(b)
+	b.SetExpandTabs(true)
+
+	p.Parse([]byte("a\tb\x1b[41m\tc"))
+	line := b.GetLine(0)
+	if got := string(cellRunes(line[:17])); got != "a       b       c" {
+		t.Errorf("line = %q, want tabs expanded to spaces", got)
+	}
+	if x, _ := b.GetCursor(); x != 17 {
+		t.Errorf("cursor x = %d, want 17", x)
+	}
+	// The spaces carry the current attributes, like any written text
+	if bg := line[12].Background; bg != StandardColor(1) {
+		t.Errorf("expanded space background = %+v, want red", bg)
+	}
+
+	// A wide character counts as two columns toward the stop
+	p.Parse([]byte("\r\n中\tz"))
+	for x, c := range b.GetLine(1) {
+		if c.Char == 'z' {
+			if v := b.logicalToVisualLocked(1, x); v != 8 {
+				t.Errorf("'z' at column %d, want 8", v)
+			}
+		}
+	}
+}
+
+func cellRunes(cells []Cell) []rune {
+	out := make([]rune, len(cells))
+	for i, c := range cells {
+		out[i] = c.Char
+	}
+	return out
+}
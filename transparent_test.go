@@ -0,0 +1,44 @@
+package purfecterm
+
+import "testing"
+
+// With a transparent background, default-background cells are skipped so
+// an image behind the terminal shows, while a cell explicitly colored
+// like the scheme background is still filled.
+func TestTransparentBackgroundSkipFill(t *testing.T) {
+	b := NewBuffer(10, 2, 0)
+	p := NewParser(b)
+	scheme := DefaultColorScheme()
+	schemeBg := scheme.Background(true)
+	p.Parse([]byte("\x1b[" + TrueColor(schemeBg.R, schemeBg.G, schemeBg.B).ToSGRCode(false) + "mE\x1b[0mD\x1b[41mR"))
+
+	explicit, plain, red := b.GetCell(0, 0), b.GetCell(1, 0), b.GetCell(2, 0)
+	resolve := func(c Cell) Color { return scheme.ResolveColor(c.Background, false, true) }
+
+	// Opaque: anything showing the scheme background is already painted
+	if !b.SkipBackgroundFill(&plain, resolve(plain), schemeBg) {
+		t.Error("opaque: default-background cell should be skipped")
+	}
+	if !b.SkipBackgroundFill(&explicit, resolve(explicit), schemeBg) {
+		t.Error("opaque: cell colored like the scheme background should be skipped")
+	}
+
+	b.SetTransparentBackground(true)
+	if !b.IsTransparentBackground() {
+		t.Fatal("IsTransparentBackground = false after enabling it")
+	}
+	if !b.SkipBackgroundFill(&plain, resolve(plain), schemeBg) {
+		t.Error("transparent: default-background cell should be skipped")
+	}
+	if b.SkipBackgroundFill(&explicit, resolve(explicit), schemeBg) {
+		t.Error("transparent: explicitly colored cell should be filled")
+	}
+	if b.SkipBackgroundFill(&red, resolve(red), schemeBg) {
+		t.Error("transparent: red cell should be filled")
+	}
+
+	// A selected default cell is shaded, so it is filled too
+	if b.SkipBackgroundFill(&plain, scheme.SelectionBackground(resolve(plain)), schemeBg) {
+		t.Error("transparent: selected cell should be filled")
+	}
+}
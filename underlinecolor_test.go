@@ -0,0 +1,24 @@
+package purfecterm
+
+import "testing"
+
+// SGR 58 in either the colon or the semicolon form sets the underline color
+// independently of the style; SGR 59 resets it to follow the foreground.
+func TestSGRUnderlineColor(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	NewParser(b).Parse([]byte("\x1b[4:3m\x1b[58;2;255;0;0mA\x1b[58:5:4mB\x1b[59mC"))
+
+	a := b.GetCell(0, 0)
+	if a.UnderlineStyle != UnderlineCurly {
+		t.Fatalf("want curly underline, got %v", a.UnderlineStyle)
+	}
+	if !a.HasUnderlineColor || a.UnderlineColor != TrueColor(255, 0, 0) {
+		t.Fatalf("want red underline color, got %+v (set=%v)", a.UnderlineColor, a.HasUnderlineColor)
+	}
+	if c := b.GetCell(1, 0); !c.HasUnderlineColor || c.UnderlineColor != PaletteColor(4) {
+		t.Fatalf("want palette 4 underline color, got %+v", c.UnderlineColor)
+	}
+	if c := b.GetCell(2, 0); c.HasUnderlineColor || c.UnderlineStyle != UnderlineCurly {
+		t.Fatalf("SGR 59 should reset only the color, got %+v", c)
+	}
+}
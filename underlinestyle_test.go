@@ -0,0 +1,32 @@
+package purfecterm
+
+import "testing"
+
+// SGR 4 takes a colon sub-parameter selecting the style; legacy SGR 21
+// selects a double underline.
+func TestSGRUnderlineSubParams(t *testing.T) {
+	cases := []struct {
+		seq  string
+		want UnderlineStyle
+	}{
+		{"\x1b[4m", UnderlineSingle},
+		{"\x1b[4:0m", UnderlineNone},
+		{"\x1b[4:1m", UnderlineSingle},
+		{"\x1b[4:2m", UnderlineDouble},
+		{"\x1b[4:3m", UnderlineCurly},
+		{"\x1b[4:4m", UnderlineDotted},
+		{"\x1b[4:5m", UnderlineDashed},
+		{"\x1b[21m", UnderlineDouble},
+	}
+	for _, tc := range cases {
+		b := NewBuffer(10, 2, 100)
+		NewParser(b).Parse([]byte("\x1b[4:3m" + tc.seq + "A"))
+		c := b.GetCell(0, 0)
+		if c.UnderlineStyle != tc.want {
+			t.Errorf("%q: want style %v, got %v", tc.seq, tc.want, c.UnderlineStyle)
+		}
+		if c.Underline != (tc.want != UnderlineNone) {
+			t.Errorf("%q: legacy Underline flag out of sync", tc.seq)
+		}
+	}
+}
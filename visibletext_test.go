@@ -0,0 +1,69 @@
+package purfecterm
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// VisibleText follows the view: scrolled back, it shows the scrollback
+// lines on screen rather than the live screen.
+func TestVisibleTextScrolled(t *testing.T) {
+	b := NewBuffer(20, 4, 100)
+	p := NewParser(b)
+	for i := 0; i < 30; i++ {
+		if i > 0 {
+			p.Parse([]byte("\r\n"))
+		}
+		p.Parse([]byte("line " + strconv.Itoa(i)))
+	}
+	if got, want := b.VisibleText(), "line 26\nline 27\nline 28\nline 29"; got != want {
+		t.Fatalf("live VisibleText = %q, want %q", got, want)
+	}
+
+	// Scroll far enough back to clear the magnetic zone, then compare with
+	// the rows GetVisibleCell reports
+	b.SetScrollOffset(15)
+	got := b.VisibleText()
+	if got == "line 26\nline 27\nline 28\nline 29" {
+		t.Fatal("VisibleText did not follow the scroll offset")
+	}
+	var rows []string
+	for y := 0; y < 4; y++ {
+		var sb strings.Builder
+		for x := 0; x < 20; x++ {
+			if ch := b.GetVisibleCell(x, y).Char; ch != 0 {
+				sb.WriteRune(ch)
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		rows = append(rows, strings.TrimRight(sb.String(), " "))
+	}
+	if want := strings.Join(rows, "\n"); got != want {
+		t.Errorf("scrolled VisibleText = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(got, "line ") || strings.Contains(got, "line 29") {
+		t.Errorf("scrolled VisibleText = %q, want scrollback lines", got)
+	}
+}
+
+// The screen crop and doubled lines hide text that RenderPlain still shows
+func TestVisibleTextClipped(t *testing.T) {
+	b := NewBuffer(10, 3, 0)
+	p := NewParser(b)
+	p.Parse([]byte("0123456789\r\nabcdefghij\x1b#6\r\nklmnopqrst"))
+
+	if got, want := b.VisibleText(), "0123456789\nabcde\nklmnopqrst"; got != want {
+		t.Errorf("doubled line: VisibleText = %q, want %q", got, want)
+	}
+
+	unitX, unitY := b.GetSpriteUnits()
+	b.SetScreenCrop(6*unitX+1, 2*unitY) // Cell 6 partly shown, row 2 hidden
+	if got, want := b.VisibleText(), "0123456\nabcd"; got != want {
+		t.Errorf("cropped: VisibleText = %q, want %q", got, want)
+	}
+	if got := b.RenderPlain(); !strings.Contains(got, "klmnopqrst") {
+		t.Errorf("RenderPlain = %q, want the whole grid", got)
+	}
+}
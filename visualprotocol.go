@@ -50,15 +50,18 @@ func (b *Buffer) standardCharWidth(ch rune) float64 {
 
 // cellWidthAt returns the effective width of the cell at (row, x): its
 // CellWidth when set, else 1.0 (also 1.0 beyond the stored line — padding is
-// always narrow). Caller holds the lock.
+// always narrow). Each half of a wide-char pair (glyph + continuation) counts
+// as one column, so pairs address like the hardware cells they model.
+// Caller holds the lock.
 func (b *Buffer) cellWidthAt(row, x int) float64 {
 	if row < 0 || row >= len(b.screen) || x < 0 || x >= len(b.screen[row]) {
 		return 1.0
 	}
-	if w := b.screen[row][x].CellWidth; w > 0 {
-		return w
+	line := b.screen[row]
+	if line[x].Continuation || (x+1 < len(line) && line[x+1].Continuation) {
+		return 1.0
 	}
-	return 1.0
+	return line[x].VisualWidth()
 }
 
 // visualToLogicalLocked maps a visual column to the logical cell index whose
@@ -155,6 +158,7 @@ func (b *Buffer) MoveCursorForwardVisual(n int) {
 func (b *Buffer) MoveCursorBackwardVisual(n int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 	b.setHorizMoveDir(-1, false)
 	if b.flexWidthMode {
 		b.cursorX -= n
@@ -174,16 +178,25 @@ func (b *Buffer) MoveCursorBackwardVisual(n int) {
 func (b *Buffer) TabVisual() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.settleWrapPendingInternal()
 	b.setHorizMoveDir(1, false)
+	var target int
 	if b.flexWidthMode {
-		b.cursorX = ((b.cursorX / 8) + 1) * 8
+		target = ((b.cursorX / 8) + 1) * 8
 	} else {
 		v := b.logicalToVisualLocked(b.cursorY, b.cursorX)
-		b.cursorX = b.visualToLogicalLocked(b.cursorY, ((v/8)+1)*8)
+		target = b.visualToLogicalLocked(b.cursorY, ((v/8)+1)*8)
 	}
-	if max := b.EffectiveCols() - 1; b.cursorX >= max {
-		b.cursorX = max
+	if max := b.EffectiveCols() - 1; target >= max {
+		target = max
 	}
+	// Spaces are one column each, so an expanded tab writes as many as the
+	// visual columns it crosses (cells, under flex mode)
+	columns := target - b.cursorX
+	if !b.flexWidthMode {
+		columns = b.logicalToVisualLocked(b.cursorY, target) - b.logicalToVisualLocked(b.cursorY, b.cursorX)
+	}
+	b.tabToInternal(target, columns)
 	b.markDirty()
 }
 
@@ -253,3 +266,32 @@ func (b *Buffer) standardOverwriteFixup(row, x int, newW float64) {
 		b.screen[row] = line
 	}
 }
+
+// breakWidePairLocked splits a wide-char pair touching (row, x) so that x can
+// be overwritten on its own: if x is a continuation, its glyph cell becomes a
+// space; if x holds a wide glyph followed by a continuation, the continuation
+// becomes a space. Either way the row keeps its column geometry. Caller
+// holds the lock.
+func (b *Buffer) breakWidePairLocked(row, x int) {
+	if row < 0 || row >= len(b.screen) || x < 0 || x >= len(b.screen[row]) {
+		return
+	}
+	line := b.screen[row]
+	blank := func(i int) {
+		line[i].Char = ' '
+		line[i].Combining = ""
+		line[i].CellWidth = 1.0
+		line[i].Continuation = false
+	}
+	if line[x].Continuation {
+		blank(x)
+		if x > 0 && !line[x-1].Continuation {
+			blank(x - 1)
+		}
+		return
+	}
+	if x+1 < len(line) && line[x+1].Continuation {
+		blank(x)
+		blank(x + 1)
+	}
+}
@@ -0,0 +1,64 @@
+package purfecterm
+
+import "testing"
+
+// Wide-char mode stores a CJK glyph as two cells, advances the cursor two
+// columns, and deletes the pair together.
+func TestWideCharMode(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	b.SetWideCharMode(true)
+	NewParser(b).Parse([]byte("日a"))
+
+	if c := b.GetCell(0, 0); c.Char != '日' || c.CellWidth != 2.0 {
+		t.Fatalf("cell 0 = %q width %v, want wide 日", c.Char, c.CellWidth)
+	}
+	if c := b.GetCell(1, 0); !c.Continuation {
+		t.Fatalf("cell 1 should be a continuation, got %+v", c)
+	}
+	if c := b.GetCell(2, 0); c.Char != 'a' {
+		t.Fatalf("cell 2 = %q, want 'a'", c.Char)
+	}
+	if x, _ := b.GetCursor(); x != 3 {
+		t.Fatalf("cursor x = %d, want 3", x)
+	}
+	if got := b.RenderPlain(); got != "日a\n" {
+		t.Fatalf("RenderPlain = %q", got)
+	}
+
+	// Overwriting the continuation breaks the pair without shifting 'a'
+	NewParser(b).Parse([]byte("\x1b[1;2Hx"))
+	if got := b.RenderPlain(); got != " xa\n" {
+		t.Fatalf("after overwrite RenderPlain = %q", got)
+	}
+
+	// DCH on a continuation removes the whole pair
+	b2 := NewBuffer(10, 2, 100)
+	b2.SetWideCharMode(true)
+	NewParser(b2).Parse([]byte("日a"))
+	b2.SetCursor(1, 0)
+	b2.DeleteChars(1)
+	if got := b2.RenderPlain(); got != "a\n" {
+		t.Fatalf("after DCH RenderPlain = %q", got)
+	}
+}
+
+// Renderers skip continuation cells, so a cursor moved onto one is shown
+// on the wide glyph rather than vanishing.
+func TestWideCharCursorOnContinuation(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	b.SetWideCharMode(true)
+	p := NewParser(b)
+	p.Parse([]byte("日a\x1b[1;2H"))
+
+	if x, _ := b.GetCursor(); x != 1 {
+		t.Fatalf("cursor x = %d, want 1 (CUP is not snapped)", x)
+	}
+	if x, y := b.GetCursorVisiblePosition(); x != 0 || y != 0 {
+		t.Errorf("visible cursor %d,%d, want 0,0 on the glyph", x, y)
+	}
+
+	p.Parse([]byte("\x1b[1;3H"))
+	if x, _ := b.GetCursorVisiblePosition(); x != 2 {
+		t.Errorf("visible cursor on 'a' at x=%d, want 2", x)
+	}
+}
@@ -0,0 +1,57 @@
+package purfecterm
+
+import (
+	"strings"
+	"testing"
+)
+
+// Smart word wrap breaks after an emdash by default, and after whatever
+// SetWordWrapBoundaries names once set.
+func TestWordWrapBoundaries(t *testing.T) {
+	b := NewBuffer(10, 3, 0)
+	NewParser(b).Parse([]byte("abcd—efghij"))
+	if got := b.RenderPlain(); !strings.HasPrefix(got, "abcd—\nefghij\n") {
+		t.Errorf("screen = %q, want the break after the emdash", got)
+	}
+
+	b = NewBuffer(10, 4, 0)
+	p := NewParser(b)
+	b.SetWordWrapBoundaries([]rune{' ', '/'})
+	p.Parse([]byte("usr/local/bin"))
+	if got := b.RenderPlain(); !strings.HasPrefix(got, "usr/local/\nbin\n") {
+		t.Errorf("screen = %q, want the break after the last slash", got)
+	}
+
+	// The custom set replaces the default: hyphens no longer break
+	p.Parse([]byte("\r\nabcd-efghij"))
+	if got := strings.Split(b.RenderPlain(), "\n")[2]; got != "abcd-efghi" {
+		t.Errorf("row 2 = %q, want a plain wrap ignoring the hyphen", got)
+	}
+
+	b.SetWordWrapBoundaries(nil)
+	if got := b.GetWordWrapBoundaries(); string(got) != string(defaultWordWrapBoundaries) {
+		t.Errorf("after reset: %q, want the default set", string(got))
+	}
+}
+
+// A smart wrap splits the wrapped line wherever the index left it: one row
+// up when the scroll region scrolled, and not at all when the cursor sits on
+// the last row below the region, where the line above must stay untouched.
+func TestWordWrapWithScrollRegion(t *testing.T) {
+	b := NewBuffer(10, 5, 0)
+	p := NewParser(b)
+	p.Parse([]byte("\x1b[4;1Habove\x1b[1;3r\x1b[3;1Hhello world"))
+	rows := strings.Split(b.RenderPlain(), "\n")
+	if rows[1] != "hello" || rows[2] != "world" {
+		t.Errorf("region rows = %q, want the break after the space", rows[:3])
+	}
+
+	p.Parse([]byte("\x1b[5;1Hhello world"))
+	rows = strings.Split(b.RenderPlain(), "\n")
+	if rows[3] != "above" {
+		t.Errorf("row 3 = %q, want it left alone", rows[3])
+	}
+	if rows[4] != "dello worl" {
+		t.Errorf("row 4 = %q, want the last row overwritten in place", rows[4])
+	}
+}
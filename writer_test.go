@@ -0,0 +1,31 @@
+package purfecterm
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+)
+
+var _ io.Writer = (*Parser)(nil)
+
+// A Parser can be a command's stdout.
+func TestParserAsCommandStdout(t *testing.T) {
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Skip("echo not available")
+	}
+	b := NewBuffer(20, 3, 10)
+	cmd := exec.Command(echo, "hello")
+	cmd.Stdout = NewParser(b)
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ""
+	for x := 0; x < 5; x++ {
+		got += string(b.GetCell(x, 0).Char)
+	}
+	if got != "hello" {
+		t.Fatalf("buffer row 0 = %q, want %q", got, "hello")
+	}
+}
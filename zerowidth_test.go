@@ -0,0 +1,26 @@
+package purfecterm
+
+import "testing"
+
+// VS16 and ZWJ attach to the previous cell instead of taking cells of their own.
+func TestZeroWidthAttach(t *testing.T) {
+	b := NewBuffer(10, 2, 100)
+	NewParser(b).Parse([]byte("👍\uFE0Fx"))
+
+	c := b.GetCell(0, 0)
+	if c.Char != '👍' || c.Combining != "\uFE0F" {
+		t.Fatalf("cell 0 = %q + %q, want thumbs up with VS16", c.Char, c.Combining)
+	}
+	if got := b.GetCell(1, 0).Char; got != 'x' {
+		t.Fatalf("cell 1 = %q, want 'x'", got)
+	}
+
+	// A ZWJ sequence stays in one cell; in wide-char mode it skips the continuation
+	b2 := NewBuffer(10, 2, 100)
+	b2.SetWideCharMode(true)
+	NewParser(b2).Parse([]byte("👩\u200D💻"))
+	c2 := b2.GetCell(0, 0)
+	if got := c2.String(); got != "👩\u200D" {
+		t.Fatalf("ZWJ should attach to the glyph cell, got %q", got)
+	}
+}